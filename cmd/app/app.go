@@ -2,10 +2,13 @@ package app
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 
 	"github.com/Megidy/rarible/internal/client"
+	"github.com/Megidy/rarible/internal/client/fake"
+	"github.com/Megidy/rarible/internal/client/replay"
 	"github.com/Megidy/rarible/internal/config"
 	"github.com/Megidy/rarible/internal/handler"
 	"github.com/Megidy/rarible/internal/service"
@@ -14,7 +17,10 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-const baseRaribleURL = "https://api.rarible.org/v0.1"
+const (
+	baseRaribleURL = "https://api.rarible.org/v0.1"
+	fakeApiKey     = "fake-api-key"
+)
 
 type App interface {
 	Run() <-chan error
@@ -22,7 +28,8 @@ type App interface {
 }
 
 type app struct {
-	httpServer *httpserver.HttpServer
+	httpServer   *httpserver.HttpServer
+	fakeUpstream *fake.Server
 }
 
 func NewApp() (App, error) {
@@ -44,20 +51,189 @@ func NewApp() (App, error) {
 	}
 	httpServer := httpserver.NewHttpServer(port)
 
-	raribleClient := client.NewRaribleClient(cfg.RaribleApiKey, baseRaribleURL)
+	mainnetURL, mainnetKeys := baseRaribleURL, cfg.ApiKeys()
+	var fakeUpstream *fake.Server
+	if cfg.FakeUpstream {
+		fakeUpstream = fake.NewServer(fake.DefaultDataset())
+		mainnetURL, err = fakeUpstream.Start()
+		if err != nil {
+			return nil, fmt.Errorf("failed to start fake upstream: %w", err)
+		}
+		if len(mainnetKeys) == 0 {
+			mainnetKeys = []string{fakeApiKey}
+		}
+		log.Warn().Str("url", mainnetURL).Msg("Serving mainnet from fake upstream")
+	}
 
-	nftService := service.NewNFTService(raribleClient)
+	// fault injection is never wired in production, so it cannot be enabled there
+	var faultInjector *client.FaultInjector
+	if !cfg.IsProduction() {
+		faultInjector = client.NewFaultInjector()
+	}
+
+	monitors := newUpstreamMonitors()
+	raribleClient, err := newRaribleClient(cfg, mainnetURL, mainnetKeys, faultInjector, monitors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rarible client: %w", err)
+	}
+
+	traitNormalizer, err := cfg.TraitNormalizer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trait normalization rules: %w", err)
+	}
+
+	nftService := service.NewNFTService(raribleClient,
+		service.WithRarityTiers(cfg.RarityTiers()),
+		service.WithMaxCollectionItems(cfg.RarityMaxCollectionItems),
+		service.WithTraitNormalizer(traitNormalizer),
+		service.WithTraitStatsCacheTTL(cfg.TraitStatsCacheTTL),
+	)
 
 	nftHandler := handler.NewNFTHandler(nftService)
 
-	router := handler.NewRouter(httpServer.Echo, nftHandler)
+	adminHandler := handler.NewAdminHandler(faultInjector,
+		handler.WithKeyPools(monitors.keyPools),
+		handler.WithShadowClient(monitors.shadow),
+		handler.WithDriftDetectors(monitors.drift),
+	)
+
+	callerTenants, err := newCallerTenants(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load caller tenants: %w", err)
+	}
+
+	routerOpts := []handler.RouterOption{
+		handler.WithCallerAPIKey(callerTenants),
+		handler.WithEnvironments(raribleClient),
+		handler.WithAdminHandler(adminHandler, cfg.AdminToken),
+	}
+
+	router := handler.NewRouter(httpServer.Echo, nftHandler, routerOpts...)
 	router.RegisterRoutes()
 
 	return &app{
-		httpServer: httpServer,
+		httpServer:   httpServer,
+		fakeUpstream: fakeUpstream,
 	}, nil
 }
 
+// newCallerTenants returns the tenants allowed to supply their own Rarible API key, none unless enabled
+func newCallerTenants(cfg *config.Config) ([]handler.CallerTenant, error) {
+	if !cfg.AllowCallerApiKey {
+		return nil, nil
+	}
+	tenants, err := cfg.CallerTenants()
+	if err != nil {
+		return nil, err
+	}
+
+	callerTenants := make([]handler.CallerTenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		callerTenants = append(callerTenants, handler.CallerTenant{
+			Token:  tenant.Token,
+			APIKey: tenant.ApiKey,
+		})
+	}
+	return callerTenants, nil
+}
+
+// upstreamMonitors keeps the per-environment client components whose counters
+// are exposed on the admin routes
+type upstreamMonitors struct {
+	keyPools map[string]*client.KeyPool
+	shadow   *client.ShadowClient
+	drift    map[string]*client.DriftDetector
+}
+
+func newUpstreamMonitors() *upstreamMonitors {
+	return &upstreamMonitors{
+		keyPools: make(map[string]*client.KeyPool),
+		drift:    make(map[string]*client.DriftDetector),
+	}
+}
+
+// newRaribleClient builds one client per upstream environment. Mainnet always
+// exists, uses the global keys and optionally mirrors reads to a shadow
+// upstream; other environments fall back to the global keys when they have
+// no keys of their own.
+func newRaribleClient(cfg *config.Config, mainnetURL string, mainnetKeys []string, faultInjector *client.FaultInjector, monitors *upstreamMonitors) (*client.EnvironmentRouter, error) {
+	environments, err := cfg.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	mainnet, err := newEnvironmentClient(cfg, config.MainnetEnvironment, mainnetURL, mainnetKeys, faultInjector, monitors)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ShadowBaseURL != "" {
+		shadow, err := newEnvironmentClient(cfg, config.ShadowEnvironment, cfg.ShadowBaseURL, mainnetKeys, nil, monitors)
+		if err != nil {
+			return nil, err
+		}
+		monitors.shadow = client.NewShadowClient(mainnet, shadow, cfg.ShadowSampleRate)
+		mainnet = monitors.shadow
+	}
+
+	clients := map[string]client.RaribleClient{
+		config.MainnetEnvironment: mainnet,
+	}
+	for _, environment := range environments {
+		keys := environment.ApiKeys
+		if len(keys) == 0 {
+			keys = mainnetKeys
+		}
+		clients[environment.Name], err = newEnvironmentClient(cfg, environment.Name, environment.BaseURL, keys, faultInjector, monitors)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return client.NewEnvironmentRouter(cfg.RaribleDefaultEnvironment, clients)
+}
+
+func newEnvironmentClient(cfg *config.Config, name, baseURL string, keys []string, faultInjector *client.FaultInjector, monitors *upstreamMonitors) (client.RaribleClient, error) {
+	keyPool := client.NewKeyPool(keys, client.KeySelection(cfg.RaribleApiKeySelection), cfg.RaribleApiKeyBenchDuration)
+	monitors.keyPools[name] = keyPool
+
+	opts := []client.Option{
+		client.WithConcurrencyLimit(cfg.RaribleMaxConcurrentRequests, cfg.RaribleInteractiveReserved),
+		client.WithKeyPool(keyPool),
+	}
+	if cfg.StrictDecoding {
+		detector := client.NewDriftDetector(cfg.StrictDecodingFailOnDrift)
+		monitors.drift[name] = detector
+		opts = append(opts, client.WithDriftDetector(detector))
+	}
+
+	transport, err := newUpstreamTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if faultInjector != nil {
+		transport = faultInjector.Wrap(transport)
+	}
+	if transport != nil {
+		opts = append(opts, client.WithTransport(transport))
+	}
+
+	return client.NewRaribleClient(keys[0], baseURL, opts...), nil
+}
+
+// newUpstreamTransport returns the record/replay transport selected in config, or nil for the default one
+func newUpstreamTransport(cfg *config.Config) (http.RoundTripper, error) {
+	switch replay.Mode(cfg.UpstreamReplayMode) {
+	case "":
+		return nil, nil
+	case replay.ModeRecord:
+		return replay.NewRecorder(cfg.UpstreamFixturesDir, nil)
+	case replay.ModeReplay:
+		return replay.NewReplayer(cfg.UpstreamFixturesDir, nil)
+	default:
+		return nil, fmt.Errorf("unknown upstream replay mode %q", cfg.UpstreamReplayMode)
+	}
+}
+
 func (a *app) Run() <-chan error {
 	errsCh := make(chan error, 1)
 	var wg = sync.WaitGroup{}
@@ -85,5 +261,12 @@ func (a *app) Shutdown() error {
 	}
 
 	log.Info().Msgf("Successfully shut down HTTP server")
+
+	if a.fakeUpstream != nil {
+		err = a.fakeUpstream.Close()
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,83 @@
+// Command rarity ranks the tokens of a zip, tar or tar.gz of ERC-721 metadata JSON files
+// offline, without calling Rarible, and prints the rankings and trait statistics as JSON.
+//
+//	go run ./cmd/rarity -case-fold -trim-space metadata.zip
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+	"github.com/Megidy/rarible/internal/service"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "rarity:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		req           model.MetadataRarityRequestDTO
+		missingTraits = flag.Bool("missing-traits", true, "score traits a token lacks as the value none")
+		traitCount    = flag.Bool("trait-count", true, "score the number of traits a token has as a meta-trait")
+		longTail      = flag.Float64("long-tail", model.DefaultLongTailRarity, "rarity, in percent, at or below which a value is long tail")
+		maxTokens     = flag.Int("max-tokens", 10000, "largest number of metadata files read")
+		unicodeForm   = flag.String("unicode-form", "", "NFC, NFD, NFKC or NFKD to normalise traits to")
+		trimSpace     = flag.Bool("trim-space", false, "trim traits and collapse inner whitespace")
+		caseFold      = flag.Bool("case-fold", false, "fold traits to lower case")
+		ignoredKeys   = flag.String("ignore", "", "comma separated trait keys left out")
+	)
+	flag.StringVar(&req.Method, "method", string(rarity.MethodInformationContent), "scoring method: information_content, rarity_score or statistical")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] metadata.zip|metadata.tar|metadata.tar.gz\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if !rarity.Method(req.Method).Valid() {
+		return fmt.Errorf("unknown method %q", req.Method)
+	}
+	req.MissingTraits, req.TraitCount, req.LongTail = missingTraits, traitCount, longTail
+
+	rules := rarity.NormalizationRules{UnicodeForm: *unicodeForm, TrimSpace: *trimSpace, CaseFold: *caseFold}
+	for _, key := range strings.Split(*ignoredKeys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			rules.IgnoredKeys = append(rules.IgnoredKeys, key)
+		}
+	}
+	normalizer, err := rarity.NewNormalizer(rules)
+	if err != nil {
+		return err
+	}
+
+	req.Archive, err = os.ReadFile(flag.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	// ranking metadata never calls upstream, so no client is needed
+	nftService := service.NewNFTService(nil,
+		service.WithMaxCollectionItems(*maxTokens),
+		service.WithTraitNormalizer(normalizer),
+	)
+	resp, err := nftService.GetMetadataRarity(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(resp)
+}
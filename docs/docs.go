@@ -15,9 +15,54 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/ownerships/{id}": {
+        "/admin/faults": {
             "get": {
-                "description": "Retrieves ownership details for a specific NFT by its ID",
+                "description": "Returns the faults currently injected into upstream Rarible calls. Only served outside production, under /admin rather than /v1.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get injected upstream faults",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token set in ADMIN_TOKEN",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved fault config",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/client.FaultConfig"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the faults injected into upstream Rarible calls. Only served outside production, under /admin rather than /v1.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,22 +70,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "NFT"
+                    "Admin"
                 ],
-                "summary": "Get NFT ownership information",
+                "summary": "Set injected upstream faults",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "0x06012c8cf97bead5deae237070f9587f8e7a266d:123456",
-                        "description": "NFT ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Admin token set in ADMIN_TOKEN",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Fault injection config",
+                        "name": "faults",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/client.FaultConfig"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved ownership data",
+                        "description": "Successfully updated fault config",
                         "schema": {
                             "allOf": [
                                 {
@@ -50,7 +103,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.OwnershipDTO"
+                                            "$ref": "#/definitions/client.FaultConfig"
                                         }
                                     }
                                 }
@@ -58,13 +111,226 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request parameters",
+                        "description": "Invalid fault config",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/upstream/drift": {
+            "get": {
+                "description": "Returns the drift counters and distinct drifted fields per environment and upstream endpoint. Served under /admin rather than /v1.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get upstream schema drift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token set in ADMIN_TOKEN",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved schema drift",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "additionalProperties": {
+                                                "$ref": "#/definitions/client.DriftSummary"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
                         "schema": {
                             "$ref": "#/definitions/dto.GeneralResponse"
                         }
                     },
                     "404": {
-                        "description": "NFT ownership not found",
+                        "description": "Strict decoding is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/upstream/keys": {
+            "get": {
+                "description": "Returns request and failure counts and bench state of every pooled Rarible API key per environment. Keys are identified by fingerprint only. Served under /admin rather than /v1.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get upstream API key usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token set in ADMIN_TOKEN",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved key usage",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "additionalProperties": {
+                                                "type": "array",
+                                                "items": {
+                                                    "$ref": "#/definitions/client.KeyStats"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/upstream/shadow": {
+            "get": {
+                "description": "Returns how many mirrored mainnet reads matched the shadow upstream, with the most recent mismatch and failure reports. Served under /admin rather than /v1.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get shadow upstream diffs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token set in ADMIN_TOKEN",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved shadow diffs",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/client.ShadowSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shadow traffic is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/openrarity": {
+            "get": {
+                "description": "Ranks a collection as OpenRarity does, tokens with more unique attributes first and then by information content over every trait, missing traits and the trait count included, and returns the ranking as an OpenRarity-compatible JSON file",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Export a collection's rarity ranking in OpenRarity format",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6",
+                        "description": "Collection ID, or its URL-encoded CAIP-19 form",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OpenRarity ranking of the collection",
+                        "schema": {
+                            "$ref": "#/definitions/model.OpenRarityRankingsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid collection id or collection too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found or empty",
                         "schema": {
                             "$ref": "#/definitions/dto.GeneralResponse"
                         }
@@ -74,13 +340,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.GeneralResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
                     }
                 }
             }
         },
-        "/trait-rarities": {
+        "/collections/{id}/openrarity/diff": {
             "post": {
-                "description": "returns rarity scores for specified traits in an NFT collection",
+                "description": "Loads an external ranking of a collection in OpenRarity format and reports, token by token, where its ranks differ from the ranking computed here, along with the tokens only one side has",
                 "consumes": [
                     "application/json"
                 ],
@@ -90,21 +362,35 @@ const docTemplate = `{
                 "tags": [
                     "NFT"
                 ],
-                "summary": "Get trait rarities for NFTs",
+                "summary": "Compare an OpenRarity ranking with ours",
                 "parameters": [
                     {
-                        "description": "Trait rarity request parameters",
+                        "type": "string",
+                        "example": "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6",
+                        "description": "Collection ID, or its URL-encoded CAIP-19 form",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "OpenRarity ranking of the collection",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.TraitRarityRequestDTO"
+                            "$ref": "#/definitions/model.OpenRarityRankingsDTO"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully calculated trait rarities",
+                        "description": "Successfully compared the rankings",
                         "schema": {
                             "allOf": [
                                 {
@@ -114,7 +400,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.TraitRarityResponseDTO"
+                                            "$ref": "#/definitions/model.OpenRarityDiffDTO"
                                         }
                                     }
                                 }
@@ -122,13 +408,19 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or parameters",
+                        "description": "Invalid ranking file or collection too large",
                         "schema": {
                             "$ref": "#/definitions/dto.GeneralResponse"
                         }
                     },
                     "404": {
-                        "description": "Collection or traits not found",
+                        "description": "Collection not found or empty",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Ranking file too large",
                         "schema": {
                             "$ref": "#/definitions/dto.GeneralResponse"
                         }
@@ -138,113 +430,2375 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.GeneralResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/rarity-rankings": {
+            "get": {
+                "description": "Fetches every item of a collection and scores it from its traits with information content (OpenRarity), rarity score sum and statistical rarity, returning a page of items rarest first. Traits an item lacks and its trait count are scored as extra traits unless disabled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Rank the items of a collection by rarity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6",
+                        "description": "Collection ID, or its URL-encoded CAIP-19 form",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "information_content",
+                            "rarity_score",
+                            "statistical"
+                        ],
+                        "type": "string",
+                        "description": "Scoring method items are ranked by, defaults to information_content",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of ranked items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of ranked items to return, defaults to 100, at most 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Score traits an item lacks as the value none, defaults to true",
+                        "name": "missingTraits",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Score the number of traits an item has as a meta-trait, defaults to true",
+                        "name": "traitCount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trait filter the listed items must match, e.g. Hat = Halo AND NOT Eyes IN (Red, Laser); ranks stay collection wide. Bucketed traits only compare with =, != or IN",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully ranked the collection",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.RarityRankingsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters or collection too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found or empty",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/trait-combinations": {
+            "get": {
+                "description": "Counts how many items of a collection have each pair, or triple, of traits and returns the rarest combinations with their counts. With a filter only the matching items are counted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Find the rarest trait combinations of a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6",
+                        "description": "Collection ID, or its URL-encoded CAIP-19 form",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            2,
+                            3
+                        ],
+                        "type": "integer",
+                        "description": "Number of traits combined, defaults to 2",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of combinations to return, defaults to 20, at most 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trait filter the counted items must match, e.g. Hat = Halo AND NOT Eyes IN (Red, Laser). Bucketed traits only compare with =, != or IN",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully counted trait combinations",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.TraitCombinationsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters or collection too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found or empty",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/trait-stats": {
+            "get": {
+                "description": "Describes how a collection's trait keys and values are distributed: values per key, entropy, items missing each key, long-tail values and a histogram of values by rarity tier. With a filter only the matching items are described. Stats are cached per collection and filter for a while. With format=csv one row per key and value is returned instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Get the trait distribution of a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6",
+                        "description": "Collection ID, or its URL-encoded CAIP-19 form",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, defaults to json",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Rarity, in percent, at or below which a value is long tail, defaults to 1",
+                        "name": "longTail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trait filter the described items must match, e.g. Hat = Halo AND NOT Eyes IN (Red, Laser). Bucketed traits only compare with =, != or IN",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully computed trait stats",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.TraitStatsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters or collection too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found or empty",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/rarity": {
+            "get": {
+                "description": "Scores an item within its collection and breaks the score down by trait, showing each trait's frequency, its contribution to the score and its share of it, most impactful first, alongside the item's collection rank and percentile and Rarible's own rarity of each trait",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Explain an item's rarity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6:1",
+                        "description": "Item ID, or its URL-encoded CAIP-19 form",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "information_content",
+                            "rarity_score",
+                            "statistical"
+                        ],
+                        "type": "string",
+                        "description": "Scoring method the breakdown is given for, defaults to information_content",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Score traits an item lacks as the value none, defaults to true",
+                        "name": "missingTraits",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Score the number of traits an item has as a meta-trait, defaults to true",
+                        "name": "traitCount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully explained the item's rarity",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ItemRarityBreakdownDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters or collection too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/similar": {
+            "get": {
+                "description": "Ranks the other items of an item's collection by how alike their traits are, by Jaccard similarity or by overlap weighted by trait rarity, and returns the closest matches with the traits they share and the ones that differ",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Find items similar to an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6:1",
+                        "description": "Item ID, or its URL-encoded CAIP-19 form",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "jaccard",
+                            "weighted"
+                        ],
+                        "type": "string",
+                        "description": "Similarity measure, defaults to weighted",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Trait keys left out of the comparison, repeated or comma separated",
+                        "name": "exclude",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of similar items to return, defaults to 10, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trait filter the similar items must match, e.g. Hat = Halo AND NOT Eyes IN (Red, Laser). Bucketed traits only compare with =, != or IN",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully found similar items",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SimilarItemsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters or collection too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/trait-rarities": {
+            "get": {
+                "description": "Fetches an item's attributes, derives its collection and returns the attributes annotated with their rarity, rank and tier. Items with unrevealed metadata or without attributes are returned with a status explaining why their traits are empty.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Get the trait rarities of an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6:1",
+                        "description": "Item ID, or its URL-encoded CAIP-19 form",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "request",
+                            "rarity",
+                            "key"
+                        ],
+                        "type": "string",
+                        "description": "Sort traits by attribute order, rarity or key",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, rarity ascending lists the rarest first",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only return traits with at least this rarity, in percent",
+                        "name": "minRarity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only return traits with at most this rarity, in percent",
+                        "name": "maxRarity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "legendary",
+                                "epic",
+                                "rare",
+                                "uncommon",
+                                "common"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only return traits in these tiers",
+                        "name": "tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully annotated the item's traits",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ItemTraitRarityResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metadata/rarity": {
+            "post": {
+                "description": "Reads a zip, tar or tar.gz of ERC-721 metadata JSON files named by token id, takes each token's traits from its OpenSea attributes, normalises them and ranks the tokens with the rarity engine, along with trait statistics. Nothing is fetched upstream, so collections can be ranked before they are revealed. The archive is the request body or the archive field of a multipart form.",
+                "consumes": [
+                    "application/zip",
+                    "application/gzip",
+                    "application/x-tar",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Rank tokens from uploaded metadata files",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Metadata archive, when uploaded as a multipart form",
+                        "name": "archive",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6",
+                        "description": "Collection whose trait normalisation rules apply",
+                        "name": "collection",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "information_content",
+                            "rarity_score",
+                            "statistical"
+                        ],
+                        "type": "string",
+                        "description": "Scoring method tokens are ranked by, defaults to information_content",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Score traits a token lacks as the value none, defaults to true",
+                        "name": "missingTraits",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Score the number of traits a token has as a meta-trait, defaults to true",
+                        "name": "traitCount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Rarity, in percent, at or below which a value is long tail, defaults to 1",
+                        "name": "longTail",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully ranked the tokens",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.MetadataRarityResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters, unreadable archive or too many tokens",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Archive too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ownerships/{blockchain}/{contract}/{tokenId}/{owner}": {
+            "get": {
+                "description": "Builds the ownership ID from separate blockchain, contract, token and owner segments, validates it and retrieves the ownership details.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Get NFT ownership information by its segments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ETHEREUM",
+                        "description": "Blockchain name or CAIP-2 chain ID",
+                        "name": "blockchain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "0x06012c8cf97bead5deae237070f9587f8e7a266d",
+                        "description": "Contract address",
+                        "name": "contract",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "123456",
+                        "description": "Token ID",
+                        "name": "tokenId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10",
+                        "description": "Owner address",
+                        "name": "owner",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include CAIP-2, CAIP-19 and CAIP-10 IDs alongside Rarible IDs",
+                        "name": "caip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved ownership data",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.OwnershipDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "NFT ownership not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ownerships/{blockchain}/{mint}/{owner}": {
+            "get": {
+                "description": "Builds the ownership ID of an item without token ID, such as a Solana mint, from separate blockchain, mint and owner segments, validates it and retrieves the ownership details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Get Solana NFT ownership information by its segments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "SOLANA",
+                        "description": "Blockchain name or CAIP-2 chain ID",
+                        "name": "blockchain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU",
+                        "description": "Mint address",
+                        "name": "mint",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM",
+                        "description": "Owner address",
+                        "name": "owner",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include CAIP-2, CAIP-19 and CAIP-10 IDs alongside Rarible IDs",
+                        "name": "caip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved ownership data",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.OwnershipDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "NFT ownership not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ownerships/{id}": {
+            "get": {
+                "description": "Retrieves ownership details for a specific NFT by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Get NFT ownership information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:123456:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10",
+                        "description": "Ownership ID as BLOCKCHAIN:contract:tokenId:owner, or a URL-encoded CAIP-19 asset ID followed by /owner",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include CAIP-2, CAIP-19 and CAIP-10 IDs alongside Rarible IDs",
+                        "name": "caip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved ownership data",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.OwnershipDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "NFT ownership not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/resolve": {
+            "get": {
+                "description": "Resolves a Rarible, OpenSea, Etherscan-family or Magic Eden URL, a CAIP-19 asset ID or a Rarible ID into normalised collection, item and ownership IDs, optionally fetching the ownership or item in the same call. A holder named without an item, as in an Etherscan token holder view, is returned as owner",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Resolve a marketplace URL into Rarible identifiers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "https://opensea.io/assets/ethereum/0x06012c8cf97bead5deae237070f9587f8e7a266d/123456",
+                        "description": "URL, CAIP-19 asset ID or Rarible ID",
+                        "name": "input",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner address, narrows an item down to its ownership",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fetch the resolved ownership, or the item when no owner is known",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include CAIP-2, CAIP-19 and CAIP-10 IDs alongside Rarible IDs",
+                        "name": "caip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully resolved identifiers",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ResolveResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Input not recognised or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Resolved item or ownership not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trait-combinations/rarity": {
+            "post": {
+                "description": "Counts how many items of a collection have every given trait and compares it with the rarity the combination would have if its traits occurred independently",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Get the rarity of a trait combination",
+                "parameters": [
+                    {
+                        "description": "Collection and the traits combined",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TraitRarityRequestDTO"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully calculated the combination's rarity",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.TraitCombinationRarityDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or collection too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found or empty",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trait-rarities": {
+            "post": {
+                "description": "returns rarity scores for specified traits in an NFT collection, ranked within the request and labelled with a rarity tier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Get trait rarities for NFTs",
+                "parameters": [
+                    {
+                        "description": "Trait rarity request parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TraitRarityRequestDTO"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "request",
+                            "rarity",
+                            "key"
+                        ],
+                        "type": "string",
+                        "description": "Sort traits by request order, rarity or key",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, rarity ascending lists the rarest first",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only return traits with at least this rarity, in percent",
+                        "name": "minRarity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only return traits with at most this rarity, in percent",
+                        "name": "maxRarity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "legendary",
+                                "epic",
+                                "rare",
+                                "uncommon",
+                                "common"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only return traits in these tiers",
+                        "name": "tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream environment, defaults to mainnet",
+                        "name": "X-Rarible-Environment",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully calculated trait rarities",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.GeneralResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.TraitRarityResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection or traits not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Upstream capacity exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneralResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "client.DriftIssue": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/client.DriftKind"
+                }
+            }
+        },
+        "client.DriftKind": {
+            "type": "string",
+            "enum": [
+                "unknown_field",
+                "missing_field",
+                "type_mismatch"
+            ],
+            "x-enum-varnames": [
+                "DriftUnknownField",
+                "DriftMissingField",
+                "DriftTypeMismatch"
+            ]
+        },
+        "client.DriftSummary": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "integer",
+                            "format": "int64"
+                        }
+                    }
+                },
+                "issues": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/client.DriftIssue"
+                        }
+                    }
+                }
+            }
+        },
+        "client.FaultConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/client.FaultRule"
+                    }
+                }
+            }
+        },
+        "client.FaultRule": {
+            "type": "object",
+            "properties": {
+                "dropProbability": {
+                    "description": "DropProbability is the chance of failing the call as if the connection dropped",
+                    "type": "number"
+                },
+                "endpoint": {
+                    "description": "Endpoint is the upstream endpoint the rule applies to, e.g. \"ownerships\" or \"items/byCollection\",\nempty for every endpoint. It matches the end of the path, optionally followed by a single id.",
+                    "type": "string"
+                },
+                "errorProbability": {
+                    "description": "ErrorProbability is the chance of answering with ErrorStatusCode instead of calling upstream",
+                    "type": "number"
+                },
+                "errorStatusCode": {
+                    "type": "integer"
+                },
+                "latencyMs": {
+                    "type": "integer"
+                },
+                "latencyProbability": {
+                    "description": "LatencyProbability is the chance of delaying a call by LatencyMs",
+                    "type": "number"
+                },
+                "truncateProbability": {
+                    "description": "TruncateProbability is the chance of cutting the upstream response body in half",
+                    "type": "number"
+                }
+            }
+        },
+        "client.FieldDiff": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "primary": {},
+                "shadow": {}
+            }
+        },
+        "client.KeyStats": {
+            "type": "object",
+            "properties": {
+                "benchedUntil": {
+                    "type": "string"
+                },
+                "failures": {
+                    "type": "integer"
+                },
+                "fingerprint": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "client.ShadowReport": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "diffs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/client.FieldDiff"
+                    }
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "request": {
+                    "type": "string"
+                }
+            }
+        },
+        "client.ShadowStats": {
+            "type": "object",
+            "properties": {
+                "compared": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "mismatched": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "client.ShadowSummary": {
+            "type": "object",
+            "properties": {
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/client.ShadowReport"
+                    }
+                },
+                "stats": {
+                    "$ref": "#/definitions/client.ShadowStats"
+                }
+            }
+        },
+        "dto.GeneralResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "status": {
+                    "$ref": "#/definitions/dto.Status"
+                }
+            }
+        },
+        "dto.Status": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CAIPIDsDTO": {
+            "type": "object",
+            "properties": {
+                "asset": {
+                    "type": "string"
+                },
+                "chain": {
+                    "description": "Chain is the CAIP-2 chain id",
+                    "type": "string"
+                },
+                "collection": {
+                    "description": "Collection and Asset are CAIP-19 asset ids",
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Owner is a CAIP-10 account id",
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreatorDTO": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ExtendedTraitProperty": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "percentile": {
+                    "description": "Percentile is the share of traits in the request at least as common as this one, the rarest is 100",
+                    "type": "number"
+                },
+                "rank": {
+                    "description": "Rank, Percentile and Tier are computed by this service and not sent by upstream.\nRank is 1 for the rarest trait in the request, ties share a rank.",
+                    "type": "integer"
+                },
+                "rarity": {
+                    "type": "string",
+                    "example": "1.2"
+                },
+                "tier": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ItemDTO": {
+            "type": "object",
+            "properties": {
+                "blockchain": {
+                    "type": "string"
+                },
+                "caip": {
+                    "$ref": "#/definitions/model.CAIPIDsDTO"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "collection": {
+                    "type": "string"
+                },
+                "contract": {
+                    "type": "string"
+                },
+                "creators": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CreatorDTO"
+                    }
+                },
+                "deleted": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastUpdatedAt": {
+                    "type": "string"
+                },
+                "lazySupply": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/model.ItemMetaDTO"
+                },
+                "mintedAt": {
+                    "type": "string"
+                },
+                "mintedSupply": {
+                    "description": "MintedSupply is computed as Supply minus LazySupply and not sent by upstream, so it is nil until computed",
+                    "type": "string",
+                    "example": "1"
+                },
+                "supply": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "tokenId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ItemMetaDTO": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MetaAttributeDTO"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ItemRarityBreakdownDTO": {
+            "type": "object",
+            "properties": {
+                "collectionId": {
+                    "type": "string"
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percentile": {
+                    "description": "Percentile is the share of the collection ranked at or below this item, the rarest is 100",
+                    "type": "number"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "ranks": {
+                    "$ref": "#/definitions/model.RarityRanksDTO"
+                },
+                "score": {
+                    "description": "Score is the item's score under the requested method",
+                    "type": "number"
+                },
+                "scores": {
+                    "$ref": "#/definitions/model.RarityScoresDTO"
+                },
+                "tokenId": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total is the number of items in the collection",
+                    "type": "integer"
+                },
+                "traits": {
+                    "description": "Traits are sorted by share, the trait with the most impact on the score first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TraitContributionDTO"
+                    }
+                }
+            }
+        },
+        "model.ItemRarityDTO": {
+            "type": "object",
+            "properties": {
+                "itemId": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percentile": {
+                    "description": "Percentile is the share of the collection ranked at or below this item, the rarest is 100",
+                    "type": "number"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "ranks": {
+                    "$ref": "#/definitions/model.RarityRanksDTO"
+                },
+                "score": {
+                    "description": "Score is the item's score under the requested method",
+                    "type": "number"
+                },
+                "scores": {
+                    "$ref": "#/definitions/model.RarityScoresDTO"
+                },
+                "tokenId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ItemTraitRarityResponseDTO": {
+            "type": "object",
+            "properties": {
+                "collectionId": {
+                    "type": "string"
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is unrevealed when the item's metadata is missing or a pre-reveal placeholder,\nno_attributes when it has none; traits are empty in both cases",
+                    "type": "string"
+                },
+                "traits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ExtendedTraitProperty"
+                    }
+                }
+            }
+        },
+        "model.MetaAttributeDTO": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MetadataRarityResponseDTO": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "Skipped are the archive's files that could not be read as token metadata",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SkippedFileDTO"
+                    }
+                },
+                "tokens": {
+                    "description": "Tokens are every token, rarest first; their ItemID is the token id",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemRarityDTO"
+                    }
+                },
+                "total": {
+                    "description": "Total is the number of tokens read from the archive",
+                    "type": "integer"
+                },
+                "traitStats": {
+                    "$ref": "#/definitions/model.TraitStatsResponseDTO"
+                }
+            }
+        },
+        "model.OpenRarityDiffDTO": {
+            "type": "object",
+            "properties": {
+                "collectionId": {
+                    "type": "string"
+                },
+                "compared": {
+                    "description": "Compared is the number of tokens in both, Identical the number of those ranked the same",
+                    "type": "integer"
+                },
+                "identical": {
+                    "type": "integer"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "maxAbsRankDelta": {
+                    "type": "integer"
+                },
+                "meanAbsRankDelta": {
+                    "description": "MeanAbsRankDelta and MaxAbsRankDelta summarise how far the compared tokens' ranks are apart",
+                    "type": "number"
+                },
+                "missing": {
+                    "description": "Missing are the collection's token ids absent from the import, Unknown the imported ones not in the collection",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tokens": {
+                    "description": "Tokens are the compared tokens whose ranks differ, largest difference first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OpenRarityTokenDiffDTO"
+                    }
+                },
+                "total": {
+                    "description": "Total is the number of tokens in the collection and Imported the number in the imported ranking",
+                    "type": "integer"
+                },
+                "unknown": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.OpenRarityRankingsDTO": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "string"
+                },
+                "collection_id": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OpenRarityTokenDTO"
+                    }
+                },
+                "total_supply": {
+                    "description": "TotalSupply is the number of tokens ranked",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.OpenRarityTokenDTO": {
+            "type": "object",
+            "properties": {
+                "rank": {
+                    "description": "Rank 1 is the rarest token, ties share a rank",
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "token_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "unique_attribute_count": {
+                    "description": "UniqueAttributeCount is the number of the token's traits no other token has",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.OpenRarityTokenDiffDTO": {
+            "type": "object",
+            "properties": {
+                "importedRank": {
+                    "type": "integer"
+                },
+                "importedScore": {
+                    "type": "number"
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "rankDelta": {
+                    "description": "RankDelta is the imported rank minus ours, positive when the import ranks the token as more common",
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "tokenId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OwnershipDTO": {
+            "type": "object",
+            "properties": {
+                "blockchain": {
+                    "type": "string"
+                },
+                "caip": {
+                    "$ref": "#/definitions/model.CAIPIDsDTO"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "collection": {
+                    "type": "string"
+                },
+                "contract": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "creators": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CreatorDTO"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "lastUpdatedAt": {
+                    "type": "string"
+                },
+                "lazyValue": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "mintedValue": {
+                    "description": "MintedValue is computed as Value minus LazyValue and not sent by upstream, so it is nil until computed",
+                    "type": "string",
+                    "example": "1"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "tokenId": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "model.RarityRankingsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "collectionId": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemRarityDTO"
                     }
+                },
+                "matched": {
+                    "description": "Matched is the number of items matching the filter, Total without one",
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.GeneralResponse": {
+        },
+        "model.RarityRanksDTO": {
             "type": "object",
             "properties": {
-                "data": {},
-                "status": {
-                    "$ref": "#/definitions/dto.Status"
+                "informationContent": {
+                    "type": "integer"
+                },
+                "rarityScore": {
+                    "type": "integer"
+                },
+                "statistical": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.Status": {
+        "model.RarityScoresDTO": {
             "type": "object",
             "properties": {
-                "error": {
+                "informationContent": {
+                    "type": "number"
+                },
+                "rarityScore": {
+                    "type": "number"
+                },
+                "statistical": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ResolveResponseDTO": {
+            "type": "object",
+            "properties": {
+                "blockchain": {
                     "type": "string"
                 },
-                "message": {
+                "caip": {
+                    "$ref": "#/definitions/model.CAIPIDsDTO"
+                },
+                "collectionId": {
                     "type": "string"
                 },
-                "status": {
+                "input": {
                     "type": "string"
                 },
-                "status_code": {
-                    "type": "integer"
+                "item": {
+                    "$ref": "#/definitions/model.ItemDTO"
                 },
-                "timestamp": {
+                "itemId": {
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Owner is the holder, as BLOCKCHAIN:address, named by an input that names no item",
+                    "type": "string"
+                },
+                "ownership": {
+                    "$ref": "#/definitions/model.OwnershipDTO"
+                },
+                "ownershipId": {
+                    "type": "string"
+                },
+                "source": {
                     "type": "string"
                 }
             }
         },
-        "model.CreatorDTO": {
+        "model.SimilarItemDTO": {
             "type": "object",
             "properties": {
-                "account": {
+                "differing": {
+                    "description": "Differing holds, by key, the values only one of the two items has",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TraitDifferenceDTO"
+                    }
+                },
+                "itemId": {
                     "type": "string"
                 },
-                "value": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
+                },
+                "shared": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TraitPropertyInput"
+                    }
+                },
+                "similarity": {
+                    "description": "Similarity goes from 0 for nothing in common to 1 for the same traits",
+                    "type": "number"
+                },
+                "tokenId": {
+                    "type": "string"
                 }
             }
         },
-        "model.ExtendedTraitProperty": {
+        "model.SimilarItemsResponseDTO": {
             "type": "object",
             "properties": {
-                "key": {
+                "collectionId": {
                     "type": "string"
                 },
-                "rarity": {
+                "itemId": {
                     "type": "string"
                 },
-                "value": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SimilarItemDTO"
+                    }
+                },
+                "method": {
                     "type": "string"
+                },
+                "total": {
+                    "description": "Total is the number of items sharing at least one trait with the item and matching the filter",
+                    "type": "integer"
                 }
             }
         },
-        "model.OwnershipDTO": {
+        "model.SkippedFileDTO": {
             "type": "object",
             "properties": {
-                "blockchain": {
+                "file": {
                     "type": "string"
                 },
-                "code": {
+                "reason": {
                     "type": "string"
+                }
+            }
+        },
+        "model.TierBucketDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "integer"
                 },
-                "collection": {
+                "tier": {
                     "type": "string"
                 },
-                "contract": {
+                "values": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TraitCombinationDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is the number of items having every trait of the combination",
+                    "type": "integer"
+                },
+                "rarity": {
+                    "description": "Rarity is the share of the collection's items having the combination, in percent",
+                    "type": "string",
+                    "example": "0.4"
+                },
+                "tier": {
                     "type": "string"
                 },
-                "createdAt": {
+                "traits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TraitPropertyInput"
+                    }
+                }
+            }
+        },
+        "model.TraitCombinationRarityDTO": {
+            "type": "object",
+            "properties": {
+                "collectionId": {
                     "type": "string"
                 },
-                "creators": {
+                "count": {
+                    "description": "Count is the number of items having every trait of the combination",
+                    "type": "integer"
+                },
+                "expectedRarity": {
+                    "description": "ExpectedRarity is the combination's rarity if its traits occurred independently,\na Rarity well below it means the traits rarely appear together",
+                    "type": "string",
+                    "example": "1.2"
+                },
+                "individual": {
+                    "description": "Individual holds the rarity of every trait of the combination on its own",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.CreatorDTO"
+                        "$ref": "#/definitions/model.ExtendedTraitProperty"
                     }
                 },
-                "id": {
-                    "type": "string"
+                "rarity": {
+                    "description": "Rarity is the share of the collection's items having the combination, in percent",
+                    "type": "string",
+                    "example": "0.4"
                 },
-                "itemId": {
+                "tier": {
                     "type": "string"
                 },
-                "lastUpdatedAt": {
+                "total": {
+                    "type": "integer"
+                },
+                "traits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TraitPropertyInput"
+                    }
+                }
+            }
+        },
+        "model.TraitCombinationsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "collectionId": {
                     "type": "string"
                 },
-                "lazyValue": {
+                "combinations": {
+                    "description": "Combinations are the rarest combinations found, rarest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TraitCombinationDTO"
+                    }
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Total is the number of items in the collection, or matching the filter when one is set",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TraitContributionDTO": {
+            "type": "object",
+            "properties": {
+                "contribution": {
+                    "description": "Contribution is the trait's term of the score under the requested method",
+                    "type": "number"
+                },
+                "count": {
+                    "description": "Count is the number of items in the collection with the trait",
+                    "type": "integer"
+                },
+                "key": {
                     "type": "string"
                 },
-                "message": {
+                "rarity": {
+                    "description": "Rarity is the share of the collection's items with the trait, in percent",
+                    "type": "string",
+                    "example": "1.2"
+                },
+                "share": {
+                    "description": "Share is the percentage of the score the trait accounts for",
+                    "type": "number"
+                },
+                "synthetic": {
+                    "description": "Synthetic is set for the missing trait and trait count meta-traits",
+                    "type": "boolean"
+                },
+                "tier": {
                     "type": "string"
                 },
-                "owner": {
+                "upstreamRarity": {
+                    "description": "UpstreamRarity is the rarity Rarible reports for the trait, when available",
+                    "type": "string",
+                    "example": "1.2"
+                },
+                "value": {
                     "type": "string"
+                }
+            }
+        },
+        "model.TraitCountBucketDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "integer"
                 },
-                "tokenId": {
+                "traits": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TraitDifferenceDTO": {
+            "type": "object",
+            "properties": {
+                "key": {
                     "type": "string"
                 },
-                "value": {
+                "similarValues": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "values": {
+                    "description": "Values are the item's own values of the key and SimilarValues the similar item's, either may be empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.TraitKeyStatsDTO": {
+            "type": "object",
+            "properties": {
+                "distribution": {
+                    "description": "Distribution are the key's values, most common first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TraitValueStatsDTO"
+                    }
+                },
+                "entropy": {
+                    "description": "Entropy is the Shannon entropy of the key's values in bits, items missing the key counting as one more value",
+                    "type": "number"
+                },
+                "histogram": {
+                    "description": "Histogram counts the key's values and the items holding them by rarity tier, legendary first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TierBucketDTO"
+                    }
+                },
+                "items": {
+                    "description": "Items is the number of items with the key, Missing the number without it",
+                    "type": "integer"
+                },
+                "key": {
                     "type": "string"
+                },
+                "longTail": {
+                    "description": "LongTail are the values at or below the long tail rarity",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "missing": {
+                    "type": "integer"
+                },
+                "missingRarity": {
+                    "type": "string",
+                    "example": "12.5"
+                },
+                "normalizedEntropy": {
+                    "description": "NormalizedEntropy is Entropy over its maximum for as many values, from 0 for a constant key to 1 for a uniform one",
+                    "type": "number"
+                },
+                "values": {
+                    "description": "Values is the number of distinct values of the key",
+                    "type": "integer"
                 }
             }
         },
@@ -263,6 +2817,7 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "collectionId": {
+                    "description": "CollectionID is a Rarible collection id or its CAIP-19 form",
                     "type": "string"
                 },
                 "properties": {
@@ -292,6 +2847,60 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "model.TraitStatsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "collectionId": {
+                    "type": "string"
+                },
+                "computedAt": {
+                    "description": "ComputedAt is when the stats were computed, they are cached per collection for a while",
+                    "type": "string"
+                },
+                "items": {
+                    "description": "Items is the number of items in the collection, or matching the filter when one is set",
+                    "type": "integer"
+                },
+                "keys": {
+                    "description": "Keys and Values are the number of distinct trait keys and key/value pairs",
+                    "type": "integer"
+                },
+                "traitCounts": {
+                    "description": "TraitCounts tells how many items have each number of traits",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TraitCountBucketDTO"
+                    }
+                },
+                "traitKeys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TraitKeyStatsDTO"
+                    }
+                },
+                "values": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TraitValueStatsDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "rarity": {
+                    "type": "string",
+                    "example": "1.2"
+                },
+                "tier": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
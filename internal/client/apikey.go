@@ -0,0 +1,31 @@
+package client
+
+import "context"
+
+type apiKeyCtxKey struct{}
+
+// WithAPIKey returns a copy of ctx carrying a caller supplied Rarible API key.
+// The key is used for requests made with that context only, bypassing the
+// shared key pool since the traffic is billed to the caller. The requests still
+// go through the limiter like any other upstream call.
+func WithAPIKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, apiKeyCtxKey{}, key)
+}
+
+// apiKeyFromContext returns the caller supplied API key carried by ctx, if any
+func apiKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(apiKeyCtxKey{}).(string)
+	return key, ok && key != ""
+}
+
+// withoutAPIKey returns a copy of ctx that no longer carries a caller supplied
+// API key, so requests made with it use the shared key pool
+func withoutAPIKey(ctx context.Context) context.Context {
+	if _, ok := apiKeyFromContext(ctx); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, apiKeyCtxKey{}, "")
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaribleClient_WithAPIKey(t *testing.T) {
+	t.Run("ShouldUseCallerKeyForThatRequestOnly", func(t *testing.T) {
+		var usedKeys []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			usedKeys = append(usedKeys, r.Header.Get("X-API-KEY"))
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		pool := NewKeyPool([]string{"shared-key"}, KeySelectionRoundRobin, time.Minute)
+		client := NewRaribleClient("", server.URL, WithKeyPool(pool))
+
+		_, err := client.GetOwnershipByID(WithAPIKey(context.Background(), "caller-key"), "test-id")
+		require.NoError(t, err)
+		_, err = client.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"caller-key", "shared-key"}, usedKeys)
+		require.Equal(t, uint64(1), pool.Stats()[0].Requests)
+	})
+
+	t.Run("ShouldNotBenchSharedKeyWhenCallerKeyIsRejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		pool := NewKeyPool([]string{"shared-key"}, KeySelectionRoundRobin, time.Minute)
+		client := NewRaribleClient("", server.URL, WithKeyPool(pool))
+
+		ownership, err := client.GetOwnershipByID(WithAPIKey(context.Background(), "caller-key"), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, ownership.StatusCode)
+		require.Zero(t, pool.Stats()[0].Failures)
+	})
+
+	t.Run("ShouldWaitForLimiterSlot", func(t *testing.T) {
+		unblock := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			// also returns when the server is closed, so a failing assertion does not hang the test
+			select {
+			case <-unblock:
+			case <-r.Context().Done():
+			}
+			w.Write([]byte(`{"id":"test-id"}`))
+		}))
+		defer server.Close()
+
+		client := NewRaribleClient("test-api-key", server.URL, WithConcurrencyLimit(1, 0))
+
+		first := make(chan error, 1)
+		go func() {
+			_, err := client.GetOwnershipByID(context.Background(), "test-id")
+			first <- err
+		}()
+
+		require.Eventually(t, func() bool {
+			client := client.(*raribleClient)
+			client.limiter.mu.Lock()
+			defer client.limiter.mu.Unlock()
+			return client.limiter.inFlight == 1
+		}, time.Second, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(WithAPIKey(context.Background(), "caller-key"), 50*time.Millisecond)
+		defer cancel()
+		_, err := client.GetOwnershipByID(ctx, "test-id")
+		require.ErrorIs(t, err, businesserrors.ErrUpstreamOverloaded)
+
+		close(unblock)
+		require.NoError(t, <-first)
+	})
+
+	t.Run("ShouldThrottleLimiterOn429", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"code":"TOO_MANY_REQUESTS","message":"rate limited"}`))
+		}))
+		defer server.Close()
+
+		client := NewRaribleClient("test-api-key", server.URL, WithConcurrencyLimit(4, 1))
+
+		ownership, err := client.GetOwnershipByID(WithAPIKey(context.Background(), "caller-key"), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusTooManyRequests, ownership.StatusCode)
+
+		ctx := WithPriority(WithAPIKey(context.Background(), "caller-key"), PriorityBackground)
+		_, err = client.GetOwnershipByID(ctx, "test-id")
+		require.ErrorIs(t, err, businesserrors.ErrUpstreamOverloaded)
+	})
+}
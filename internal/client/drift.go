@@ -0,0 +1,237 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrSchemaDrift is returned for drifted responses when the detector is configured to fail on drift
+var ErrSchemaDrift = errors.New("upstream schema drift")
+
+// DriftKind classifies a difference between an upstream payload and our models
+type DriftKind string
+
+const (
+	DriftUnknownField DriftKind = "unknown_field"
+	DriftMissingField DriftKind = "missing_field"
+	DriftTypeMismatch DriftKind = "type_mismatch"
+)
+
+// DriftIssue is a single drifted field, with slice elements collapsed to [] in its path
+type DriftIssue struct {
+	Kind  DriftKind `json:"kind"`
+	Field string    `json:"field"`
+}
+
+func (i DriftIssue) String() string {
+	return fmt.Sprintf("%s %s", i.Kind, i.Field)
+}
+
+// DriftSummary is a snapshot of the drift counters and distinct issues per endpoint
+type DriftSummary struct {
+	Counts map[string]map[DriftKind]uint64 `json:"counts"`
+	Issues map[string][]DriftIssue         `json:"issues"`
+}
+
+// DriftDetector compares successful upstream payloads against the model they
+// are decoded into, logging each distinct issue once per endpoint and
+// counting every occurrence
+type DriftDetector struct {
+	failOnDrift bool
+
+	mu     sync.Mutex
+	seen   map[string]map[DriftIssue]struct{}
+	counts map[string]map[DriftKind]uint64
+}
+
+// NewDriftDetector creates a detector. With failOnDrift set, drifted responses
+// fail with ErrSchemaDrift instead of only being reported.
+func NewDriftDetector(failOnDrift bool) *DriftDetector {
+	return &DriftDetector{
+		failOnDrift: failOnDrift,
+		seen:        make(map[string]map[DriftIssue]struct{}),
+		counts:      make(map[string]map[DriftKind]uint64),
+	}
+}
+
+// Counts returns the number of drift occurrences per endpoint and kind
+func (d *DriftDetector) Counts() map[string]map[DriftKind]uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	counts := make(map[string]map[DriftKind]uint64, len(d.counts))
+	for endpoint, kinds := range d.counts {
+		counts[endpoint] = make(map[DriftKind]uint64, len(kinds))
+		for kind, n := range kinds {
+			counts[endpoint][kind] = n
+		}
+	}
+	return counts
+}
+
+// Report returns every distinct issue seen per endpoint, sorted by field
+func (d *DriftDetector) Report() map[string][]DriftIssue {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	report := make(map[string][]DriftIssue, len(d.seen))
+	for endpoint, issues := range d.seen {
+		for issue := range issues {
+			report[endpoint] = append(report[endpoint], issue)
+		}
+		slices.SortFunc(report[endpoint], func(a, b DriftIssue) int {
+			return strings.Compare(a.String(), b.String())
+		})
+	}
+	return report
+}
+
+// Summary returns the drift counters together with the distinct issues
+func (d *DriftDetector) Summary() DriftSummary {
+	return DriftSummary{Counts: d.Counts(), Issues: d.Report()}
+}
+
+// check inspects body against the type of target and records any drift
+func (d *DriftDetector) check(endpoint string, body []byte, target any) error {
+	var issues []DriftIssue
+	collectDrift("", json.RawMessage(body), reflect.TypeOf(target).Elem(), &issues)
+	if len(issues) == 0 {
+		return nil
+	}
+
+	d.record(endpoint, issues)
+	if d.failOnDrift {
+		return fmt.Errorf("%w on %s: %v", ErrSchemaDrift, endpoint, issues)
+	}
+	return nil
+}
+
+func (d *DriftDetector) record(endpoint string, issues []DriftIssue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.seen[endpoint] == nil {
+		d.seen[endpoint] = make(map[DriftIssue]struct{})
+		d.counts[endpoint] = make(map[DriftKind]uint64)
+	}
+
+	var fresh []string
+	for _, issue := range issues {
+		d.counts[endpoint][issue.Kind]++
+		if _, ok := d.seen[endpoint][issue]; ok {
+			continue
+		}
+		d.seen[endpoint][issue] = struct{}{}
+		fresh = append(fresh, issue.String())
+	}
+
+	if len(fresh) > 0 {
+		log.Warn().
+			Str("endpoint", endpoint).
+			Strs("issues", fresh).
+			Msg("upstream response drifted from model")
+	}
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// collectDrift walks raw alongside typ, descending into structs and slices and
+// treating anything else, including json.Unmarshaler implementations, as a leaf
+func collectDrift(path string, raw json.RawMessage, typ reflect.Type, issues *[]DriftIssue) {
+	if string(raw) == "null" {
+		return
+	}
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	isLeaf := reflect.PointerTo(typ).Implements(jsonUnmarshalerType) ||
+		(typ.Kind() != reflect.Struct && typ.Kind() != reflect.Slice)
+	if isLeaf {
+		if err := json.Unmarshal(raw, reflect.New(typ).Interface()); err != nil {
+			*issues = append(*issues, DriftIssue{Kind: DriftTypeMismatch, Field: path})
+		}
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			*issues = append(*issues, DriftIssue{Kind: DriftTypeMismatch, Field: path})
+			return
+		}
+
+		known := make(map[string]struct{}, typ.NumField())
+		for i := range typ.NumField() {
+			field := typ.Field(i)
+			name, omitEmpty, ok := jsonFieldName(field)
+			if !ok {
+				continue
+			}
+			known[name] = struct{}{}
+
+			value, present := fields[name]
+			if !present {
+				if !omitEmpty {
+					*issues = append(*issues, DriftIssue{Kind: DriftMissingField, Field: joinDriftPath(path, name)})
+				}
+				continue
+			}
+			collectDrift(joinDriftPath(path, name), value, field.Type, issues)
+		}
+
+		for name := range fields {
+			if _, ok := known[name]; !ok {
+				*issues = append(*issues, DriftIssue{Kind: DriftUnknownField, Field: joinDriftPath(path, name)})
+			}
+		}
+	case reflect.Slice:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			*issues = append(*issues, DriftIssue{Kind: DriftTypeMismatch, Field: path})
+			return
+		}
+		seen := make(map[DriftIssue]struct{})
+		for _, elem := range elems {
+			var elemIssues []DriftIssue
+			collectDrift(path+"[]", elem, typ.Elem(), &elemIssues)
+			for _, issue := range elemIssues {
+				if _, ok := seen[issue]; !ok {
+					seen[issue] = struct{}{}
+					*issues = append(*issues, issue)
+				}
+			}
+		}
+	}
+}
+
+// jsonFieldName returns the JSON name of a struct field and whether it is optional
+func jsonFieldName(field reflect.StructField) (string, bool, bool) {
+	if !field.IsExported() {
+		return "", false, false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, slices.Contains(strings.Split(opts, ","), "omitempty"), true
+}
+
+func joinDriftPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
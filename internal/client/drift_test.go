@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const driftedOwnership = `{
+	"id": "ETHEREUM:0x123:1:0xabc",
+	"blockchain": "ETHEREUM",
+	"itemId": "ETHEREUM:0x123:1",
+	"contract": "ETHEREUM:0x123",
+	"collection": "ETHEREUM:0x123",
+	"tokenId": "1",
+	"owner": "ETHEREUM:0xabc",
+	"value": 1,
+	"createdAt": "2024-01-01T00:00:00Z",
+	"lastUpdatedAt": "2024-01-01T00:00:00Z",
+	"creators": [{"account": "ETHEREUM:0xabc", "value": 10000, "share": 1}],
+	"version": 2
+}`
+
+func TestDriftDetector(t *testing.T) {
+	newServer := func() *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(driftedOwnership))
+		}))
+	}
+
+	t.Run("ShouldReportDriftWithoutFailing", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		detector := NewDriftDetector(false)
+		client := NewRaribleClient("test-api-key", server.URL, WithDriftDetector(detector))
+
+		ownership, err := client.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, "ETHEREUM:0xabc", ownership.Owner)
+
+		require.Equal(t, []DriftIssue{
+			{Kind: DriftMissingField, Field: "lazyValue"},
+			{Kind: DriftTypeMismatch, Field: "value"},
+			{Kind: DriftUnknownField, Field: "creators[].share"},
+			{Kind: DriftUnknownField, Field: "version"},
+		}, detector.Report()["ownerships"])
+	})
+
+	t.Run("ShouldCountEveryOccurrenceButLogOnce", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		detector := NewDriftDetector(false)
+		client := NewRaribleClient("test-api-key", server.URL, WithDriftDetector(detector))
+
+		for range 2 {
+			_, err := client.GetOwnershipByID(context.Background(), "test-id")
+			require.NoError(t, err)
+		}
+
+		require.Len(t, detector.Report()["ownerships"], 4)
+		require.Equal(t, uint64(4), detector.Counts()["ownerships"][DriftUnknownField])
+	})
+
+	t.Run("ShouldFailWhenConfigured", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		client := NewRaribleClient("test-api-key", server.URL, WithDriftDetector(NewDriftDetector(true)))
+
+		_, err := client.GetOwnershipByID(context.Background(), "test-id")
+		require.ErrorIs(t, err, ErrSchemaDrift)
+	})
+
+	t.Run("ShouldIgnoreErrorResponses", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"NOT_FOUND","message":"Ownership not found"}`))
+		}))
+		defer server.Close()
+
+		detector := NewDriftDetector(true)
+		client := NewRaribleClient("test-api-key", server.URL, WithDriftDetector(detector))
+
+		ownership, err := client.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, ownership.StatusCode)
+		require.Empty(t, detector.Report())
+	})
+}
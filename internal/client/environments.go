@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/Megidy/rarible/internal/domain/model"
+)
+
+type environmentCtxKey struct{}
+
+// WithEnvironment returns a copy of ctx selecting the named upstream environment
+func WithEnvironment(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, environmentCtxKey{}, name)
+}
+
+// EnvironmentFromContext returns the upstream environment selected in ctx, if any.
+// Anything caching upstream data should include it in its keys.
+func EnvironmentFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(environmentCtxKey{}).(string)
+	return name, ok && name != ""
+}
+
+// EnvironmentRouter dispatches calls to one RaribleClient per upstream
+// environment (mainnet, testnet, ...) based on the environment in the context
+type EnvironmentRouter struct {
+	defaultEnvironment string
+	clients            map[string]RaribleClient
+}
+
+func NewEnvironmentRouter(defaultEnvironment string, clients map[string]RaribleClient) (*EnvironmentRouter, error) {
+	if _, ok := clients[defaultEnvironment]; !ok {
+		return nil, fmt.Errorf("default environment %q has no client", defaultEnvironment)
+	}
+	return &EnvironmentRouter{
+		defaultEnvironment: defaultEnvironment,
+		clients:            clients,
+	}, nil
+}
+
+// Default returns the name of the environment used when none is selected
+func (r *EnvironmentRouter) Default() string {
+	return r.defaultEnvironment
+}
+
+// Names returns the sorted names of all configured environments
+func (r *EnvironmentRouter) Names() []string {
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has reports whether an environment with the given name is configured
+func (r *EnvironmentRouter) Has(name string) bool {
+	_, ok := r.clients[name]
+	return ok
+}
+
+// GetOwnershipByID fetches ownership data by ID from the selected environment
+func (r *EnvironmentRouter) GetOwnershipByID(ctx context.Context, id string) (*model.OwnershipDTO, error) {
+	c, err := r.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetOwnershipByID(ctx, id)
+}
+
+// GetItemByID fetches item data by ID from the selected environment
+func (r *EnvironmentRouter) GetItemByID(ctx context.Context, id string) (*model.ItemDTO, error) {
+	c, err := r.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetItemByID(ctx, id)
+}
+
+// GetItemsByCollection fetches a page of a collection's items from the selected environment
+func (r *EnvironmentRouter) GetItemsByCollection(ctx context.Context, collectionID, continuation string, size int) (*model.ItemsDTO, error) {
+	c, err := r.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetItemsByCollection(ctx, collectionID, continuation, size)
+}
+
+// GetTraitRarity returns rarity of a given trait from the selected environment
+func (r *EnvironmentRouter) GetTraitRarity(ctx context.Context, req *model.TraitRarityRequestDTO) (*model.TraitRarityResponseDTO, error) {
+	c, err := r.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetTraitRarity(ctx, req)
+}
+
+func (r *EnvironmentRouter) clientFor(ctx context.Context) (RaribleClient, error) {
+	name, ok := EnvironmentFromContext(ctx)
+	if !ok {
+		name = r.defaultEnvironment
+	}
+	c, ok := r.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown environment %q", businesserrors.ErrInvalidRequest, name)
+	}
+	return c, nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentRouter(t *testing.T) {
+	newServer := func(owner string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"owner":"` + owner + `"}`))
+		}))
+	}
+	mainnet := newServer("mainnet-owner")
+	defer mainnet.Close()
+	testnet := newServer("testnet-owner")
+	defer testnet.Close()
+
+	router, err := NewEnvironmentRouter("mainnet", map[string]RaribleClient{
+		"mainnet": NewRaribleClient("mainnet-key", mainnet.URL),
+		"testnet": NewRaribleClient("testnet-key", testnet.URL),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"mainnet", "testnet"}, router.Names())
+
+	t.Run("ShouldUseDefaultEnvironment", func(t *testing.T) {
+		ownership, err := router.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, "mainnet-owner", ownership.Owner)
+	})
+
+	t.Run("ShouldUseSelectedEnvironment", func(t *testing.T) {
+		ownership, err := router.GetOwnershipByID(WithEnvironment(context.Background(), "testnet"), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, "testnet-owner", ownership.Owner)
+	})
+
+	t.Run("ShouldRejectUnknownEnvironment", func(t *testing.T) {
+		_, err := router.GetOwnershipByID(WithEnvironment(context.Background(), "devnet"), "test-id")
+		require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+	})
+
+	t.Run("ShouldRequireDefaultEnvironmentClient", func(t *testing.T) {
+		_, err := NewEnvironmentRouter("devnet", map[string]RaribleClient{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,102 @@
+package fake
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+)
+
+// DefaultCollection is the collection generated by DefaultDataset
+const DefaultCollection = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+// Dataset is the in-memory state served by the fake Rarible API
+type Dataset struct {
+	Items      []model.ItemDTO
+	Ownerships []model.OwnershipDTO
+}
+
+// traitPool lists the values generated per trait key, rarest last
+var traitPool = []struct {
+	key    string
+	values []string
+}{
+	{key: "Background", values: []string{"Blue", "Green", "Orange", "Purple", "Gold"}},
+	{key: "Eyes", values: []string{"Bored", "Sleepy", "Angry", "Red", "Laser"}},
+	{key: "Hat", values: []string{"Beanie", "Cap", "Fedora", "Crown", "Halo"}},
+	{key: "Mouth", values: []string{"Smile", "Grin", "Frown", "Gold Teeth"}},
+}
+
+// DefaultDataset returns a deterministic dataset of 100 items in DefaultCollection
+func DefaultDataset() Dataset {
+	return GenerateDataset(1, DefaultCollection, 100)
+}
+
+// GenerateDataset builds size items in collection with pseudo random traits
+// derived from seed, each owned by a single owner. Earlier trait values are
+// more common, and some items miss the Hat trait altogether.
+func GenerateDataset(seed uint64, collection string, size int) Dataset {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	mintedAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	blockchain, _, _ := strings.Cut(collection, ":")
+	dataset := Dataset{
+		Items:      make([]model.ItemDTO, 0, size),
+		Ownerships: make([]model.OwnershipDTO, 0, size),
+	}
+	for tokenID := 1; tokenID <= size; tokenID++ {
+		itemID := fmt.Sprintf("%s:%d", collection, tokenID)
+		owner := fmt.Sprintf("%s:0x%040x", blockchain, rng.Uint64())
+		creator := model.CreatorDTO{Account: fmt.Sprintf("%s:0x%040x", blockchain, seed), Value: 10000}
+
+		var attributes []model.MetaAttributeDTO
+		for _, trait := range traitPool {
+			if trait.key == "Hat" && rng.IntN(5) == 0 {
+				continue
+			}
+			attributes = append(attributes, model.MetaAttributeDTO{
+				Key:   trait.key,
+				Value: trait.values[skewedIndex(rng, len(trait.values))],
+			})
+		}
+
+		dataset.Items = append(dataset.Items, model.ItemDTO{
+			ID:            itemID,
+			Blockchain:    blockchain,
+			Collection:    collection,
+			Contract:      collection,
+			TokenID:       fmt.Sprint(tokenID),
+			Creators:      []model.CreatorDTO{creator},
+			LazySupply:    model.NewQuantity(0),
+			Supply:        model.NewQuantity(1),
+			MintedAt:      mintedAt.Add(time.Duration(tokenID) * time.Hour),
+			LastUpdatedAt: mintedAt.Add(time.Duration(tokenID) * time.Hour),
+			Meta: &model.ItemMetaDTO{
+				Name:       fmt.Sprintf("Fake #%d", tokenID),
+				Attributes: attributes,
+			},
+		})
+		dataset.Ownerships = append(dataset.Ownerships, model.OwnershipDTO{
+			ID:            fmt.Sprintf("%s:%s", itemID, strings.TrimPrefix(owner, blockchain+":")),
+			Blockchain:    blockchain,
+			ItemID:        itemID,
+			Contract:      collection,
+			Collection:    collection,
+			TokenID:       fmt.Sprint(tokenID),
+			Owner:         owner,
+			Value:         model.NewQuantity(1),
+			CreatedAt:     mintedAt.Add(time.Duration(tokenID) * time.Hour),
+			LastUpdatedAt: mintedAt.Add(time.Duration(tokenID) * time.Hour),
+			Creators:      []model.CreatorDTO{creator},
+			LazyValue:     model.NewQuantity(0),
+		})
+	}
+	return dataset
+}
+
+// skewedIndex picks an index in [0, n) where lower indexes are more likely
+func skewedIndex(rng *rand.Rand, n int) int {
+	return min(rng.IntN(n), rng.IntN(n))
+}
@@ -0,0 +1,331 @@
+// Package fake implements an in-process fake of the Rarible API serving a
+// seeded in-memory dataset, with scriptable faults for resilience tests and
+// local development without a real API key
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// Fault alters responses of requests whose path starts with Path
+type Fault struct {
+	// Path is the request path prefix the fault applies to, empty for every path
+	Path string
+	// Latency delays the response
+	Latency time.Duration
+	// StatusCode replaces the response with an error of this status when set
+	StatusCode int
+	// RetryAfter is sent as the Retry-After header alongside StatusCode
+	RetryAfter time.Duration
+	// Malformed truncates the response body so it is no longer valid JSON
+	Malformed bool
+	// Times is how many requests the fault applies to, 0 for every request
+	Times int
+}
+
+// Server is an http.Handler serving the ownership, items, traits and rarity endpoints
+type Server struct {
+	mux     *http.ServeMux
+	items   []model.ItemDTO
+	byID    map[string]model.ItemDTO
+	owners  map[string]model.OwnershipDTO
+	mu      sync.Mutex
+	faults  []*Fault
+	started *http.Server
+}
+
+func NewServer(dataset Dataset) *Server {
+	s := &Server{
+		mux:    http.NewServeMux(),
+		items:  dataset.Items,
+		byID:   make(map[string]model.ItemDTO, len(dataset.Items)),
+		owners: make(map[string]model.OwnershipDTO, len(dataset.Ownerships)),
+	}
+	for _, item := range dataset.Items {
+		s.byID[item.ID] = item
+	}
+	for _, ownership := range dataset.Ownerships {
+		s.owners[ownership.ID] = ownership
+	}
+
+	s.mux.HandleFunc("GET /ownerships/{id}", s.getOwnership)
+	s.mux.HandleFunc("GET /items/byCollection", s.getItemsByCollection)
+	s.mux.HandleFunc("GET /items/traits", s.getTraits)
+	s.mux.HandleFunc("POST /items/traits/rarity", s.getTraitRarity)
+	s.mux.HandleFunc("GET /items/{id}", s.getItem)
+	return s
+}
+
+// Script queues faults, applied in order to matching requests
+func (s *Server) Script(faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, fault := range faults {
+		s.faults = append(s.faults, &fault)
+	}
+}
+
+// ClearFaults removes every scripted fault
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Start serves the fake API on a random local port and returns its base URL
+func (s *Server) Start() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to listen: %w", err)
+	}
+
+	s.started = &http.Server{Handler: s, ReadHeaderTimeout: 5 * time.Second}
+	go s.started.Serve(listener)
+	return "http://" + listener.Addr().String(), nil
+}
+
+// Close stops a server started with Start
+func (s *Server) Close() error {
+	if s.started == nil {
+		return nil
+	}
+	return s.started.Close()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fault := s.nextFault(r.URL.Path)
+	if fault == nil {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fault.StatusCode != 0 {
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(fault.RetryAfter.Seconds()))))
+		}
+		writeError(w, fault.StatusCode, "injected fault")
+		return
+	}
+
+	if fault.Malformed {
+		w = &truncatingWriter{ResponseWriter: w}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// nextFault returns the first scripted fault matching path, consuming one of its uses
+func (s *Server) nextFault(path string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, fault := range s.faults {
+		if !strings.HasPrefix(path, fault.Path) {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return fault
+	}
+	return nil
+}
+
+func (s *Server) getOwnership(w http.ResponseWriter, r *http.Request) {
+	ownership, ok := s.owners[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Ownership %s not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, ownership)
+}
+
+func (s *Server) getItem(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.byID[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Item %s not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) getItemsByCollection(w http.ResponseWriter, r *http.Request) {
+	collection := r.URL.Query().Get("collection")
+	if collection == "" {
+		writeError(w, http.StatusBadRequest, "collection is required")
+		return
+	}
+
+	size := defaultPageSize
+	if raw := r.URL.Query().Get("size"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid size")
+			return
+		}
+		size = min(n, maxPageSize)
+	}
+
+	offset := 0
+	if raw := r.URL.Query().Get("continuation"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid continuation")
+			return
+		}
+		offset = n
+	}
+
+	items := s.collectionItems(collection)
+	page := model.ItemsDTO{Items: []model.ItemDTO{}}
+	if offset < len(items) {
+		end := min(offset+size, len(items))
+		page.Items = items[offset:end]
+		if end < len(items) {
+			page.Continuation = strconv.Itoa(end)
+		}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) getTraits(w http.ResponseWriter, r *http.Request) {
+	collection := r.URL.Query().Get("collectionIds")
+	if collection == "" {
+		writeError(w, http.StatusBadRequest, "collectionIds is required")
+		return
+	}
+
+	keyCounts := make(map[string]int)
+	valueCounts := make(map[string]map[string]int)
+	var keys []string
+	for _, item := range s.collectionItems(collection) {
+		if item.Meta == nil {
+			continue
+		}
+		for _, attribute := range item.Meta.Attributes {
+			if _, ok := valueCounts[attribute.Key]; !ok {
+				valueCounts[attribute.Key] = make(map[string]int)
+				keys = append(keys, attribute.Key)
+			}
+			keyCounts[attribute.Key]++
+			valueCounts[attribute.Key][attribute.Value]++
+		}
+	}
+
+	traits := model.TraitsDTO{Traits: make([]model.TraitDTO, 0, len(keys))}
+	for _, key := range keys {
+		trait := model.TraitDTO{Key: model.TraitValueCountDTO{Value: key, Count: keyCounts[key]}}
+		for value, count := range valueCounts[key] {
+			trait.Values = append(trait.Values, model.TraitValueCountDTO{Value: value, Count: count})
+		}
+		sort.Slice(trait.Values, func(i, j int) bool {
+			if trait.Values[i].Count != trait.Values[j].Count {
+				return trait.Values[i].Count > trait.Values[j].Count
+			}
+			return trait.Values[i].Value < trait.Values[j].Value
+		})
+		traits.Traits = append(traits.Traits, trait)
+	}
+	writeJSON(w, http.StatusOK, traits)
+}
+
+func (s *Server) getTraitRarity(w http.ResponseWriter, r *http.Request) {
+	var req model.TraitRarityRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	items := s.collectionItems(req.CollectionID)
+	if len(items) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Collection %s not found", req.CollectionID))
+		return
+	}
+
+	resp := model.TraitRarityResponseDTO{Traits: make([]model.ExtendedTraitProperty, 0, len(req.Properties))}
+	for _, property := range req.Properties {
+		count := 0
+		for _, item := range items {
+			if item.Meta != nil && hasAttribute(item.Meta.Attributes, property.Key, property.Value) {
+				count++
+			}
+		}
+		rarity := math.Round(float64(count)/float64(len(items))*100*10000) / 10000
+		resp.Traits = append(resp.Traits, model.ExtendedTraitProperty{
+			Key:    property.Key,
+			Value:  property.Value,
+			Rarity: model.Rarity(rarity),
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) collectionItems(collection string) []model.ItemDTO {
+	var items []model.ItemDTO
+	for _, item := range s.items {
+		if item.Collection == collection {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func hasAttribute(attributes []model.MetaAttributeDTO, key, value string) bool {
+	for _, attribute := range attributes {
+		if attribute.Key == key && attribute.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	code := strings.ToUpper(strings.ReplaceAll(http.StatusText(statusCode), " ", "_"))
+	writeJSON(w, statusCode, map[string]string{"code": code, "message": message})
+}
+
+// truncatingWriter drops the second half of the body so it cannot be decoded
+type truncatingWriter struct {
+	http.ResponseWriter
+}
+
+func (w *truncatingWriter) Write(b []byte) (int, error) {
+	if _, err := w.ResponseWriter.Write(b[:len(b)/2]); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,132 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Megidy/rarible/internal/client"
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	dataset := GenerateDataset(7, DefaultCollection, 30)
+	fake := NewServer(dataset)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	raribleClient := client.NewRaribleClient("test-api-key", server.URL)
+
+	t.Run("ShouldServeOwnership", func(t *testing.T) {
+		want := dataset.Ownerships[3]
+
+		ownership, err := raribleClient.GetOwnershipByID(context.Background(), want.ID)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, ownership.StatusCode)
+		require.Equal(t, want.Owner, ownership.Owner)
+		require.Equal(t, want.ItemID, ownership.ItemID)
+	})
+
+	t.Run("ShouldReturn404ForUnknownOwnership", func(t *testing.T) {
+		ownership, err := raribleClient.GetOwnershipByID(context.Background(), "ETHEREUM:0x0:1:0x0")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, ownership.StatusCode)
+		require.Equal(t, "NOT_FOUND", ownership.Code)
+	})
+
+	t.Run("ShouldPageItemsByCollection", func(t *testing.T) {
+		var page model.ItemsDTO
+		getJSON(t, server.URL+"/items/byCollection?collection="+DefaultCollection+"&size=20", &page)
+		require.Len(t, page.Items, 20)
+		require.Equal(t, "20", page.Continuation)
+
+		var last model.ItemsDTO
+		getJSON(t, server.URL+"/items/byCollection?collection="+DefaultCollection+"&size=20&continuation=20", &last)
+		require.Len(t, last.Items, 10)
+		require.Empty(t, last.Continuation)
+	})
+
+	t.Run("ShouldServeItemAndTraits", func(t *testing.T) {
+		var item model.ItemDTO
+		getJSON(t, server.URL+"/items/"+dataset.Items[0].ID, &item)
+		require.Equal(t, dataset.Items[0].Meta.Attributes, item.Meta.Attributes)
+
+		var traits model.TraitsDTO
+		getJSON(t, server.URL+"/items/traits?collectionIds="+DefaultCollection, &traits)
+		require.NotEmpty(t, traits.Traits)
+		total := 0
+		for _, value := range traits.Traits[0].Values {
+			total += value.Count
+		}
+		require.Equal(t, traits.Traits[0].Key.Count, total)
+	})
+
+	t.Run("ShouldComputeTraitRarity", func(t *testing.T) {
+		attribute := dataset.Items[0].Meta.Attributes[0]
+
+		resp, err := raribleClient.GetTraitRarity(context.Background(), &model.TraitRarityRequestDTO{
+			CollectionID: DefaultCollection,
+			Properties:   []model.TraitPropertyInput{{Key: attribute.Key, Value: attribute.Value}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Len(t, resp.Traits, 1)
+		require.NotEmpty(t, resp.Traits[0].Rarity)
+	})
+
+	t.Run("ShouldInjectScriptedFaults", func(t *testing.T) {
+		defer fake.ClearFaults()
+		id := dataset.Ownerships[0].ID
+
+		fake.Script(
+			Fault{Path: "/ownerships/", StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Second, Times: 1},
+			Fault{Path: "/ownerships/", StatusCode: http.StatusBadGateway, Times: 1},
+			Fault{Path: "/ownerships/", Malformed: true, Times: 1},
+			Fault{Path: "/ownerships/", Latency: 50 * time.Millisecond, Times: 1},
+		)
+
+		resp, err := http.Get(server.URL + "/ownerships/" + id)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		require.Equal(t, "2", resp.Header.Get("Retry-After"))
+
+		ownership, err := raribleClient.GetOwnershipByID(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadGateway, ownership.StatusCode)
+
+		_, err = raribleClient.GetOwnershipByID(context.Background(), id)
+		require.Error(t, err)
+
+		start := time.Now()
+		ownership, err = raribleClient.GetOwnershipByID(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, ownership.StatusCode)
+		require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+}
+
+func TestServer_Start(t *testing.T) {
+	fake := NewServer(DefaultDataset())
+	url, err := fake.Start()
+	require.NoError(t, err)
+	defer fake.Close()
+
+	ownership, err := client.NewRaribleClient("test-api-key", url).GetOwnershipByID(context.Background(), DefaultDataset().Ownerships[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, ownership.StatusCode)
+}
+
+func getJSON(t *testing.T, url string, target any) {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(target))
+}
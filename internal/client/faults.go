@@ -0,0 +1,188 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInjectedDrop is returned by the fault injector to simulate a dropped connection
+var ErrInjectedDrop = errors.New("injected fault: connection dropped")
+
+// FaultRule describes faults injected into upstream calls to one endpoint
+type FaultRule struct {
+	// Endpoint is the upstream endpoint the rule applies to, e.g. "ownerships" or "items/byCollection",
+	// empty for every endpoint. It matches the end of the path, optionally followed by a single id.
+	Endpoint string `json:"endpoint"`
+	// LatencyProbability is the chance of delaying a call by LatencyMs
+	LatencyProbability float64 `json:"latencyProbability"`
+	LatencyMs          int     `json:"latencyMs"`
+	// ErrorProbability is the chance of answering with ErrorStatusCode instead of calling upstream
+	ErrorProbability float64 `json:"errorProbability"`
+	ErrorStatusCode  int     `json:"errorStatusCode"`
+	// DropProbability is the chance of failing the call as if the connection dropped
+	DropProbability float64 `json:"dropProbability"`
+	// TruncateProbability is the chance of cutting the upstream response body in half
+	TruncateProbability float64 `json:"truncateProbability"`
+}
+
+// FaultConfig is the runtime configuration of the fault injector
+type FaultConfig struct {
+	Enabled bool        `json:"enabled"`
+	Rules   []FaultRule `json:"rules"`
+}
+
+// Validate reports the first invalid probability or status code in the config
+func (c FaultConfig) Validate() error {
+	for i, rule := range c.Rules {
+		probabilities := map[string]float64{
+			"latencyProbability":  rule.LatencyProbability,
+			"errorProbability":    rule.ErrorProbability,
+			"dropProbability":     rule.DropProbability,
+			"truncateProbability": rule.TruncateProbability,
+		}
+		for name, p := range probabilities {
+			if p < 0 || p > 1 {
+				return fmt.Errorf("rule %d: %s must be between 0 and 1", i, name)
+			}
+		}
+		if rule.LatencyMs < 0 {
+			return fmt.Errorf("rule %d: latencyMs must not be negative", i)
+		}
+		if rule.ErrorProbability > 0 && (rule.ErrorStatusCode < 400 || rule.ErrorStatusCode > 599) {
+			return fmt.Errorf("rule %d: errorStatusCode must be between 400 and 599", i)
+		}
+	}
+	return nil
+}
+
+// FaultInjector holds a fault configuration shared by every transport it wraps.
+// It starts disabled and can be reconfigured at runtime.
+type FaultInjector struct {
+	mu     sync.RWMutex
+	config FaultConfig
+}
+
+func NewFaultInjector() *FaultInjector {
+	return &FaultInjector{}
+}
+
+// Config returns the current fault configuration
+func (f *FaultInjector) Config() FaultConfig {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.config
+}
+
+// SetConfig replaces the fault configuration after validating it
+func (f *FaultInjector) SetConfig(config FaultConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.config = config
+	return nil
+}
+
+// Wrap returns a transport injecting the configured faults in front of next
+func (f *FaultInjector) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &faultTransport{injector: f, next: next}
+}
+
+// rule returns the first rule matching path, if injection is enabled
+func (f *FaultInjector) rule(path string) (FaultRule, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if !f.config.Enabled {
+		return FaultRule{}, false
+	}
+	for _, rule := range f.config.Rules {
+		if rule.Endpoint == "" || matchesEndpoint(path, rule.Endpoint) {
+			return rule, true
+		}
+	}
+	return FaultRule{}, false
+}
+
+// matchesEndpoint reports whether path ends with endpoint, or with endpoint followed by
+// a single id. Rarible ids are blockchain prefixed, so a final segment without a colon
+// is a sibling endpoint such as items/byCollection rather than an id under items.
+func matchesEndpoint(path, endpoint string) bool {
+	suffix := "/" + strings.Trim(endpoint, "/")
+	if strings.HasSuffix(path, suffix) {
+		return true
+	}
+	i := strings.LastIndex(path, "/")
+	return i >= 0 && strings.Contains(path[i+1:], ":") && strings.HasSuffix(path[:i], suffix)
+}
+
+type faultTransport struct {
+	injector *FaultInjector
+	next     http.RoundTripper
+}
+
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rule, ok := t.injector.rule(req.URL.Path)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	if chance(rule.LatencyProbability) {
+		select {
+		case <-time.After(time.Duration(rule.LatencyMs) * time.Millisecond):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	if chance(rule.DropProbability) {
+		return nil, ErrInjectedDrop
+	}
+
+	if chance(rule.ErrorProbability) {
+		body := fmt.Sprintf(`{"code":"INJECTED_FAULT","message":"injected %d response"}`, rule.ErrorStatusCode)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", rule.ErrorStatusCode, http.StatusText(rule.ErrorStatusCode)),
+			StatusCode:    rule.ErrorStatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !chance(rule.TruncateProbability) {
+		return resp, err
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	body = body[:len(body)/2]
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+func chance(p float64) bool {
+	return p > 0 && rand.Float64() < p
+}
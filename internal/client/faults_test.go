@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjector(t *testing.T) {
+	const (
+		ownershipID = "ETHEREUM:0x123:1:0xabc"
+		itemID      = "ETHEREUM:0x123:1"
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"test-id","owner":"0xabc"}`))
+	}))
+	defer server.Close()
+
+	injector := NewFaultInjector()
+	client := NewRaribleClient("test-api-key", server.URL, WithTransport(injector.Wrap(nil)))
+
+	t.Run("ShouldPassThroughWhenDisabled", func(t *testing.T) {
+		require.NoError(t, injector.SetConfig(FaultConfig{
+			Enabled: false,
+			Rules:   []FaultRule{{DropProbability: 1}},
+		}))
+
+		ownership, err := client.GetOwnershipByID(context.Background(), ownershipID)
+		require.NoError(t, err)
+		require.Equal(t, "0xabc", ownership.Owner)
+	})
+
+	t.Run("ShouldInjectErrorStatus", func(t *testing.T) {
+		require.NoError(t, injector.SetConfig(FaultConfig{
+			Enabled: true,
+			Rules:   []FaultRule{{Endpoint: "ownerships", ErrorProbability: 1, ErrorStatusCode: http.StatusServiceUnavailable}},
+		}))
+
+		ownership, err := client.GetOwnershipByID(context.Background(), ownershipID)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, ownership.StatusCode)
+		require.Equal(t, "INJECTED_FAULT", ownership.Code)
+	})
+
+	t.Run("ShouldDropConnection", func(t *testing.T) {
+		require.NoError(t, injector.SetConfig(FaultConfig{
+			Enabled: true,
+			Rules:   []FaultRule{{DropProbability: 1}},
+		}))
+
+		_, err := client.GetOwnershipByID(context.Background(), ownershipID)
+		require.ErrorIs(t, err, ErrInjectedDrop)
+	})
+
+	t.Run("ShouldTruncateBody", func(t *testing.T) {
+		require.NoError(t, injector.SetConfig(FaultConfig{
+			Enabled: true,
+			Rules:   []FaultRule{{TruncateProbability: 1}},
+		}))
+
+		_, err := client.GetOwnershipByID(context.Background(), ownershipID)
+		require.ErrorContains(t, err, "failed to decode ownership response")
+	})
+
+	t.Run("ShouldDelayCalls", func(t *testing.T) {
+		require.NoError(t, injector.SetConfig(FaultConfig{
+			Enabled: true,
+			Rules:   []FaultRule{{LatencyProbability: 1, LatencyMs: 50}},
+		}))
+
+		start := time.Now()
+		_, err := client.GetOwnershipByID(context.Background(), ownershipID)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("ShouldScopeRulesToEndpoint", func(t *testing.T) {
+		require.NoError(t, injector.SetConfig(FaultConfig{
+			Enabled: true,
+			Rules:   []FaultRule{{Endpoint: "items/traits/rarity", DropProbability: 1}},
+		}))
+
+		_, err := client.GetOwnershipByID(context.Background(), ownershipID)
+		require.NoError(t, err)
+	})
+
+	t.Run("ShouldNotHitSiblingEndpoint", func(t *testing.T) {
+		require.NoError(t, injector.SetConfig(FaultConfig{
+			Enabled: true,
+			Rules:   []FaultRule{{Endpoint: "items", DropProbability: 1}},
+		}))
+
+		_, err := client.GetItemsByCollection(context.Background(), "ETHEREUM:0x123", "", 10)
+		require.NoError(t, err)
+
+		_, err = client.GetItemByID(context.Background(), itemID)
+		require.ErrorIs(t, err, ErrInjectedDrop)
+	})
+
+	t.Run("ShouldMatchMultiSegmentEndpoint", func(t *testing.T) {
+		require.NoError(t, injector.SetConfig(FaultConfig{
+			Enabled: true,
+			Rules:   []FaultRule{{Endpoint: "items/byCollection", DropProbability: 1}},
+		}))
+
+		_, err := client.GetItemByID(context.Background(), itemID)
+		require.NoError(t, err)
+
+		_, err = client.GetItemsByCollection(context.Background(), "ETHEREUM:0x123", "", 10)
+		require.ErrorIs(t, err, ErrInjectedDrop)
+	})
+
+	t.Run("ShouldRejectInvalidConfig", func(t *testing.T) {
+		err := injector.SetConfig(FaultConfig{Rules: []FaultRule{{DropProbability: 2}}})
+		require.Error(t, err)
+
+		err = injector.SetConfig(FaultConfig{Rules: []FaultRule{{ErrorProbability: 0.5, ErrorStatusCode: http.StatusOK}}})
+		require.Error(t, err)
+	})
+}
@@ -9,6 +9,10 @@ import (
 type RaribleClient interface {
 	// GetOwnershipByID fetches ownership data by ID
 	GetOwnershipByID(ctx context.Context, id string) (*model.OwnershipDTO, error)
+	// GetItemByID fetches item data by ID
+	GetItemByID(ctx context.Context, id string) (*model.ItemDTO, error)
+	// GetItemsByCollection fetches a page of a collection's items, starting at continuation
+	GetItemsByCollection(ctx context.Context, collectionID, continuation string, size int) (*model.ItemsDTO, error)
 	// GetTraitRarity returns rarity of a given trait
 	GetTraitRarity(ctx context.Context, req *model.TraitRarityRequestDTO) (*model.TraitRarityResponseDTO, error)
 }
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// KeySelection decides which API key of the pool serves the next request
+type KeySelection string
+
+const (
+	KeySelectionRoundRobin KeySelection = "round-robin"
+	KeySelectionLeastUsed  KeySelection = "least-used"
+
+	defaultKeyBenchDuration = time.Minute
+)
+
+// KeyStats is a snapshot of a pooled key's usage. It identifies the key only
+// by a fingerprint so it is safe to log or expose.
+type KeyStats struct {
+	Fingerprint  string    `json:"fingerprint"`
+	Requests     uint64    `json:"requests"`
+	Failures     uint64    `json:"failures"`
+	BenchedUntil time.Time `json:"benchedUntil,omitempty"`
+}
+
+type apiKey struct {
+	value        string
+	fingerprint  string
+	requests     uint64
+	failures     uint64
+	benchedUntil time.Time
+}
+
+// KeyPool rotates requests across several Rarible API keys and temporarily
+// benches keys the upstream rejected or rate limited
+type KeyPool struct {
+	mu        sync.Mutex
+	keys      []*apiKey
+	next      int
+	selection KeySelection
+	benchFor  time.Duration
+}
+
+// NewKeyPool creates a pool from the given keys, skipping empty and duplicate ones
+func NewKeyPool(keys []string, selection KeySelection, benchFor time.Duration) *KeyPool {
+	if benchFor <= 0 {
+		benchFor = defaultKeyBenchDuration
+	}
+	pool := &KeyPool{
+		selection: selection,
+		benchFor:  benchFor,
+	}
+
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		pool.keys = append(pool.keys, &apiKey{
+			value:       key,
+			fingerprint: fingerprint(key),
+		})
+	}
+	return pool
+}
+
+// Stats returns a usage snapshot for every key in the pool
+func (p *KeyPool) Stats() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]KeyStats, 0, len(p.keys))
+	for _, key := range p.keys {
+		stats = append(stats, KeyStats{
+			Fingerprint:  key.fingerprint,
+			Requests:     key.requests,
+			Failures:     key.failures,
+			BenchedUntil: key.benchedUntil,
+		})
+	}
+	return stats
+}
+
+// pick selects the key for the next request. When every key is benched the one
+// released soonest is used rather than failing the request outright.
+func (p *KeyPool) pick() *apiKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.keys) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var picked *apiKey
+	switch p.selection {
+	case KeySelectionLeastUsed:
+		for _, key := range p.keys {
+			if key.benchedUntil.After(now) {
+				continue
+			}
+			if picked == nil || key.requests < picked.requests {
+				picked = key
+			}
+		}
+	default:
+		for i := range p.keys {
+			key := p.keys[(p.next+i)%len(p.keys)]
+			if key.benchedUntil.After(now) {
+				continue
+			}
+			picked = key
+			p.next = (p.next + i + 1) % len(p.keys)
+			break
+		}
+	}
+
+	if picked == nil {
+		for _, key := range p.keys {
+			if picked == nil || key.benchedUntil.Before(picked.benchedUntil) {
+				picked = key
+			}
+		}
+	}
+
+	picked.requests++
+	return picked
+}
+
+// report records the upstream verdict on a key, benching it after 401, 403 or 429
+func (p *KeyPool) report(key *apiKey, resp *http.Response) {
+	if key == nil {
+		return
+	}
+
+	var benchFor time.Duration
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		benchFor = p.benchFor
+	case http.StatusTooManyRequests:
+		benchFor = max(p.benchFor, retryAfter(resp.Header))
+	default:
+		return
+	}
+
+	p.mu.Lock()
+	key.failures++
+	key.benchedUntil = time.Now().Add(benchFor)
+	p.mu.Unlock()
+
+	log.Warn().
+		Str("key", key.fingerprint).
+		Int("status_code", resp.StatusCode).
+		Dur("bench_for", benchFor).
+		Msg("benching rarible api key")
+}
+
+// fingerprint identifies a key without revealing it
+func fingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:4])
+}
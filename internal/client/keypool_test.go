@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyPool(t *testing.T) {
+	t.Run("ShouldRotateRoundRobin", func(t *testing.T) {
+		pool := NewKeyPool([]string{"key-a", "key-b", "key-a", ""}, KeySelectionRoundRobin, time.Minute)
+
+		require.Equal(t, "key-a", pool.pick().value)
+		require.Equal(t, "key-b", pool.pick().value)
+		require.Equal(t, "key-a", pool.pick().value)
+	})
+
+	t.Run("ShouldPickLeastUsed", func(t *testing.T) {
+		pool := NewKeyPool([]string{"key-a", "key-b"}, KeySelectionLeastUsed, time.Minute)
+		pool.keys[0].requests = 5
+
+		require.Equal(t, "key-b", pool.pick().value)
+	})
+
+	t.Run("ShouldBenchRejectedKey", func(t *testing.T) {
+		pool := NewKeyPool([]string{"key-a", "key-b"}, KeySelectionRoundRobin, time.Minute)
+
+		key := pool.pick()
+		pool.report(key, &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}})
+
+		require.Equal(t, "key-b", pool.pick().value)
+		require.Equal(t, "key-b", pool.pick().value)
+
+		stats := pool.Stats()
+		require.Len(t, stats, 2)
+		require.Equal(t, uint64(1), stats[0].Failures)
+		require.True(t, stats[0].BenchedUntil.After(time.Now()))
+	})
+
+	t.Run("ShouldFallBackToSoonestReleasedKeyWhenAllBenched", func(t *testing.T) {
+		pool := NewKeyPool([]string{"key-a", "key-b"}, KeySelectionRoundRobin, time.Minute)
+		pool.keys[0].benchedUntil = time.Now().Add(time.Hour)
+		pool.keys[1].benchedUntil = time.Now().Add(time.Minute)
+
+		require.Equal(t, "key-b", pool.pick().value)
+	})
+
+	t.Run("ShouldNotExposeKeyMaterialInStats", func(t *testing.T) {
+		pool := NewKeyPool([]string{"super-secret-key"}, KeySelectionRoundRobin, time.Minute)
+
+		stats := pool.Stats()
+		require.Len(t, stats, 1)
+		require.NotContains(t, stats[0].Fingerprint, "secret")
+	})
+}
+
+func TestRaribleClient_WithKeyPool(t *testing.T) {
+	t.Run("ShouldRetireKeyAfter429", func(t *testing.T) {
+		var usedKeys []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			usedKeys = append(usedKeys, r.Header.Get("X-API-KEY"))
+			if r.Header.Get("X-API-KEY") == "key-a" {
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		pool := NewKeyPool([]string{"key-a", "key-b"}, KeySelectionRoundRobin, time.Minute)
+		client := NewRaribleClient("", server.URL, WithKeyPool(pool))
+
+		for range 3 {
+			_, err := client.GetOwnershipByID(context.Background(), "test-id")
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, []string{"key-a", "key-b", "key-b"}, usedKeys)
+	})
+}
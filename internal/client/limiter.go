@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+)
+
+const defaultThrottleWindow = time.Second
+
+// priorityLimiter bounds concurrent upstream calls while keeping a number of
+// slots reserved for interactive traffic. Batch calls wait for a shared slot,
+// background calls are shed immediately when no shared slot is free or when
+// the upstream has recently answered with 429.
+type priorityLimiter struct {
+	mu             sync.Mutex
+	capacity       int
+	reserved       int
+	inFlight       int
+	throttledUntil time.Time
+	released       chan struct{}
+}
+
+func newPriorityLimiter(capacity, reserved int) *priorityLimiter {
+	if reserved < 0 {
+		reserved = 0
+	}
+	if reserved > capacity {
+		reserved = capacity
+	}
+	return &priorityLimiter{
+		capacity: capacity,
+		reserved: reserved,
+		released: make(chan struct{}),
+	}
+}
+
+// ceiling returns the in-flight count below which a request of priority p may start
+func (l *priorityLimiter) ceiling(p Priority) int {
+	shared := l.capacity - l.reserved
+	switch p {
+	case PriorityInteractive:
+		return l.capacity
+	case PriorityBatch:
+		return shared
+	default:
+		if shared == 0 {
+			return 0
+		}
+		return max(1, shared/2)
+	}
+}
+
+// acquire blocks until a slot is available for priority p and returns a func releasing it
+func (l *priorityLimiter) acquire(ctx context.Context, p Priority) (func(), error) {
+	for {
+		l.mu.Lock()
+		throttled := time.Now().Before(l.throttledUntil)
+		if l.inFlight < l.ceiling(p) && !(p == PriorityBackground && throttled) {
+			l.inFlight++
+			l.mu.Unlock()
+			return l.release, nil
+		}
+		if p == PriorityBackground {
+			l.mu.Unlock()
+			return nil, fmt.Errorf("%w: %s request shed", businesserrors.ErrUpstreamOverloaded, p)
+		}
+		wait := l.released
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %s request timed out waiting for capacity: %w", businesserrors.ErrUpstreamOverloaded, p, ctx.Err())
+		case <-wait:
+		}
+	}
+}
+
+func (l *priorityLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+// throttle marks the upstream as under pressure for d, shedding background calls meanwhile
+func (l *priorityLimiter) throttle(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := time.Now().Add(d)
+	if until.After(l.throttledUntil) {
+		l.throttledUntil = until
+	}
+}
+
+// retryAfter parses the Retry-After header in its delay-seconds form
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return defaultThrottleWindow
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityLimiter(t *testing.T) {
+	t.Run("ShouldKeepReservedSlotsForInteractive", func(t *testing.T) {
+		limiter := newPriorityLimiter(2, 1)
+
+		release, err := limiter.acquire(context.Background(), PriorityBatch)
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = limiter.acquire(ctx, PriorityBatch)
+		require.ErrorIs(t, err, businesserrors.ErrUpstreamOverloaded)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		releaseInteractive, err := limiter.acquire(context.Background(), PriorityInteractive)
+		require.NoError(t, err)
+		releaseInteractive()
+	})
+
+	t.Run("ShouldShedBackgroundWhenSharedSlotsAreBusy", func(t *testing.T) {
+		limiter := newPriorityLimiter(3, 1)
+
+		release, err := limiter.acquire(context.Background(), PriorityBackground)
+		require.NoError(t, err)
+		defer release()
+
+		_, err = limiter.acquire(context.Background(), PriorityBackground)
+		require.ErrorIs(t, err, businesserrors.ErrUpstreamOverloaded)
+	})
+
+	t.Run("ShouldShedBackgroundWhileThrottled", func(t *testing.T) {
+		limiter := newPriorityLimiter(4, 1)
+		limiter.throttle(time.Minute)
+
+		_, err := limiter.acquire(context.Background(), PriorityBackground)
+		require.ErrorIs(t, err, businesserrors.ErrUpstreamOverloaded)
+
+		release, err := limiter.acquire(context.Background(), PriorityBatch)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("ShouldWakeWaitingRequestOnRelease", func(t *testing.T) {
+		limiter := newPriorityLimiter(1, 0)
+
+		release, err := limiter.acquire(context.Background(), PriorityInteractive)
+		require.NoError(t, err)
+
+		acquired := make(chan error, 1)
+		go func() {
+			releaseSecond, err := limiter.acquire(context.Background(), PriorityBatch)
+			if err == nil {
+				releaseSecond()
+			}
+			acquired <- err
+		}()
+
+		release()
+		select {
+		case err := <-acquired:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("waiting request was not woken up")
+		}
+	})
+}
+
+func TestRaribleClient_WithConcurrencyLimit(t *testing.T) {
+	t.Run("ShouldThrottleBackgroundAfter429", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"code":"TOO_MANY_REQUESTS","message":"rate limited"}`))
+		}))
+		defer server.Close()
+
+		client := NewRaribleClient("test-api-key", server.URL, WithConcurrencyLimit(4, 1))
+
+		ownership, err := client.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusTooManyRequests, ownership.StatusCode)
+
+		ctx := WithPriority(context.Background(), PriorityBackground)
+		_, err = client.GetOwnershipByID(ctx, "test-id")
+		require.ErrorIs(t, err, businesserrors.ErrUpstreamOverloaded)
+	})
+
+	t.Run("ShouldHoldSlotWhileDecodingBody", func(t *testing.T) {
+		unblock := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			<-unblock
+			w.Write([]byte(`{"id":"test-id"}`))
+		}))
+		defer server.Close()
+
+		client := NewRaribleClient("test-api-key", server.URL, WithConcurrencyLimit(1, 0))
+
+		first := make(chan error, 1)
+		go func() {
+			_, err := client.GetOwnershipByID(context.Background(), "test-id")
+			first <- err
+		}()
+
+		// the first response has its headers but not its body yet
+		require.Eventually(t, func() bool {
+			client := client.(*raribleClient)
+			client.limiter.mu.Lock()
+			defer client.limiter.mu.Unlock()
+			return client.limiter.inFlight == 1
+		}, time.Second, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := client.GetOwnershipByID(ctx, "test-id")
+		require.ErrorIs(t, err, businesserrors.ErrUpstreamOverloaded)
+
+		close(unblock)
+		require.NoError(t, <-first)
+
+		ownership, err := client.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, "test-id", ownership.ID)
+	})
+}
@@ -35,6 +35,36 @@ func (m *MockRaribleClient) EXPECT() *MockRaribleClientMockRecorder {
 	return m.recorder
 }
 
+// GetItemByID mocks base method.
+func (m *MockRaribleClient) GetItemByID(ctx context.Context, id string) (*model.ItemDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemByID", ctx, id)
+	ret0, _ := ret[0].(*model.ItemDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemByID indicates an expected call of GetItemByID.
+func (mr *MockRaribleClientMockRecorder) GetItemByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByID", reflect.TypeOf((*MockRaribleClient)(nil).GetItemByID), ctx, id)
+}
+
+// GetItemsByCollection mocks base method.
+func (m *MockRaribleClient) GetItemsByCollection(ctx context.Context, collectionID, continuation string, size int) (*model.ItemsDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemsByCollection", ctx, collectionID, continuation, size)
+	ret0, _ := ret[0].(*model.ItemsDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemsByCollection indicates an expected call of GetItemsByCollection.
+func (mr *MockRaribleClientMockRecorder) GetItemsByCollection(ctx, collectionID, continuation, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemsByCollection", reflect.TypeOf((*MockRaribleClient)(nil).GetItemsByCollection), ctx, collectionID, continuation, size)
+}
+
 // GetOwnershipByID mocks base method.
 func (m *MockRaribleClient) GetOwnershipByID(ctx context.Context, id string) (*model.OwnershipDTO, error) {
 	m.ctrl.T.Helper()
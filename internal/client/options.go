@@ -0,0 +1,40 @@
+package client
+
+import "net/http"
+
+// Option configures optional behaviour of the rarible client
+type Option func(*raribleClient)
+
+// WithConcurrencyLimit bounds concurrent upstream calls to capacity, keeping
+// reserved slots available only to interactive requests. A capacity of zero
+// disables the limit.
+func WithConcurrencyLimit(capacity, reserved int) Option {
+	return func(c *raribleClient) {
+		if capacity <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = newPriorityLimiter(capacity, reserved)
+	}
+}
+
+// WithKeyPool replaces the single API key with a rotating pool of keys
+func WithKeyPool(pool *KeyPool) Option {
+	return func(c *raribleClient) {
+		c.keys = pool
+	}
+}
+
+// WithDriftDetector checks successful upstream payloads against our models and reports drift
+func WithDriftDetector(detector *DriftDetector) Option {
+	return func(c *raribleClient) {
+		c.drift = detector
+	}
+}
+
+// WithTransport sends upstream requests through rt, e.g. a record/replay transport in tests
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *raribleClient) {
+		c.client.Transport = rt
+	}
+}
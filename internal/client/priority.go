@@ -0,0 +1,45 @@
+package client
+
+import "context"
+
+// Priority describes how important an upstream call is relative to others
+// sharing the same Rarible quota
+type Priority int
+
+const (
+	// PriorityInteractive is used for user-facing requests and is the default
+	PriorityInteractive Priority = iota
+	// PriorityBatch is used for bulk work that can wait for capacity
+	PriorityBatch
+	// PriorityBackground is used for refresh jobs and is shed first under pressure
+	PriorityBackground
+)
+
+type priorityCtxKey struct{}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PriorityBatch:
+		return "batch"
+	case PriorityBackground:
+		return "background"
+	default:
+		return "unknown"
+	}
+}
+
+// WithPriority returns a copy of ctx carrying the given priority
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityCtxKey{}, p)
+}
+
+// PriorityFromContext returns the priority carried by ctx, defaulting to interactive
+func PriorityFromContext(ctx context.Context) Priority {
+	p, ok := ctx.Value(priorityCtxKey{}).(Priority)
+	if !ok {
+		return PriorityInteractive
+	}
+	return p
+}
@@ -4,8 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Megidy/rarible/internal/domain/model"
@@ -17,18 +22,24 @@ const (
 
 type raribleClient struct {
 	baseRaribleUrl string
-	apiKey         string
+	keys           *KeyPool
 	client         *http.Client
+	limiter        *priorityLimiter
+	drift          *DriftDetector
 }
 
-func NewRaribleClient(apiKey string, baseRaribleUrl string) RaribleClient {
-	return &raribleClient{
+func NewRaribleClient(apiKey string, baseRaribleUrl string, opts ...Option) RaribleClient {
+	c := &raribleClient{
 		baseRaribleUrl: baseRaribleUrl,
-		apiKey:         apiKey,
+		keys:           NewKeyPool([]string{apiKey}, KeySelectionRoundRobin, defaultKeyBenchDuration),
 		client: &http.Client{
 			Timeout: httpTimeout,
 		},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // GetOwnershipByID fetches ownership data by ID
@@ -39,16 +50,14 @@ func (c *raribleClient) GetOwnershipByID(ctx context.Context, id string) (*model
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	c.setRequiredHeaders(req)
-
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var ownership model.OwnershipDTO
-	if err := json.NewDecoder(resp.Body).Decode(&ownership); err != nil {
+	if err := c.decode("ownerships", resp, &ownership); err != nil {
 		return nil, fmt.Errorf("failed to decode ownership response: %w", err)
 	}
 
@@ -57,6 +66,60 @@ func (c *raribleClient) GetOwnershipByID(ctx context.Context, id string) (*model
 	return &ownership, nil
 }
 
+// GetItemByID fetches item data by ID
+func (c *raribleClient) GetItemByID(ctx context.Context, id string) (*model.ItemDTO, error) {
+	url := fmt.Sprintf("%s/items/%s", c.baseRaribleUrl, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var item model.ItemDTO
+	if err := c.decode("items", resp, &item); err != nil {
+		return nil, fmt.Errorf("failed to decode item response: %w", err)
+	}
+
+	item.StatusCode = resp.StatusCode
+
+	return &item, nil
+}
+
+// GetItemsByCollection fetches a page of a collection's items, starting at continuation
+func (c *raribleClient) GetItemsByCollection(ctx context.Context, collectionID, continuation string, size int) (*model.ItemsDTO, error) {
+	query := url.Values{}
+	query.Set("collection", collectionID)
+	query.Set("size", strconv.Itoa(size))
+	if continuation != "" {
+		query.Set("continuation", continuation)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseRaribleUrl+"/items/byCollection?"+query.Encode(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var items model.ItemsDTO
+	if err := c.decode("items/byCollection", resp, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode items response: %w", err)
+	}
+
+	items.StatusCode = resp.StatusCode
+
+	return &items, nil
+}
+
 // GetTraitRarity returns rarity of a given trait
 func (c *raribleClient) GetTraitRarity(ctx context.Context, dto *model.TraitRarityRequestDTO) (*model.TraitRarityResponseDTO, error) {
 	url := fmt.Sprintf("%s/items/traits/rarity", c.baseRaribleUrl)
@@ -71,16 +134,14 @@ func (c *raribleClient) GetTraitRarity(ctx context.Context, dto *model.TraitRari
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	c.setRequiredHeaders(req)
-
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var traitRarity model.TraitRarityResponseDTO
-	if err := json.NewDecoder(resp.Body).Decode(&traitRarity); err != nil {
+	if err := c.decode("items/traits/rarity", resp, &traitRarity); err != nil {
 		return nil, fmt.Errorf("failed to decode trait rarity response: %w", err)
 	}
 
@@ -88,8 +149,87 @@ func (c *raribleClient) GetTraitRarity(ctx context.Context, dto *model.TraitRari
 	return &traitRarity, nil
 }
 
-func (c *raribleClient) setRequiredHeaders(req *http.Request) {
+// do sends req upstream once the limiter grants a slot for the priority carried in its context.
+// Requests carrying a caller supplied key take a slot as well and only skip the shared key pool.
+func (c *raribleClient) do(req *http.Request) (*http.Response, error) {
+	release := func() {}
+	if c.limiter != nil {
+		var err error
+		release, err = c.limiter.acquire(req.Context(), PriorityFromContext(req.Context()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var key *apiKey
+	if callerKey, ok := apiKeyFromContext(req.Context()); ok {
+		c.setCommonHeaders(req)
+		req.Header.Set("X-API-KEY", callerKey)
+	} else {
+		key = c.setRequiredHeaders(req)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	c.keys.report(key, resp)
+	if resp.StatusCode == http.StatusTooManyRequests && c.limiter != nil {
+		c.limiter.throttle(retryAfter(resp.Header))
+	}
+	// the slot is held until the caller has read and closed the body
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody releases a limiter slot once the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// decode reads the response body into target, checking successful payloads for schema drift when enabled
+func (c *raribleClient) decode(endpoint string, resp *http.Response, target any) error {
+	if c.drift == nil {
+		return json.NewDecoder(resp.Body).Decode(target)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	// type mismatches leave the rest of target populated and are reported as drift below
+	var typeErr *json.UnmarshalTypeError
+	err = json.Unmarshal(body, target)
+	if err != nil && (!errors.As(err, &typeErr) || resp.StatusCode != http.StatusOK) {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	return c.drift.check(endpoint, body, target)
+}
+
+// setRequiredHeaders sets the common headers and the API key picked from the pool, returning that key
+func (c *raribleClient) setRequiredHeaders(req *http.Request) *apiKey {
+	c.setCommonHeaders(req)
+
+	key := c.keys.pick()
+	if key != nil {
+		req.Header.Set("X-API-KEY", key.value)
+	}
+	return key
+}
+
+func (c *raribleClient) setCommonHeaders(req *http.Request) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Add("content-type", "application/json")
-	req.Header.Set("X-API-KEY", c.apiKey)
 }
@@ -123,7 +123,7 @@ func TestGetTraitRarity(t *testing.T) {
 				{
 					Key:    "Hat",
 					Value:  "Halo",
-					Rarity: "1.2",
+					Rarity: 1.2,
 				},
 			},
 		}
@@ -176,7 +176,7 @@ func TestGetTraitRarity(t *testing.T) {
 				{
 					Key:    "Hat",
 					Value:  "Halo",
-					Rarity: "1.2",
+					Rarity: 1.2,
 				},
 			},
 		}
@@ -198,3 +198,74 @@ func TestGetTraitRarity(t *testing.T) {
 		require.Equal(t, expectedStatusCode, actualStatusCode)
 	})
 }
+
+func TestGetOwnershipByID_Quantities(t *testing.T) {
+	t.Run("ShouldPass_DecodesLargeBalancesExactly", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"test-id","value":"340282366920938463463374607431768211457","lazyValue":"2"}`))
+		}))
+		defer server.Close()
+
+		client := NewRaribleClient("test-api-key", server.URL)
+
+		ownership, err := client.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, "340282366920938463463374607431768211457", ownership.Value.String())
+		require.Equal(t, "340282366920938463463374607431768211455", ownership.Minted().String())
+	})
+
+	t.Run("ShouldFail_WhenUpstreamValueIsNotNumeric", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"test-id","value":"lots","lazyValue":"0"}`))
+		}))
+		defer server.Close()
+
+		client := NewRaribleClient("test-api-key", server.URL, WithDriftDetector(NewDriftDetector(false)))
+
+		_, err := client.GetOwnershipByID(context.Background(), "test-id")
+		require.ErrorIs(t, err, model.ErrInvalidQuantity)
+	})
+}
+
+func TestGetItemsByCollection(t *testing.T) {
+	t.Run("ShouldPass_SendsPagingParams", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/items/byCollection", r.URL.Path)
+			require.Equal(t, "ETHEREUM:0x123", r.URL.Query().Get("collection"))
+			require.Equal(t, "50", r.URL.Query().Get("size"))
+			require.Equal(t, "next-page", r.URL.Query().Get("continuation"))
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(model.ItemsDTO{Continuation: "last-page", Items: []model.ItemDTO{{ID: "ETHEREUM:0x123:1"}}})
+		}))
+		defer server.Close()
+
+		client := NewRaribleClient("test-api-key", server.URL)
+
+		items, err := client.GetItemsByCollection(context.Background(), "ETHEREUM:0x123", "next-page", 50)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, items.StatusCode)
+		require.Equal(t, "last-page", items.Continuation)
+		require.Len(t, items.Items, 1)
+	})
+
+	t.Run("ShouldPass_OmitsEmptyContinuation", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.False(t, r.URL.Query().Has("continuation"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"NOT_FOUND","message":"collection not found"}`))
+		}))
+		defer server.Close()
+
+		client := NewRaribleClient("test-api-key", server.URL)
+
+		items, err := client.GetItemsByCollection(context.Background(), "ETHEREUM:0x123", "", 50)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, items.StatusCode)
+		require.Equal(t, "collection not found", items.Message)
+	})
+}
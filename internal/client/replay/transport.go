@@ -0,0 +1,335 @@
+// Package replay provides an http.RoundTripper that records upstream
+// request/response pairs to fixture files and serves them back offline
+package replay
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether the transport records live traffic or replays fixtures
+type Mode string
+
+const (
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+
+	redacted = "REDACTED"
+)
+
+// ErrNoFixture is returned in replay mode for requests no fixture matches
+var ErrNoFixture = errors.New("replay: no fixture matches request")
+
+// sensitiveHeaders are never written to fixture files
+var sensitiveHeaders = []string{"X-Api-Key", "Authorization", "X-Rarible-Api-Key"}
+
+// Request is the recorded part of an outgoing request
+type Request struct {
+	Method  string          `json:"method"`
+	Host    string          `json:"host,omitempty"`
+	Path    string          `json:"path"`
+	Query   string          `json:"query,omitempty"`
+	Headers http.Header     `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	RawBody string          `json:"rawBody,omitempty"`
+}
+
+// Response is the recorded upstream answer
+type Response struct {
+	StatusCode int             `json:"statusCode"`
+	Headers    http.Header     `json:"headers,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	RawBody    string          `json:"rawBody,omitempty"`
+}
+
+// Fixture is a single recorded request/response pair
+type Fixture struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Matcher reports whether a recorded request answers an outgoing one
+type Matcher func(recorded Request, req *http.Request, body []byte) bool
+
+// MatchMethodAndPath matches on HTTP method and URL path only
+func MatchMethodAndPath(recorded Request, req *http.Request, _ []byte) bool {
+	return recorded.Method == req.Method && recorded.Path == req.URL.Path
+}
+
+// MatchHost matches on the upstream host, so fixtures recorded against different
+// environments never answer each other. Fixtures recorded without a host match any host.
+func MatchHost(recorded Request, req *http.Request, _ []byte) bool {
+	return recorded.Host == "" || recorded.Host == req.URL.Host
+}
+
+// MatchQuery matches on the raw query string
+func MatchQuery(recorded Request, req *http.Request, _ []byte) bool {
+	return recorded.Query == req.URL.RawQuery
+}
+
+// MatchBody matches on the request body, comparing JSON bodies semantically
+func MatchBody(recorded Request, _ *http.Request, body []byte) bool {
+	if len(recorded.Body) == 0 {
+		return recorded.RawBody == string(body)
+	}
+
+	var want, got any
+	if json.Unmarshal(recorded.Body, &want) != nil || json.Unmarshal(body, &got) != nil {
+		return false
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// MatchAll combines matchers, matching only when every one of them does
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(recorded Request, req *http.Request, body []byte) bool {
+		for _, match := range matchers {
+			if !match(recorded, req, body) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// DefaultMatcher matches on method, host, path, query and body
+var DefaultMatcher = MatchAll(MatchMethodAndPath, MatchHost, MatchQuery, MatchBody)
+
+// Transport records or replays upstream traffic depending on its mode
+type Transport struct {
+	mode    Mode
+	dir     string
+	next    http.RoundTripper
+	matcher Matcher
+
+	mu       sync.Mutex
+	fixtures []Fixture
+}
+
+// NewRecorder returns a transport forwarding requests to next and saving every exchange under dir
+func NewRecorder(dir string, next http.RoundTripper) (*Transport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fixtures dir: %w", err)
+	}
+	return &Transport{mode: ModeRecord, dir: dir, next: next}, nil
+}
+
+// NewReplayer returns a transport serving responses from the fixtures under dir.
+// A nil matcher uses DefaultMatcher.
+func NewReplayer(dir string, matcher Matcher) (*Transport, error) {
+	if matcher == nil {
+		matcher = DefaultMatcher
+	}
+	fixtures, err := loadFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Transport{mode: ModeReplay, dir: dir, matcher: matcher, fixtures: fixtures}, nil
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, out, err := readBody(req)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("replay: failed to read request body: %w", err)
+	}
+
+	if t.mode == ModeReplay {
+		// nothing sends the body, but a RoundTripper must still close it
+		if out.Body != nil {
+			out.Body.Close()
+		}
+		return t.replay(req, body)
+	}
+	return t.record(out, body)
+}
+
+func (t *Transport) replay(req *http.Request, body []byte) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, fixture := range t.fixtures {
+		if t.matcher(fixture.Request, req, body) {
+			return fixture.Response.toHTTP(req), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s%s (fixtures dir %s)", ErrNoFixture, req.Method, req.URL.Host, req.URL.RequestURI(), t.dir)
+}
+
+func (t *Transport) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("replay: failed to read response body: %w", err)
+	}
+
+	fixture := Fixture{
+		Request: Request{
+			Method:  req.Method,
+			Host:    req.URL.Host,
+			Path:    req.URL.Path,
+			Query:   req.URL.RawQuery,
+			Headers: redact(req.Header),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    redact(resp.Header),
+		},
+	}
+	fixture.Request.Body, fixture.Request.RawBody = splitBody(body)
+	fixture.Response.Body, fixture.Response.RawBody = splitBody(respBody)
+
+	if err := t.save(fixture); err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// save writes fixture to a file named after the request, so re-recording overwrites it
+func (t *Transport) save(fixture Fixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("replay: failed to marshal fixture: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	path := filepath.Join(t.dir, fixtureName(fixture.Request))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("replay: failed to write fixture: %w", err)
+	}
+	t.fixtures = append(t.fixtures, fixture)
+	return nil
+}
+
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	body := []byte(r.RawBody)
+	if len(r.Body) > 0 {
+		body = r.Body
+	}
+	headers := r.Headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func loadFixtures(dir string) ([]Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fixtures: %w", err)
+	}
+
+	fixtures := make([]Fixture, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s: %w", path, err)
+		}
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("failed to decode fixture %s: %w", path, err)
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+// readBody returns the request body together with the request to send on.
+// req itself is never modified: its body is read through GetBody when set,
+// otherwise it is drained into a clone that carries the buffered copy.
+func readBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer rc.Close()
+		body, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, nil, err
+		}
+		return body, req, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, out, nil
+}
+
+func splitBody(body []byte) (json.RawMessage, string) {
+	if len(body) == 0 {
+		return nil, ""
+	}
+	if json.Valid(body) {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, body); err == nil {
+			return compact.Bytes(), ""
+		}
+	}
+	return nil, string(body)
+}
+
+func redact(headers http.Header) http.Header {
+	clone := headers.Clone()
+	for _, name := range sensitiveHeaders {
+		if clone.Get(name) != "" {
+			clone.Set(name, redacted)
+		}
+	}
+	return clone
+}
+
+func fixtureName(req Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.Host + req.Path + "?" + req.Query + "\n" + string(req.Body) + req.RawBody))
+	slug := strings.Trim(strings.NewReplacer("/", "_", ":", "_").Replace(req.Path), "_")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+	return fmt.Sprintf("%s_%s_%s.json", strings.ToLower(req.Method), slug, hex.EncodeToString(sum[:4]))
+}
@@ -0,0 +1,208 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Megidy/rarible/internal/client"
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	upstreamHost = "upstream.invalid"
+	upstreamURL  = "http://" + upstreamHost
+)
+
+// writeFixtures saves fixtures recorded against upstreamURL to a fresh directory
+func writeFixtures(t *testing.T, fixtures ...Fixture) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, fixture := range fixtures {
+		if fixture.Request.Host == "" {
+			fixture.Request.Host = upstreamHost
+		}
+		data, err := json.Marshal(fixture)
+		require.NoError(t, err)
+		err = os.WriteFile(filepath.Join(dir, fixtureName(fixture.Request)), data, 0o644)
+		require.NoError(t, err)
+	}
+	return dir
+}
+
+var (
+	ownershipFixture = Fixture{
+		Request:  Request{Method: http.MethodGet, Path: "/ownerships/test-id"},
+		Response: Response{StatusCode: http.StatusOK, Body: json.RawMessage(`{"id":"test-id","owner":"0xabc"}`)},
+	}
+	traitRarityFixture = Fixture{
+		Request: Request{
+			Method: http.MethodPost,
+			Path:   "/items/traits/rarity",
+			Body:   json.RawMessage(`{"collectionId":"ETHEREUM:0x123","properties":[{"key":"Hat","value":"Halo"}]}`),
+		},
+		Response: Response{StatusCode: http.StatusOK, Body: json.RawMessage(`{"traits":[{"key":"Hat","value":"Halo","rarity":"1.2"}]}`)},
+	}
+)
+
+func TestTransport(t *testing.T) {
+	t.Run("ShouldRecordAndRedactApiKey", func(t *testing.T) {
+		dir := t.TempDir()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/ownerships/test-id":
+				w.Write([]byte(`{"id":"test-id","owner":"0xabc"}`))
+			default:
+				w.Write([]byte(`{"traits":[{"key":"Hat","value":"Halo","rarity":"1.2"}]}`))
+			}
+		}))
+		defer server.Close()
+
+		recorder, err := NewRecorder(dir, nil)
+		require.NoError(t, err)
+		raribleClient := client.NewRaribleClient("secret-api-key", server.URL, client.WithTransport(recorder))
+
+		ownership, err := raribleClient.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, "0xabc", ownership.Owner)
+
+		_, err = raribleClient.GetTraitRarity(context.Background(), &model.TraitRarityRequestDTO{
+			CollectionID: "ETHEREUM:0x123",
+			Properties:   []model.TraitPropertyInput{{Key: "Hat", Value: "Halo"}},
+		})
+		require.NoError(t, err)
+
+		paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		require.NoError(t, err)
+		require.Len(t, paths, 2)
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.NotContains(t, string(data), "secret-api-key")
+			require.Contains(t, string(data), redacted)
+			require.Contains(t, string(data), strings.TrimPrefix(server.URL, "http://"))
+		}
+
+		// what was just recorded replays against the same upstream
+		replayer, err := NewReplayer(dir, nil)
+		require.NoError(t, err)
+		ownership, err = client.NewRaribleClient("other-api-key", server.URL, client.WithTransport(replayer)).GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, "0xabc", ownership.Owner)
+	})
+
+	t.Run("ShouldReplayRecordedFixtures", func(t *testing.T) {
+		replayer, err := NewReplayer(writeFixtures(t, ownershipFixture, traitRarityFixture), nil)
+		require.NoError(t, err)
+		raribleClient := client.NewRaribleClient("other-api-key", upstreamURL, client.WithTransport(replayer))
+
+		ownership, err := raribleClient.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, "0xabc", ownership.Owner)
+		require.Equal(t, http.StatusOK, ownership.StatusCode)
+
+		rarity, err := raribleClient.GetTraitRarity(context.Background(), &model.TraitRarityRequestDTO{
+			CollectionID: "ETHEREUM:0x123",
+			Properties:   []model.TraitPropertyInput{{Key: "Hat", Value: "Halo"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, model.Rarity(1.2), rarity.Traits[0].Rarity)
+	})
+
+	t.Run("ShouldFailOnUnmatchedRequest", func(t *testing.T) {
+		replayer, err := NewReplayer(writeFixtures(t, ownershipFixture, traitRarityFixture), nil)
+		require.NoError(t, err)
+		raribleClient := client.NewRaribleClient("other-api-key", upstreamURL, client.WithTransport(replayer))
+
+		_, err = raribleClient.GetOwnershipByID(context.Background(), "unknown-id")
+		require.ErrorIs(t, err, ErrNoFixture)
+
+		_, err = raribleClient.GetTraitRarity(context.Background(), &model.TraitRarityRequestDTO{CollectionID: "ETHEREUM:0x456"})
+		require.ErrorIs(t, err, ErrNoFixture)
+	})
+
+	t.Run("ShouldKeepEnvironmentsApart", func(t *testing.T) {
+		testnetFixture := ownershipFixture
+		testnetFixture.Request.Host = "testnet.upstream.invalid"
+		testnetFixture.Response.Body = json.RawMessage(`{"id":"test-id","owner":"0xdef"}`)
+		replayer, err := NewReplayer(writeFixtures(t, ownershipFixture, testnetFixture), nil)
+		require.NoError(t, err)
+
+		mainnet := client.NewRaribleClient("other-api-key", upstreamURL, client.WithTransport(replayer))
+		ownership, err := mainnet.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, "0xabc", ownership.Owner)
+
+		testnet := client.NewRaribleClient("other-api-key", "http://testnet.upstream.invalid", client.WithTransport(replayer))
+		ownership, err = testnet.GetOwnershipByID(context.Background(), "test-id")
+		require.NoError(t, err)
+		require.Equal(t, "0xdef", ownership.Owner)
+
+		devnet := client.NewRaribleClient("other-api-key", "http://devnet.upstream.invalid", client.WithTransport(replayer))
+		_, err = devnet.GetOwnershipByID(context.Background(), "test-id")
+		require.ErrorIs(t, err, ErrNoFixture)
+	})
+
+	t.Run("ShouldUseCustomMatcher", func(t *testing.T) {
+		matchPrefix := func(recorded Request, req *http.Request, _ []byte) bool {
+			return recorded.Method == req.Method && strings.HasPrefix(req.URL.Path, "/ownerships/")
+		}
+		replayer, err := NewReplayer(writeFixtures(t, ownershipFixture), matchPrefix)
+		require.NoError(t, err)
+		raribleClient := client.NewRaribleClient("other-api-key", upstreamURL, client.WithTransport(replayer))
+
+		ownership, err := raribleClient.GetOwnershipByID(context.Background(), "unknown-id")
+		require.NoError(t, err)
+		require.Equal(t, "0xabc", ownership.Owner)
+	})
+
+	t.Run("ShouldNotModifyRequest", func(t *testing.T) {
+		var received string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			received = string(data)
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		recorder, err := NewRecorder(t.TempDir(), nil)
+		require.NoError(t, err)
+
+		// a bare reader leaves GetBody unset, so the body has to be buffered on a clone
+		body := io.NopCloser(strings.NewReader(`{"collectionId":"ETHEREUM:0x123"}`))
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/items/traits/rarity", body)
+		require.NoError(t, err)
+		require.Nil(t, req.GetBody)
+
+		resp, err := recorder.RoundTrip(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, `{"collectionId":"ETHEREUM:0x123"}`, received)
+		require.True(t, req.Body == body)
+		require.Nil(t, req.GetBody)
+	})
+
+	t.Run("ShouldReadBodyThroughGetBody", func(t *testing.T) {
+		replayer, err := NewReplayer(writeFixtures(t, traitRarityFixture), nil)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, upstreamURL+"/items/traits/rarity",
+			strings.NewReader(`{"collectionId":"ETHEREUM:0x123","properties":[{"key":"Hat","value":"Halo"}]}`))
+		require.NoError(t, err)
+		body := req.Body
+
+		resp, err := replayer.RoundTrip(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.True(t, req.Body == body)
+	})
+}
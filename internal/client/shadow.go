@@ -0,0 +1,276 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultShadowTimeout     = 10 * time.Second
+	defaultShadowConcurrency = 4
+	defaultShadowReportLimit = 100
+)
+
+// FieldDiff is a single field whose value differs between primary and shadow responses
+type FieldDiff struct {
+	Field   string `json:"field"`
+	Primary any    `json:"primary"`
+	Shadow  any    `json:"shadow"`
+}
+
+// ShadowReport describes the outcome of one mirrored request
+type ShadowReport struct {
+	Endpoint string      `json:"endpoint"`
+	Request  string      `json:"request"`
+	At       time.Time   `json:"at"`
+	Diffs    []FieldDiff `json:"diffs,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// ShadowStats counts mirrored requests by outcome
+type ShadowStats struct {
+	Compared   uint64 `json:"compared"`
+	Matched    uint64 `json:"matched"`
+	Mismatched uint64 `json:"mismatched"`
+	Failed     uint64 `json:"failed"`
+	Skipped    uint64 `json:"skipped"`
+}
+
+// ShadowSummary is a snapshot of the mirrored request counts and most recent reports
+type ShadowSummary struct {
+	Stats   ShadowStats    `json:"stats"`
+	Reports []ShadowReport `json:"reports"`
+}
+
+// ShadowClient serves every call from the primary client and mirrors a sample
+// of successful reads to a secondary client in the background, comparing the
+// decoded responses field by field without delaying the primary response
+type ShadowClient struct {
+	primary    RaribleClient
+	secondary  RaribleClient
+	sampleRate float64
+	timeout    time.Duration
+	slots      chan struct{}
+
+	mu          sync.Mutex
+	stats       ShadowStats
+	reports     []ShadowReport
+	reportLimit int
+	wg          sync.WaitGroup
+}
+
+func NewShadowClient(primary, secondary RaribleClient, sampleRate float64) *ShadowClient {
+	return &ShadowClient{
+		primary:     primary,
+		secondary:   secondary,
+		sampleRate:  sampleRate,
+		timeout:     defaultShadowTimeout,
+		slots:       make(chan struct{}, defaultShadowConcurrency),
+		reportLimit: defaultShadowReportLimit,
+	}
+}
+
+// GetOwnershipByID fetches ownership data by ID from the primary client
+func (s *ShadowClient) GetOwnershipByID(ctx context.Context, id string) (*model.OwnershipDTO, error) {
+	ownership, err := s.primary.GetOwnershipByID(ctx, id)
+	if err != nil || ownership.StatusCode != http.StatusOK || !s.sampled() {
+		return ownership, err
+	}
+
+	primary := *ownership
+	primary.Creators = slices.Clone(ownership.Creators)
+	s.mirror(ctx, "ownerships", id, primary, func(ctx context.Context) (any, error) {
+		shadow, err := s.secondary.GetOwnershipByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return *shadow, nil
+	})
+	return ownership, nil
+}
+
+// GetItemByID fetches item data by ID from the primary client without mirroring
+func (s *ShadowClient) GetItemByID(ctx context.Context, id string) (*model.ItemDTO, error) {
+	return s.primary.GetItemByID(ctx, id)
+}
+
+// GetItemsByCollection fetches a page of a collection's items from the primary client without mirroring
+func (s *ShadowClient) GetItemsByCollection(ctx context.Context, collectionID, continuation string, size int) (*model.ItemsDTO, error) {
+	return s.primary.GetItemsByCollection(ctx, collectionID, continuation, size)
+}
+
+// GetTraitRarity returns rarity of a given trait from the primary client
+func (s *ShadowClient) GetTraitRarity(ctx context.Context, req *model.TraitRarityRequestDTO) (*model.TraitRarityResponseDTO, error) {
+	resp, err := s.primary.GetTraitRarity(ctx, req)
+	if err != nil || resp.StatusCode != http.StatusOK || !s.sampled() {
+		return resp, err
+	}
+
+	primary := *resp
+	primary.Traits = slices.Clone(resp.Traits)
+	shadowReq := *req
+	shadowReq.Properties = slices.Clone(req.Properties)
+	s.mirror(ctx, "items/traits/rarity", req.CollectionID, primary, func(ctx context.Context) (any, error) {
+		shadow, err := s.secondary.GetTraitRarity(ctx, &shadowReq)
+		if err != nil {
+			return nil, err
+		}
+		return *shadow, nil
+	})
+	return resp, nil
+}
+
+// Stats returns the number of mirrored requests by outcome
+func (s *ShadowClient) Stats() ShadowStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Reports returns the most recent mismatch and failure reports, oldest first
+func (s *ShadowClient) Reports() []ShadowReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.reports)
+}
+
+// Summary returns the current counts together with the most recent reports
+func (s *ShadowClient) Summary() ShadowSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return ShadowSummary{Stats: s.stats, Reports: slices.Clone(s.reports)}
+}
+
+// Wait blocks until every in-flight mirrored request has been compared
+func (s *ShadowClient) Wait() {
+	s.wg.Wait()
+}
+
+func (s *ShadowClient) sampled() bool {
+	return s.sampleRate > 0 && rand.Float64() < s.sampleRate
+}
+
+// mirror runs fetch in the background and records how its result compares to primary.
+// Mirrored requests are dropped rather than queued once all shadow slots are busy.
+func (s *ShadowClient) mirror(ctx context.Context, endpoint, request string, primary any, fetch func(context.Context) (any, error)) {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		s.mu.Lock()
+		s.stats.Skipped++
+		s.mu.Unlock()
+		return
+	}
+
+	// the mirrored call is ours, so it never uses, or bills, a caller supplied key
+	shadowCtx, cancel := context.WithTimeout(WithPriority(withoutAPIKey(context.WithoutCancel(ctx)), PriorityBackground), s.timeout)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.slots }()
+		defer cancel()
+
+		report := ShadowReport{Endpoint: endpoint, Request: request, At: time.Now().UTC()}
+		shadow, err := fetch(shadowCtx)
+		if err != nil {
+			report.Error = err.Error()
+		} else {
+			report.Diffs = diffFields(primary, shadow)
+		}
+		s.record(report)
+	}()
+}
+
+func (s *ShadowClient) record(report ShadowReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case report.Error != "":
+		s.stats.Failed++
+	case len(report.Diffs) == 0:
+		s.stats.Compared++
+		s.stats.Matched++
+		return
+	default:
+		s.stats.Compared++
+		s.stats.Mismatched++
+	}
+
+	s.reports = append(s.reports, report)
+	if len(s.reports) > s.reportLimit {
+		s.reports = s.reports[len(s.reports)-s.reportLimit:]
+	}
+
+	log.Warn().
+		Str("endpoint", report.Endpoint).
+		Str("request", report.Request).
+		Interface("diffs", report.Diffs).
+		Str("error", report.Error).
+		Msg("shadow response differs from primary")
+}
+
+// diffFields compares two values of the same type field by field, descending
+// into nested structs and slices and naming fields by their path
+func diffFields(primary, shadow any) []FieldDiff {
+	var diffs []FieldDiff
+	collectDiffs("", reflect.ValueOf(primary), reflect.ValueOf(shadow), &diffs)
+	return diffs
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+func collectDiffs(path string, primary, shadow reflect.Value, diffs *[]FieldDiff) {
+	switch primary.Kind() {
+	case reflect.Struct:
+		if primary.Type() == reflect.TypeOf(time.Time{}) {
+			if !primary.Interface().(time.Time).Equal(shadow.Interface().(time.Time)) {
+				*diffs = append(*diffs, FieldDiff{Field: path, Primary: primary.Interface(), Shadow: shadow.Interface()})
+			}
+			return
+		}
+		// values with their own encoding, such as model.Quantity, are compared as encoded
+		if primary.Type().Implements(jsonMarshalerType) {
+			primaryJSON, _ := json.Marshal(primary.Interface())
+			shadowJSON, _ := json.Marshal(shadow.Interface())
+			if !bytes.Equal(primaryJSON, shadowJSON) {
+				*diffs = append(*diffs, FieldDiff{Field: path, Primary: primary.Interface(), Shadow: shadow.Interface()})
+			}
+			return
+		}
+		for i := range primary.NumField() {
+			field := primary.Type().Field(i)
+			collectDiffs(joinPath(path, field.Name), primary.Field(i), shadow.Field(i), diffs)
+		}
+	case reflect.Slice:
+		if primary.Len() != shadow.Len() {
+			*diffs = append(*diffs, FieldDiff{Field: joinPath(path, "len"), Primary: primary.Len(), Shadow: shadow.Len()})
+			return
+		}
+		for i := range primary.Len() {
+			collectDiffs(fmt.Sprintf("%s[%d]", path, i), primary.Index(i), shadow.Index(i), diffs)
+		}
+	default:
+		if !reflect.DeepEqual(primary.Interface(), shadow.Interface()) {
+			*diffs = append(*diffs, FieldDiff{Field: path, Primary: primary.Interface(), Shadow: shadow.Interface()})
+		}
+	}
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
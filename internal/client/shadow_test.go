@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	client "github.com/Megidy/rarible/internal/client/mock"
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShadowClient(t *testing.T) {
+	t.Run("ShouldRecordMatchingResponses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ownership := &model.OwnershipDTO{ID: "id-123", Owner: "0xabc", StatusCode: http.StatusOK}
+		primary := client.NewMockRaribleClient(ctrl)
+		primary.EXPECT().GetOwnershipByID(gomock.Any(), "id-123").Return(ownership, nil)
+		secondary := client.NewMockRaribleClient(ctrl)
+		secondary.EXPECT().GetOwnershipByID(gomock.Any(), "id-123").Return(&model.OwnershipDTO{ID: "id-123", Owner: "0xabc", StatusCode: http.StatusOK}, nil)
+
+		shadow := NewShadowClient(primary, secondary, 1)
+
+		resp, err := shadow.GetOwnershipByID(context.Background(), "id-123")
+		require.NoError(t, err)
+		require.Equal(t, ownership, resp)
+
+		shadow.Wait()
+		require.Equal(t, ShadowStats{Compared: 1, Matched: 1}, shadow.Stats())
+		require.Empty(t, shadow.Reports())
+	})
+
+	t.Run("ShouldReportFieldDiffs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		req := &model.TraitRarityRequestDTO{CollectionID: "ETHEREUM:0x123"}
+		primary := client.NewMockRaribleClient(ctrl)
+		primary.EXPECT().GetTraitRarity(gomock.Any(), req).Return(&model.TraitRarityResponseDTO{
+			Traits:     []model.ExtendedTraitProperty{{Key: "Hat", Value: "Halo", Rarity: 1.2}},
+			StatusCode: http.StatusOK,
+		}, nil)
+		secondary := client.NewMockRaribleClient(ctrl)
+		secondary.EXPECT().GetTraitRarity(gomock.Any(), gomock.Any()).Return(&model.TraitRarityResponseDTO{
+			Traits:     []model.ExtendedTraitProperty{{Key: "Hat", Value: "Halo", Rarity: 1.3}},
+			StatusCode: http.StatusOK,
+		}, nil)
+
+		shadow := NewShadowClient(primary, secondary, 1)
+
+		_, err := shadow.GetTraitRarity(context.Background(), req)
+		require.NoError(t, err)
+
+		shadow.Wait()
+		require.Equal(t, uint64(1), shadow.Stats().Mismatched)
+		reports := shadow.Reports()
+		require.Len(t, reports, 1)
+		require.Equal(t, []FieldDiff{{Field: "Traits[0].Rarity", Primary: model.Rarity(1.2), Shadow: model.Rarity(1.3)}}, reports[0].Diffs)
+	})
+
+	t.Run("ShouldNotWaitForSlowShadow", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		primary := client.NewMockRaribleClient(ctrl)
+		primary.EXPECT().GetOwnershipByID(gomock.Any(), "id-123").Return(&model.OwnershipDTO{ID: "id-123", StatusCode: http.StatusOK}, nil)
+		secondary := client.NewMockRaribleClient(ctrl)
+		secondary.EXPECT().GetOwnershipByID(gomock.Any(), "id-123").DoAndReturn(func(ctx context.Context, id string) (*model.OwnershipDTO, error) {
+			time.Sleep(200 * time.Millisecond)
+			return nil, errors.New("shadow unavailable")
+		})
+
+		shadow := NewShadowClient(primary, secondary, 1)
+
+		start := time.Now()
+		_, err := shadow.GetOwnershipByID(context.Background(), "id-123")
+		require.NoError(t, err)
+		require.Less(t, time.Since(start), 100*time.Millisecond)
+
+		shadow.Wait()
+		require.Equal(t, uint64(1), shadow.Stats().Failed)
+		require.Equal(t, "shadow unavailable", shadow.Reports()[0].Error)
+	})
+
+	t.Run("ShouldNotMirrorWhenNotSampled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		primary := client.NewMockRaribleClient(ctrl)
+		primary.EXPECT().GetOwnershipByID(gomock.Any(), "id-123").Return(&model.OwnershipDTO{ID: "id-123", StatusCode: http.StatusOK}, nil)
+		secondary := client.NewMockRaribleClient(ctrl)
+
+		shadow := NewShadowClient(primary, secondary, 0)
+
+		_, err := shadow.GetOwnershipByID(context.Background(), "id-123")
+		require.NoError(t, err)
+		shadow.Wait()
+		require.Equal(t, ShadowStats{}, shadow.Stats())
+	})
+
+	t.Run("ShouldNotMirrorUnsuccessfulResponses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		primary := client.NewMockRaribleClient(ctrl)
+		primary.EXPECT().GetOwnershipByID(gomock.Any(), "id-123").Return(&model.OwnershipDTO{StatusCode: http.StatusNotFound}, nil)
+		secondary := client.NewMockRaribleClient(ctrl)
+
+		shadow := NewShadowClient(primary, secondary, 1)
+
+		resp, err := shadow.GetOwnershipByID(context.Background(), "id-123")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		shadow.Wait()
+		require.Equal(t, ShadowStats{}, shadow.Stats())
+	})
+
+	t.Run("ShouldMirrorWithSharedKeyInsteadOfCallerKey", func(t *testing.T) {
+		newServer := func(usedKey *string) *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				*usedKey = r.Header.Get("X-API-KEY")
+				w.Write([]byte(`{"id":"id-123"}`))
+			}))
+		}
+		var primaryKey, shadowKey string
+		primaryServer := newServer(&primaryKey)
+		defer primaryServer.Close()
+		shadowServer := newServer(&shadowKey)
+		defer shadowServer.Close()
+
+		shadow := NewShadowClient(NewRaribleClient("shared-key", primaryServer.URL), NewRaribleClient("shared-key", shadowServer.URL), 1)
+
+		_, err := shadow.GetOwnershipByID(WithAPIKey(context.Background(), "caller-key"), "id-123")
+		require.NoError(t, err)
+
+		shadow.Wait()
+		require.Equal(t, "caller-key", primaryKey)
+		require.Equal(t, "shared-key", shadowKey)
+	})
+}
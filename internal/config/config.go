@@ -1,17 +1,111 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/Megidy/rarible/internal/client"
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
 	"github.com/caarlos0/env"
 )
 
+const (
+	AppEnvProduction  = "production"
+	AppEnvDevelopment = "development"
+)
+
+const (
+	// MainnetEnvironment names the built-in mainnet upstream
+	MainnetEnvironment = "mainnet"
+	// ShadowEnvironment names the shadow upstream's client in the admin stats
+	ShadowEnvironment = "mainnet-shadow"
+)
+
 type Config struct {
+	// AppEnv is production or development, tooling such as fault injection is only available outside production
+	AppEnv string `env:"APP_ENV" envDefault:"production"`
+
 	LogLevel string `env:"LOG_LEVEL,required"`
 
 	HttpServerPort string `env:"HTTP_SERVER_PORT,required"`
 
-	RaribleApiKey string `env:"RARIBLE_API_KEY,required"`
+	RaribleApiKey string `env:"RARIBLE_API_KEY"`
+	// RaribleApiKeys is a comma separated pool of keys rotated across upstream calls
+	RaribleApiKeys []string `env:"RARIBLE_API_KEYS" envSeparator:","`
+	// RaribleApiKeySelection is either round-robin or least-used
+	RaribleApiKeySelection string `env:"RARIBLE_API_KEY_SELECTION" envDefault:"round-robin"`
+	// RaribleApiKeyBenchDuration is how long a key rejected with 401/403/429 stays out of rotation
+	RaribleApiKeyBenchDuration time.Duration `env:"RARIBLE_API_KEY_BENCH_DURATION" envDefault:"1m"`
+
+	// RaribleDefaultEnvironment is the upstream environment used when a request selects none
+	RaribleDefaultEnvironment string `env:"RARIBLE_DEFAULT_ENVIRONMENT" envDefault:"mainnet"`
+	// RaribleEnvironments lists additional upstream environments as name=baseURL pairs
+	RaribleEnvironments []string `env:"RARIBLE_ENVIRONMENTS" envSeparator:","`
+	// RaribleEnvironmentApiKeys lists environment specific keys as name=key pairs
+	RaribleEnvironmentApiKeys []string `env:"RARIBLE_ENVIRONMENT_API_KEYS" envSeparator:","`
+
+	// ShadowBaseURL is an alternate upstream that mirrors a sample of mainnet reads, empty disables shadowing
+	ShadowBaseURL string `env:"SHADOW_BASE_URL"`
+	// ShadowSampleRate is the fraction of reads mirrored to ShadowBaseURL
+	ShadowSampleRate float64 `env:"SHADOW_SAMPLE_RATE" envDefault:"0.01"`
+
+	// StrictDecoding reports upstream payloads that drift from our models
+	StrictDecoding bool `env:"STRICT_DECODING" envDefault:"false"`
+	// StrictDecodingFailOnDrift fails requests whose upstream payload drifted instead of only reporting it
+	StrictDecodingFailOnDrift bool `env:"STRICT_DECODING_FAIL_ON_DRIFT" envDefault:"false"`
+
+	// UpstreamReplayMode is record or replay to capture or serve upstream traffic from fixtures, empty disables it
+	UpstreamReplayMode string `env:"UPSTREAM_REPLAY_MODE"`
+	// UpstreamFixturesDir is where recorded upstream fixtures are stored
+	UpstreamFixturesDir string `env:"UPSTREAM_FIXTURES_DIR" envDefault:"testdata/fixtures"`
+
+	// FakeUpstream serves mainnet from an in-process fake Rarible API, no API key is needed then
+	FakeUpstream bool `env:"FAKE_UPSTREAM" envDefault:"false"`
+
+	// AllowCallerApiKey lets tenants bill upstream calls to their own key, sent in the
+	// X-Rarible-Api-Key header or stored in CALLER_TENANT_API_KEYS
+	AllowCallerApiKey bool `env:"ALLOW_CALLER_API_KEY" envDefault:"false"`
+	// CallerTenantTokens lists the tenants allowed to supply their own key as name=token pairs,
+	// the token is sent in the X-Rarible-Tenant-Token header
+	CallerTenantTokens []string `env:"CALLER_TENANT_TOKENS" envSeparator:","`
+	// CallerTenantApiKeys lists the keys stored per tenant as name=key pairs
+	CallerTenantApiKeys []string `env:"CALLER_TENANT_API_KEYS" envSeparator:","`
+
+	// AdminToken is sent in the X-Admin-Token header to reach the admin routes, empty disables them
+	AdminToken string `env:"ADMIN_TOKEN"`
+
+	// RaribleMaxConcurrentRequests bounds in-flight upstream calls, 0 disables the limit
+	RaribleMaxConcurrentRequests int `env:"RARIBLE_MAX_CONCURRENT_REQUESTS" envDefault:"16"`
+	// RaribleInteractiveReserved is the number of upstream slots only interactive requests may use,
+	// it must be less than RaribleMaxConcurrentRequests
+	RaribleInteractiveReserved int `env:"RARIBLE_INTERACTIVE_RESERVED" envDefault:"4"`
+
+	// RarityTier* are the highest trait rarities, in percent, labelled with each tier; anything more common than uncommon is common
+	RarityTierLegendary float64 `env:"RARITY_TIER_LEGENDARY" envDefault:"1"`
+	RarityTierEpic      float64 `env:"RARITY_TIER_EPIC" envDefault:"5"`
+	RarityTierRare      float64 `env:"RARITY_TIER_RARE" envDefault:"15"`
+	RarityTierUncommon  float64 `env:"RARITY_TIER_UNCOMMON" envDefault:"40"`
+
+	// RarityMaxCollectionItems bounds how many items are fetched to rank a collection, larger collections are rejected
+	RarityMaxCollectionItems int `env:"RARITY_MAX_COLLECTION_ITEMS" envDefault:"10000"`
+	// TraitStatsCacheTTL is how long collection trait stats are reused, 0 disables caching
+	TraitStatsCacheTTL time.Duration `env:"TRAIT_STATS_CACHE_TTL" envDefault:"10m"`
+
+	// TraitUnicodeForm is NFC, NFD, NFKC or NFKD to normalise trait keys and values to, empty disables it
+	TraitUnicodeForm string `env:"TRAIT_UNICODE_FORM"`
+	// TraitTrimSpace trims trait keys and values and collapses inner whitespace
+	TraitTrimSpace bool `env:"TRAIT_TRIM_SPACE" envDefault:"false"`
+	// TraitCaseFold folds trait keys and values to lower case
+	TraitCaseFold bool `env:"TRAIT_CASE_FOLD" envDefault:"false"`
+	// TraitIgnoredKeys is a comma separated list of trait keys left out of every collection
+	TraitIgnoredKeys []string `env:"TRAIT_IGNORED_KEYS" envSeparator:","`
+	// TraitRulesFile is a JSON file of per-collection aliases, numeric buckets and ignored keys, keyed by collection id
+	TraitRulesFile string `env:"TRAIT_RULES_FILE"`
 }
 
 func NewConfig() (*Config, error) {
@@ -21,5 +115,219 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	if cfg.AppEnv != AppEnvProduction && cfg.AppEnv != AppEnvDevelopment {
+		return nil, fmt.Errorf("failed to parse config: APP_ENV must be %s or %s", AppEnvProduction, AppEnvDevelopment)
+	}
+	if len(cfg.ApiKeys()) == 0 && !cfg.FakeUpstream {
+		return nil, errors.New("failed to parse config: RARIBLE_API_KEY or RARIBLE_API_KEYS is required")
+	}
+	switch client.KeySelection(cfg.RaribleApiKeySelection) {
+	case client.KeySelectionRoundRobin, client.KeySelectionLeastUsed:
+	default:
+		return nil, fmt.Errorf("failed to parse config: RARIBLE_API_KEY_SELECTION must be %s or %s", client.KeySelectionRoundRobin, client.KeySelectionLeastUsed)
+	}
+	if _, err := cfg.Environments(); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if cfg.RaribleMaxConcurrentRequests < 0 {
+		return nil, errors.New("failed to parse config: RARIBLE_MAX_CONCURRENT_REQUESTS must not be negative")
+	}
+	if cfg.RaribleInteractiveReserved < 0 {
+		return nil, errors.New("failed to parse config: RARIBLE_INTERACTIVE_RESERVED must not be negative")
+	}
+	// batch and background requests would never get a slot otherwise
+	if cfg.RaribleMaxConcurrentRequests > 0 && cfg.RaribleInteractiveReserved >= cfg.RaribleMaxConcurrentRequests {
+		return nil, errors.New("failed to parse config: RARIBLE_INTERACTIVE_RESERVED must be less than RARIBLE_MAX_CONCURRENT_REQUESTS")
+	}
+	tenants, err := cfg.CallerTenants()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if cfg.AllowCallerApiKey && len(tenants) == 0 {
+		return nil, errors.New("failed to parse config: ALLOW_CALLER_API_KEY requires CALLER_TENANT_TOKENS")
+	}
+	if err := cfg.RarityTiers().Validate(); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if cfg.RarityMaxCollectionItems <= 0 {
+		return nil, errors.New("failed to parse config: RARITY_MAX_COLLECTION_ITEMS must be positive")
+	}
+	if cfg.TraitStatsCacheTTL < 0 {
+		return nil, errors.New("failed to parse config: TRAIT_STATS_CACHE_TTL must not be negative")
+	}
+	if _, err := cfg.TraitNormalizer(); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
 	return &cfg, nil
 }
+
+// IsProduction reports whether the service runs in production mode
+func (c *Config) IsProduction() bool {
+	return c.AppEnv == AppEnvProduction
+}
+
+// ApiKeys returns every configured Rarible API key, the single key first
+func (c *Config) ApiKeys() []string {
+	keys := make([]string, 0, len(c.RaribleApiKeys)+1)
+	if c.RaribleApiKey != "" {
+		keys = append(keys, c.RaribleApiKey)
+	}
+	for _, key := range c.RaribleApiKeys {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// RarityTiers returns the configured trait rarity tier thresholds
+func (c *Config) RarityTiers() model.RarityTiers {
+	return model.RarityTiers{
+		Legendary: model.Rarity(c.RarityTierLegendary),
+		Epic:      model.Rarity(c.RarityTierEpic),
+		Rare:      model.Rarity(c.RarityTierRare),
+		Uncommon:  model.Rarity(c.RarityTierUncommon),
+	}
+}
+
+// Environment is an additional upstream Rarible environment such as testnet
+type Environment struct {
+	Name    string
+	BaseURL string
+	ApiKeys []string
+}
+
+// Environments parses RARIBLE_ENVIRONMENTS and RARIBLE_ENVIRONMENT_API_KEYS.
+// Environments without their own keys are left with an empty key list.
+// The mainnet and mainnet-shadow names are reserved for the built-in clients.
+func (c *Config) Environments() ([]Environment, error) {
+	environments := make([]Environment, 0, len(c.RaribleEnvironments))
+	index := make(map[string]int, len(c.RaribleEnvironments))
+	for _, entry := range c.RaribleEnvironments {
+		name, baseURL, err := splitPair(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RARIBLE_ENVIRONMENTS entry: %w", err)
+		}
+		if name == MainnetEnvironment || name == ShadowEnvironment {
+			return nil, fmt.Errorf("invalid RARIBLE_ENVIRONMENTS entry: environment name %q is reserved", name)
+		}
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("invalid RARIBLE_ENVIRONMENTS entry: duplicate environment %q", name)
+		}
+		index[name] = len(environments)
+		environments = append(environments, Environment{Name: name, BaseURL: baseURL})
+	}
+
+	for _, entry := range c.RaribleEnvironmentApiKeys {
+		name, key, err := splitPair(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RARIBLE_ENVIRONMENT_API_KEYS entry: %w", err)
+		}
+		i, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid RARIBLE_ENVIRONMENT_API_KEYS entry: unknown environment %q", name)
+		}
+		environments[i].ApiKeys = append(environments[i].ApiKeys, key)
+	}
+	return environments, nil
+}
+
+// CallerTenant is a tenant allowed to bill upstream calls to its own Rarible API key
+type CallerTenant struct {
+	Name   string
+	Token  string
+	ApiKey string
+}
+
+// CallerTenants parses CALLER_TENANT_TOKENS and CALLER_TENANT_API_KEYS.
+// Tenants without a stored key are left with an empty one.
+func (c *Config) CallerTenants() ([]CallerTenant, error) {
+	tenants := make([]CallerTenant, 0, len(c.CallerTenantTokens))
+	index := make(map[string]int, len(c.CallerTenantTokens))
+	tokens := make(map[string]struct{}, len(c.CallerTenantTokens))
+	for _, entry := range c.CallerTenantTokens {
+		name, token, err := splitPair(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CALLER_TENANT_TOKENS entry: %w", err)
+		}
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("invalid CALLER_TENANT_TOKENS entry: duplicate tenant %q", name)
+		}
+		if _, ok := tokens[token]; ok {
+			return nil, fmt.Errorf("invalid CALLER_TENANT_TOKENS entry: tenant %q reuses another tenant's token", name)
+		}
+		index[name] = len(tenants)
+		tokens[token] = struct{}{}
+		tenants = append(tenants, CallerTenant{Name: name, Token: token})
+	}
+
+	for _, entry := range c.CallerTenantApiKeys {
+		name, key, err := splitPair(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CALLER_TENANT_API_KEYS entry: %w", err)
+		}
+		i, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid CALLER_TENANT_API_KEYS entry: unknown tenant %q", name)
+		}
+		if tenants[i].ApiKey != "" {
+			return nil, fmt.Errorf("invalid CALLER_TENANT_API_KEYS entry: duplicate key for tenant %q", name)
+		}
+		tenants[i].ApiKey = key
+	}
+	return tenants, nil
+}
+
+// splitPair splits a name=value config entry
+func splitPair(entry string) (string, string, error) {
+	name, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if !ok || name == "" || value == "" {
+		return "", "", errors.New("expected name=value")
+	}
+	return name, value, nil
+}
+
+// TraitNormalizer returns the configured trait normalisation rules, loading the per-collection rules file if set
+func (c *Config) TraitNormalizer() (*rarity.Normalizer, error) {
+	rules := rarity.NormalizationRules{
+		UnicodeForm: c.TraitUnicodeForm,
+		TrimSpace:   c.TraitTrimSpace,
+		CaseFold:    c.TraitCaseFold,
+	}
+	for _, key := range c.TraitIgnoredKeys {
+		if key = strings.TrimSpace(key); key != "" {
+			rules.IgnoredKeys = append(rules.IgnoredKeys, key)
+		}
+	}
+
+	if c.TraitRulesFile != "" {
+		data, err := os.ReadFile(c.TraitRulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TRAIT_RULES_FILE: %w", err)
+		}
+		var collections map[string]rarity.CollectionRules
+		if err := json.Unmarshal(data, &collections); err != nil {
+			return nil, fmt.Errorf("invalid TRAIT_RULES_FILE: %w", err)
+		}
+		rules.Collections = make(map[string]rarity.CollectionRules, len(collections))
+		for collectionID, collectionRules := range collections {
+			id, err := model.ParseCollectionID(collectionID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid TRAIT_RULES_FILE: %w", err)
+			}
+			rules.Collections[id.Normalized().String()] = collectionRules
+		}
+	}
+
+	// without rules traits are passed through untouched
+	if rules.UnicodeForm == "" && !rules.TrimSpace && !rules.CaseFold && len(rules.IgnoredKeys) == 0 && len(rules.Collections) == 0 {
+		return nil, nil
+	}
+	normalizer, err := rarity.NewNormalizer(rules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trait normalization rules: %w", err)
+	}
+	return normalizer, nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Environments(t *testing.T) {
+	t.Run("ShouldAttachKeysToEnvironments", func(t *testing.T) {
+		cfg := &Config{
+			RaribleEnvironments:       []string{"testnet=https://testnet-api.rarible.org/v0.1"},
+			RaribleEnvironmentApiKeys: []string{"testnet=testnet-key"},
+		}
+
+		environments, err := cfg.Environments()
+		require.NoError(t, err)
+		require.Equal(t, []Environment{{
+			Name:    "testnet",
+			BaseURL: "https://testnet-api.rarible.org/v0.1",
+			ApiKeys: []string{"testnet-key"},
+		}}, environments)
+	})
+
+	t.Run("ShouldRejectReservedNames", func(t *testing.T) {
+		for _, name := range []string{MainnetEnvironment, ShadowEnvironment} {
+			cfg := &Config{RaribleEnvironments: []string{name + "=https://example.org"}}
+
+			_, err := cfg.Environments()
+			require.ErrorContains(t, err, "is reserved", name)
+		}
+	})
+}
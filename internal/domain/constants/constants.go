@@ -2,6 +2,7 @@ package constants
 
 const (
 	StatusRetrieved = "retrieved"
+	StatusUpdated   = "updated"
 	StatusFailed    = "failed"
 )
 
@@ -6,4 +6,5 @@ var (
 	ErrInvalidRequest     = errors.New("invalid request")
 	ErrNotFound           = errors.New("not found")
 	ErrSomethingWentWrong = errors.New("something went wrong")
+	ErrUpstreamOverloaded = errors.New("upstream overloaded")
 )
@@ -0,0 +1,151 @@
+// Package filter parses and evaluates trait filters such as
+//
+//	Hat = "Halo" AND Eyes IN ("Laser", "Red") AND NOT Background = "Blue"
+//
+// Keys and values are bare words or double quoted strings, keywords are case insensitive.
+// Comparisons are =, !=, <, <=, >, >=, IN (...) and EXISTS, combined with NOT, AND and OR
+// in that order of precedence and grouped with parentheses.
+package filter
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Op is a comparison operator
+type Op string
+
+const (
+	OpEq     Op = "="
+	OpNe     Op = "!="
+	OpLt     Op = "<"
+	OpLe     Op = "<="
+	OpGt     Op = ">"
+	OpGe     Op = ">="
+	OpIn     Op = "IN"
+	OpExists Op = "EXISTS"
+)
+
+// Ordering reports whether op compares numbers, as <, <=, > and >= do
+func (op Op) Ordering() bool {
+	return op == OpLt || op == OpLe || op == OpGt || op == OpGe
+}
+
+// Traits are an item's trait values by key
+type Traits map[string][]string
+
+// Expr is a node of a parsed filter
+type Expr interface {
+	// Match reports whether an item with traits passes the filter
+	Match(traits Traits) bool
+	// String returns the filter in canonical form, every value quoted
+	String() string
+}
+
+// Comparison tests the values of one trait key. != matches items without the key too,
+// ordering operators match items with at least one numeric value in range.
+type Comparison struct {
+	Key    string
+	Op     Op
+	Values []string
+}
+
+type And struct {
+	Left, Right Expr
+}
+
+type Or struct {
+	Left, Right Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+func (c *Comparison) Match(traits Traits) bool {
+	values, ok := traits[c.Key]
+	switch c.Op {
+	case OpExists:
+		return ok
+	case OpEq, OpIn:
+		return slices.ContainsFunc(values, func(value string) bool { return slices.Contains(c.Values, value) })
+	case OpNe:
+		return !slices.Contains(values, c.Values[0])
+	}
+
+	bound, _ := strconv.ParseFloat(c.Values[0], 64)
+	for _, value := range values {
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			continue
+		}
+		switch {
+		case c.Op == OpLt && f < bound,
+			c.Op == OpLe && f <= bound,
+			c.Op == OpGt && f > bound,
+			c.Op == OpGe && f >= bound:
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Comparison) String() string {
+	switch c.Op {
+	case OpExists:
+		return quote(c.Key) + " EXISTS"
+	case OpIn:
+		values := make([]string, 0, len(c.Values))
+		for _, value := range c.Values {
+			values = append(values, quote(value))
+		}
+		return quote(c.Key) + " IN (" + strings.Join(values, ", ") + ")"
+	}
+	return quote(c.Key) + " " + string(c.Op) + " " + quote(c.Values[0])
+}
+
+func (a *And) Match(traits Traits) bool {
+	return a.Left.Match(traits) && a.Right.Match(traits)
+}
+
+func (a *And) String() string {
+	return "(" + a.Left.String() + " AND " + a.Right.String() + ")"
+}
+
+func (o *Or) Match(traits Traits) bool {
+	return o.Left.Match(traits) || o.Right.Match(traits)
+}
+
+func (o *Or) String() string {
+	return "(" + o.Left.String() + " OR " + o.Right.String() + ")"
+}
+
+func (n *Not) Match(traits Traits) bool {
+	return !n.Expr.Match(traits)
+}
+
+func (n *Not) String() string {
+	return "NOT " + n.Expr.String()
+}
+
+// MapComparisons returns a copy of expr with every comparison replaced by f's result,
+// e.g. to normalise keys and values the way item traits are
+func MapComparisons(expr Expr, f func(Comparison) Comparison) Expr {
+	switch e := expr.(type) {
+	case *Comparison:
+		mapped := f(Comparison{Key: e.Key, Op: e.Op, Values: slices.Clone(e.Values)})
+		return &mapped
+	case *And:
+		return &And{Left: MapComparisons(e.Left, f), Right: MapComparisons(e.Right, f)}
+	case *Or:
+		return &Or{Left: MapComparisons(e.Left, f), Right: MapComparisons(e.Right, f)}
+	case *Not:
+		return &Not{Expr: MapComparisons(e.Expr, f)}
+	}
+	return expr
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
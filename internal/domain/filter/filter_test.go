@@ -0,0 +1,78 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("ShouldRespectPrecedence", func(t *testing.T) {
+		expr, err := Parse(`Hat = "Halo" AND Eyes IN ("Laser","Red") AND NOT Background = "Blue" or level >= 10`)
+		require.NoError(t, err)
+		require.Equal(t, `((("Hat" = "Halo" AND "Eyes" IN ("Laser", "Red")) AND NOT "Background" = "Blue") OR "level" >= "10")`, expr.String())
+	})
+
+	t.Run("ShouldParseGroupsQuotesAndBareWords", func(t *testing.T) {
+		expr, err := Parse(`NOT (Hat EXISTS OR "Background Color" != "Sky \"Blue\"") and eyes = laser-red`)
+		require.NoError(t, err)
+		require.Equal(t, `(NOT ("Hat" EXISTS OR "Background Color" != "Sky \"Blue\"") AND "eyes" = "laser-red")`, expr.String())
+	})
+
+	t.Run("ShouldReportErrorPositions", func(t *testing.T) {
+		for filter, expected := range map[string]SyntaxError{
+			``:                       {Pos: 1, Msg: "empty filter"},
+			`Hat = `:                 {Pos: 7, Msg: "expected trait value, got end of filter"},
+			`Hat "Halo"`:             {Pos: 5, Msg: `expected =, !=, <, <=, >, >=, IN or EXISTS, got "Halo"`},
+			`Hat = "Halo`:            {Pos: 7, Msg: "unterminated string"},
+			`Hat = Halo Eyes = Red`:  {Pos: 12, Msg: "expected AND, OR or end of filter, got 'Eyes'"},
+			`Eyes IN ("Laser" "Red"`: {Pos: 18, Msg: `expected ',' or ')', got "Red"`},
+			`(Hat = Halo`:            {Pos: 12, Msg: "expected ')', got end of filter"},
+			`Level > high`:           {Pos: 9, Msg: "> needs a number, got 'high'"},
+			`Hat ! Halo`:             {Pos: 5, Msg: "expected != after !"},
+			`Hat = AND`:              {Pos: 7, Msg: "expected trait value, got keyword AND, quote it to use it as text"},
+			`Über = Gold AND`:        {Pos: 16, Msg: "expected trait key or '(', got end of filter"},
+		} {
+			_, err := Parse(filter)
+			var syntaxErr *SyntaxError
+			require.True(t, errors.As(err, &syntaxErr), filter)
+			require.Equal(t, expected, *syntaxErr, filter)
+		}
+	})
+}
+
+func TestMatch(t *testing.T) {
+	traits := Traits{"Hat": {"Halo"}, "Eyes": {"Laser"}, "Level": {"12"}, "Tags": {"a", "b"}}
+
+	for filter, expected := range map[string]bool{
+		`Hat = Halo AND Eyes IN (Laser, Red)`: true,
+		`Hat = halo`:                          false,
+		`NOT Background = Blue`:               true,
+		`Background != Blue`:                  true,
+		`Hat != Halo`:                         false,
+		`Background EXISTS OR Tags = b`:       true,
+		`Level >= 12 AND Level < 12.5`:        true,
+		`Level > 12`:                          false,
+		`Hat > 1`:                             false,
+		`NOT (Hat = Halo OR Eyes = Red)`:      false,
+	} {
+		expr, err := Parse(filter)
+		require.NoError(t, err, filter)
+		require.Equal(t, expected, expr.Match(traits), filter)
+	}
+}
+
+func TestMapComparisons(t *testing.T) {
+	expr, err := Parse(`NOT Hat = HALO OR Eyes IN (RED)`)
+	require.NoError(t, err)
+
+	prefixed := MapComparisons(expr, func(c Comparison) Comparison {
+		for i := range c.Values {
+			c.Values[i] = "x" + c.Values[i]
+		}
+		return c
+	})
+	require.Equal(t, `(NOT "Hat" = "xHALO" OR "Eyes" IN ("xRED"))`, prefixed.String())
+	require.Equal(t, `(NOT "Hat" = "HALO" OR "Eyes" IN ("RED"))`, expr.String())
+}
@@ -0,0 +1,318 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLength bounds the length of a filter in bytes
+const MaxLength = 4096
+
+// SyntaxError is a filter that does not parse. Pos is the 1-based position, in characters, of the offending token.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// describe names the token in syntax errors
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of filter"
+	case tokenString:
+		return strconv.Quote(t.text)
+	}
+	return "'" + t.text + "'"
+}
+
+// keyword reports whether t is the unquoted keyword kw, in any case
+func (t token) keyword(kw string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, kw)
+}
+
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`()=!<>,"`, r)
+}
+
+// tokenize splits s into tokens, positions counted in characters from 1
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	pos := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		pos++
+		start := pos
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: start})
+			i += size
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: start})
+			i += size
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: start})
+			i += size
+		case r == '=':
+			tokens = append(tokens, token{kind: tokenOp, text: "=", pos: start})
+			i += size
+		case r == '!' || r == '<' || r == '>':
+			op := string(r)
+			if i+1 < len(s) && s[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &SyntaxError{Pos: start, Msg: "expected != after !"}
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: start})
+			pos += len(op) - 1
+			i += len(op)
+		case r == '"':
+			var b strings.Builder
+			i += size
+			for {
+				if i >= len(s) {
+					return nil, &SyntaxError{Pos: start, Msg: "unterminated string"}
+				}
+				r, size = utf8.DecodeRuneInString(s[i:])
+				i += size
+				pos++
+				if r == '"' {
+					break
+				}
+				if r == '\\' {
+					if i >= len(s) {
+						return nil, &SyntaxError{Pos: start, Msg: "unterminated string"}
+					}
+					r, size = utf8.DecodeRuneInString(s[i:])
+					i += size
+					pos++
+					if r != '"' && r != '\\' {
+						return nil, &SyntaxError{Pos: pos - 1, Msg: `only \" and \\ may be escaped`}
+					}
+				}
+				b.WriteRune(r)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: b.String(), pos: start})
+		default:
+			end := i + size
+			for end < len(s) {
+				next, nextSize := utf8.DecodeRuneInString(s[end:])
+				if !isWordRune(next) {
+					break
+				}
+				end += nextSize
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: s[i:end], pos: start})
+			i = end
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: pos + 1}), nil
+}
+
+// Parse parses a filter. Errors are *SyntaxError.
+func Parse(s string) (Expr, error) {
+	if len(s) > MaxLength {
+		return nil, &SyntaxError{Pos: utf8.RuneCountInString(s[:MaxLength]) + 1, Msg: fmt.Sprintf("filter is longer than %d bytes", MaxLength)}
+	}
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &SyntaxError{Pos: 1, Msg: "empty filter"}
+	}
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, p.unexpected(next, "AND, OR or end of filter")
+	}
+	return expr, nil
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	or         = and { "OR" and }
+//	and        = not { "AND" not }
+//	not        = "NOT" not | primary
+//	primary    = "(" or ")" | comparison
+//	comparison = text ( op text | "IN" "(" text { "," text } ")" | "EXISTS" )
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) unexpected(t token, expected string) error {
+	return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected %s, got %s", expected, t.describe())}
+}
+
+func (p *parser) or() (Expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("OR") {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (Expr, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("AND") {
+		p.next()
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) not() (Expr, error) {
+	if p.peek().keyword("NOT") {
+		p.next()
+		expr, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (Expr, error) {
+	if p.peek().kind == tokenLParen {
+		p.next()
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, p.unexpected(t, "')'")
+		}
+		return expr, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (Expr, error) {
+	key, err := p.text("trait key or '('")
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.next()
+	switch {
+	case t.keyword("EXISTS"):
+		return &Comparison{Key: key, Op: OpExists}, nil
+	case t.keyword("IN"):
+		return p.in(key)
+	case t.kind == tokenOp:
+		valueToken := p.peek()
+		value, err := p.text("trait value")
+		if err != nil {
+			return nil, err
+		}
+		op := Op(t.text)
+		if op != OpEq && op != OpNe {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, &SyntaxError{Pos: valueToken.pos, Msg: fmt.Sprintf("%s needs a number, got %s", op, valueToken.describe())}
+			}
+		}
+		return &Comparison{Key: key, Op: op, Values: []string{value}}, nil
+	}
+	return nil, p.unexpected(t, "=, !=, <, <=, >, >=, IN or EXISTS")
+}
+
+func (p *parser) in(key string) (Expr, error) {
+	if t := p.next(); t.kind != tokenLParen {
+		return nil, p.unexpected(t, "'(' after IN")
+	}
+	comparison := &Comparison{Key: key, Op: OpIn}
+	for {
+		value, err := p.text("trait value")
+		if err != nil {
+			return nil, err
+		}
+		comparison.Values = append(comparison.Values, value)
+
+		t := p.next()
+		if t.kind == tokenRParen {
+			return comparison, nil
+		}
+		if t.kind != tokenComma {
+			return nil, p.unexpected(t, "',' or ')'")
+		}
+	}
+}
+
+// text consumes a trait key or value: a quoted string or a word that is not a keyword
+func (p *parser) text(expected string) (string, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return t.text, nil
+	case tokenWord:
+		for _, kw := range []string{"AND", "OR", "NOT", "IN", "EXISTS"} {
+			if t.keyword(kw) {
+				return "", &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected %s, got keyword %s, quote it to use it as text", expected, t.text)}
+			}
+		}
+		return t.text, nil
+	}
+	return "", p.unexpected(t, expected)
+}
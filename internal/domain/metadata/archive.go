@@ -0,0 +1,297 @@
+// Package metadata reads folders of ERC-721 token metadata JSON files, as creators
+// ship them before reveal, and extracts every token's traits from its OpenSea attributes
+package metadata
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+)
+
+var (
+	// ErrUnsupportedArchive is returned for data that is neither a zip, a tar nor a gzipped tar
+	ErrUnsupportedArchive = errors.New("unsupported archive, expected zip, tar or tar.gz")
+	// ErrTooLarge is returned when an archive exceeds its Limits
+	ErrTooLarge = errors.New("archive too large")
+	// ErrNoTokens is returned when an archive holds no readable metadata file
+	ErrNoTokens = errors.New("archive holds no token metadata")
+)
+
+// Limits bound what is read from an archive, so compressed bombs cannot exhaust memory.
+// Every tar entry counts towards them, as it has to be decompressed to be skipped,
+// while zip entries and folder files that are no metadata are never read and do not count.
+type Limits struct {
+	MaxFiles     int
+	MaxFileSize  int64
+	MaxTotalSize int64
+}
+
+// DefaultLimits returns limits fitting collections of up to maxFiles tokens
+func DefaultLimits(maxFiles int) Limits {
+	return Limits{MaxFiles: maxFiles, MaxFileSize: 1 << 20, MaxTotalSize: 512 << 20}
+}
+
+// Token is the metadata of one token, its id taken from its file name without a .json extension
+type Token struct {
+	ID     string
+	Name   string
+	Traits []rarity.Trait
+}
+
+// Skipped is a file that was not read as token metadata
+type Skipped struct {
+	File   string
+	Reason string
+}
+
+// Archive holds the tokens read from an archive, sorted by id, numerically when they are numbers
+type Archive struct {
+	Tokens  []Token
+	Skipped []Skipped
+}
+
+// ReadArchive reads the metadata files of a zip, tar or gzipped tar archive
+func ReadArchive(data []byte, limits Limits) (*Archive, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")), bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnsupportedArchive, err)
+		}
+		return readFS(zr, limits)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnsupportedArchive, err)
+		}
+		defer gr.Close()
+		return readTar(gr, limits)
+	case len(data) > 262 && string(data[257:262]) == "ustar":
+		return readTar(bytes.NewReader(data), limits)
+	}
+	return nil, ErrUnsupportedArchive
+}
+
+// readFS reads every metadata file of fsys, such as an unpacked metadata folder
+func readFS(fsys fs.FS, limits Limits) (*Archive, error) {
+	r := newReader(limits)
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if name != "." && ignored(name) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if _, ok := metadataFile(name); !ok {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if err := r.count(info.Size()); err != nil {
+			return err
+		}
+
+		return r.read(name, info.Size(), func() (io.ReadCloser, error) { return fsys.Open(name) })
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.archive()
+}
+
+func readTar(tr io.Reader, limits Limits) (*Archive, error) {
+	r := newReader(limits)
+	reader := tar.NewReader(tr)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnsupportedArchive, err)
+		}
+		// skipped entries are decompressed all the same, so they count too, at their declared size
+		if err := r.count(header.Size); err != nil {
+			return nil, err
+		}
+		if _, ok := metadataFile(header.Name); header.Typeflag != tar.TypeReg || !ok {
+			continue
+		}
+
+		err = r.read(header.Name, header.Size, func() (io.ReadCloser, error) { return io.NopCloser(reader), nil })
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r.archive()
+}
+
+type reader struct {
+	limits  Limits
+	total   int64
+	files   int
+	tokens  map[string]Token
+	skipped []Skipped
+}
+
+func newReader(limits Limits) *reader {
+	return &reader{limits: limits, tokens: make(map[string]Token)}
+}
+
+// metadataFile returns the cleaned name of a file that looks like token metadata
+func metadataFile(name string) (string, bool) {
+	name = strings.TrimPrefix(path.Clean(name), "./")
+	if ignoredPath(name) {
+		return "", false
+	}
+	// images and other files shipped alongside the metadata are left alone
+	ext := path.Ext(path.Base(name))
+	return name, ext == "" || strings.EqualFold(ext, ".json")
+}
+
+// count adds a file of the given declared size to the totals the limits bound
+func (r *reader) count(size int64) error {
+	r.files++
+	if r.files > r.limits.MaxFiles {
+		return fmt.Errorf("%w: more than %d files", ErrTooLarge, r.limits.MaxFiles)
+	}
+	r.total += max(size, 0)
+	if r.total > r.limits.MaxTotalSize {
+		return fmt.Errorf("%w: more than %d bytes uncompressed", ErrTooLarge, r.limits.MaxTotalSize)
+	}
+	return nil
+}
+
+// read parses the metadata file name of the given size, already counted
+func (r *reader) read(name string, size int64, open func() (io.ReadCloser, error)) error {
+	name, _ = metadataFile(name)
+	base := path.Base(name)
+	ext := path.Ext(base)
+	if size > r.limits.MaxFileSize {
+		r.skipped = append(r.skipped, Skipped{File: name, Reason: fmt.Sprintf("larger than %d bytes", r.limits.MaxFileSize)})
+		return nil
+	}
+
+	f, err := open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// sizes in headers can lie, the read itself is bounded too
+	data, err := io.ReadAll(io.LimitReader(f, r.limits.MaxFileSize+1))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrUnsupportedArchive, name, err)
+	}
+	if int64(len(data)) > r.limits.MaxFileSize {
+		r.skipped = append(r.skipped, Skipped{File: name, Reason: fmt.Sprintf("larger than %d bytes", r.limits.MaxFileSize)})
+		return nil
+	}
+
+	id := strings.TrimSuffix(base, ext)
+	if _, ok := r.tokens[id]; ok {
+		r.skipped = append(r.skipped, Skipped{File: name, Reason: "duplicate token id " + id})
+		return nil
+	}
+	token, err := parseToken(id, data)
+	if err != nil {
+		r.skipped = append(r.skipped, Skipped{File: name, Reason: err.Error()})
+		return nil
+	}
+	r.tokens[id] = token
+	return nil
+}
+
+func (r *reader) archive() (*Archive, error) {
+	if len(r.tokens) == 0 {
+		return nil, ErrNoTokens
+	}
+	archive := &Archive{Tokens: make([]Token, 0, len(r.tokens)), Skipped: r.skipped}
+	for _, token := range r.tokens {
+		archive.Tokens = append(archive.Tokens, token)
+	}
+	slices.SortFunc(archive.Tokens, func(a, b Token) int { return model.CompareTokenIDs(a.ID, b.ID) })
+	slices.SortFunc(archive.Skipped, func(a, b Skipped) int { return cmp.Compare(a.File, b.File) })
+	return archive, nil
+}
+
+// tokenMetadata is the part of the ERC-721 metadata JSON schema, with OpenSea's attributes, that is read
+type tokenMetadata struct {
+	Name       string `json:"name"`
+	Attributes []struct {
+		TraitType string          `json:"trait_type"`
+		Value     json.RawMessage `json:"value"`
+	} `json:"attributes"`
+}
+
+func parseToken(id string, data []byte) (Token, error) {
+	var meta tokenMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return Token{}, fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	token := Token{ID: id, Name: meta.Name, Traits: make([]rarity.Trait, 0, len(meta.Attributes))}
+	for _, attribute := range meta.Attributes {
+		value, ok := attributeValue(attribute.Value)
+		// attributes without a type or a value describe nothing that can be counted
+		if attribute.TraitType == "" || !ok {
+			continue
+		}
+		token.Traits = append(token.Traits, rarity.Trait{Key: attribute.TraitType, Value: value})
+	}
+	return token, nil
+}
+
+// attributeValue returns a string, number or boolean attribute value as text
+func attributeValue(raw json.RawMessage) (string, bool) {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if len(raw) == 0 || decoder.Decode(&value) != nil {
+		return "", false
+	}
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// ignored reports hidden files and folders and the resource forks macOS adds to zips
+func ignored(name string) bool {
+	base := path.Base(name)
+	return strings.HasPrefix(base, ".") || base == "__MACOSX"
+}
+
+func ignoredPath(name string) bool {
+	for _, part := range strings.Split(path.Clean(name), "/") {
+		if part != "." && part != ".." && ignored(part) {
+			return true
+		}
+	}
+	return false
+}
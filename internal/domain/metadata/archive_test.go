@@ -0,0 +1,142 @@
+package metadata
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Megidy/rarible/internal/domain/rarity"
+	"github.com/stretchr/testify/require"
+)
+
+var testFiles = map[string]string{
+	"metadata/1.json":  `{"name":"#1","attributes":[{"trait_type":"Hat","value":"Cap"},{"trait_type":"Level","value":5,"display_type":"number"}]}`,
+	"metadata/10":      `{"name":"#10","attributes":[{"trait_type":"Hat","value":"Crown"},{"trait_type":"Shiny","value":true},{"value":"untyped"}]}`,
+	"metadata/2.json":  `{"name":"#2","attributes":[]}`,
+	"metadata/3.json":  `not json`,
+	"metadata/1.png":   "image",
+	"__MACOSX/1.json":  "resource fork",
+	"metadata/.hidden": "{}",
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func tarGzArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func requireTestArchive(t *testing.T, archive *Archive) {
+	t.Helper()
+
+	require.Len(t, archive.Tokens, 3)
+	require.Equal(t, Token{ID: "1", Name: "#1", Traits: []rarity.Trait{{Key: "Hat", Value: "Cap"}, {Key: "Level", Value: "5"}}}, archive.Tokens[0])
+	require.Equal(t, Token{ID: "2", Name: "#2", Traits: []rarity.Trait{}}, archive.Tokens[1])
+	require.Equal(t, Token{ID: "10", Name: "#10", Traits: []rarity.Trait{{Key: "Hat", Value: "Crown"}, {Key: "Shiny", Value: "true"}}}, archive.Tokens[2])
+
+	require.Len(t, archive.Skipped, 1)
+	require.Equal(t, "metadata/3.json", archive.Skipped[0].File)
+	require.Contains(t, archive.Skipped[0].Reason, "invalid metadata")
+}
+
+func TestReadArchive(t *testing.T) {
+	limits := DefaultLimits(100)
+
+	t.Run("ShouldReadZip", func(t *testing.T) {
+		archive, err := ReadArchive(zipArchive(t, testFiles), limits)
+		require.NoError(t, err)
+		requireTestArchive(t, archive)
+	})
+
+	t.Run("ShouldReadTarGz", func(t *testing.T) {
+		archive, err := ReadArchive(tarGzArchive(t, testFiles), limits)
+		require.NoError(t, err)
+		requireTestArchive(t, archive)
+	})
+
+	t.Run("ShouldReadFolder", func(t *testing.T) {
+		fsys := fstest.MapFS{}
+		for name, content := range testFiles {
+			fsys[name] = &fstest.MapFile{Data: []byte(content)}
+		}
+		archive, err := readFS(fsys, limits)
+		require.NoError(t, err)
+		requireTestArchive(t, archive)
+	})
+
+	t.Run("ShouldEnforceLimits", func(t *testing.T) {
+		_, err := ReadArchive(zipArchive(t, testFiles), Limits{MaxFiles: 2, MaxFileSize: 1 << 10, MaxTotalSize: 1 << 20})
+		require.ErrorIs(t, err, ErrTooLarge)
+
+		archive, err := ReadArchive(zipArchive(t, testFiles), Limits{MaxFiles: 10, MaxFileSize: 40, MaxTotalSize: 1 << 20})
+		require.NoError(t, err)
+		require.Len(t, archive.Tokens, 1)
+		require.Len(t, archive.Skipped, 3)
+	})
+
+	t.Run("ShouldCountSkippedTarEntries", func(t *testing.T) {
+		files := map[string]string{
+			"metadata/1.json":    `{"name":"#1"}`,
+			"metadata/1.png":     strings.Repeat("x", 1<<10),
+			"metadata/2.png":     "image",
+			"__MACOSX/1.json":    "resource fork",
+			"metadata/.DS_Store": "",
+		}
+		limits := Limits{MaxFiles: 5, MaxFileSize: 1 << 10, MaxTotalSize: 2 << 10}
+		archive, err := ReadArchive(tarGzArchive(t, files), limits)
+		require.NoError(t, err)
+		require.Len(t, archive.Tokens, 1)
+
+		limits.MaxFiles = 4
+		_, err = ReadArchive(tarGzArchive(t, files), limits)
+		require.ErrorIs(t, err, ErrTooLarge)
+
+		limits.MaxFiles, limits.MaxTotalSize = 5, 1<<10
+		_, err = ReadArchive(tarGzArchive(t, files), limits)
+		require.ErrorIs(t, err, ErrTooLarge)
+
+		// the same files zipped are not decompressed when skipped
+		archive, err = ReadArchive(zipArchive(t, files), Limits{MaxFiles: 1, MaxFileSize: 1 << 10, MaxTotalSize: 1 << 10})
+		require.NoError(t, err)
+		require.Len(t, archive.Tokens, 1)
+	})
+
+	t.Run("ShouldSkipDuplicateTokenIDs", func(t *testing.T) {
+		archive, err := ReadArchive(zipArchive(t, map[string]string{"a/1.json": `{}`, "b/1": `{}`}), limits)
+		require.NoError(t, err)
+		require.Len(t, archive.Tokens, 1)
+		require.Contains(t, archive.Skipped[0].Reason, "duplicate token id 1")
+	})
+
+	t.Run("ShouldRejectOtherData", func(t *testing.T) {
+		_, err := ReadArchive([]byte(`{"name":"#1"}`), limits)
+		require.ErrorIs(t, err, ErrUnsupportedArchive)
+
+		_, err = ReadArchive(zipArchive(t, map[string]string{"1.png": "image"}), limits)
+		require.ErrorIs(t, err, ErrNoTokens)
+	})
+}
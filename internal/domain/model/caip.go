@@ -0,0 +1,275 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	caipSegmentSeparator = "/"
+	caipPartSeparator    = ":"
+)
+
+// caipChains maps CAIP-2 chain ids to the blockchains they identify
+var caipChains = map[string]Blockchain{
+	"eip155:1":          BlockchainEthereum,
+	"eip155:137":        BlockchainPolygon,
+	"eip155:13371":      BlockchainImmutableX,
+	"eip155:5000":       BlockchainMantle,
+	"eip155:42161":      BlockchainArbitrum,
+	"eip155:8453":       BlockchainBase,
+	"eip155:324":        BlockchainZkSync,
+	"eip155:88888":      BlockchainChiliz,
+	"eip155:1890":       BlockchainLightlink,
+	"eip155:1380012617": BlockchainRari,
+	"solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp": BlockchainSolana,
+	"tezos:NetXdQprcVkpaWU":                   BlockchainTezos,
+	"flow:mainnet":                            BlockchainFlow,
+}
+
+// caipAssetNamespaces is the asset namespace used when rendering CAIP-19 ids.
+// Rarible ids do not carry the token standard, so EVM assets are rendered as
+// erc721; any namespace is accepted when parsing.
+var caipAssetNamespaces = map[addressFamily]string{
+	familyEVM:    "erc721",
+	familySolana: "token",
+	familyTezos:  "fa2",
+	familyFlow:   "nft",
+}
+
+// isCAIP reports whether s is in CAIP form. Rarible ids never contain a slash.
+func isCAIP(s string) bool {
+	return strings.Contains(s, caipSegmentSeparator)
+}
+
+// ParseCAIP2 parses a CAIP-2 chain id such as eip155:1
+func ParseCAIP2(s string) (Blockchain, error) {
+	blockchain, ok := caipChains[s]
+	if !ok {
+		return "", &IDError{Kind: "caip-2 chain id", Input: s, Reason: "unsupported chain id"}
+	}
+	return blockchain, nil
+}
+
+// CAIP2 returns the CAIP-2 chain id of the blockchain
+func (b Blockchain) CAIP2() (string, bool) {
+	for chainID, blockchain := range caipChains {
+		if blockchain == b {
+			return chainID, true
+		}
+	}
+	return "", false
+}
+
+// CAIP10 returns the CAIP-10 account id of the address, e.g. eip155:1:0x...
+func (a Address) CAIP10() (string, bool) {
+	chainID, ok := a.Blockchain.CAIP2()
+	if !ok {
+		return "", false
+	}
+	return chainID + caipPartSeparator + a.Value, true
+}
+
+// CAIP19 returns the CAIP-19 asset id of the collection, e.g. eip155:1/erc721:0x...
+func (id CollectionID) CAIP19() (string, bool) {
+	return caipAsset(id.Blockchain, id.Contract, "")
+}
+
+// CAIP19 returns the CAIP-19 asset id of the item, e.g. eip155:1/erc721:0x.../123
+func (id ItemID) CAIP19() (string, bool) {
+	return caipAsset(id.Blockchain, id.Contract, id.TokenID)
+}
+
+func caipAsset(blockchain Blockchain, contract, tokenID string) (string, bool) {
+	chainID, ok := blockchain.CAIP2()
+	if !ok {
+		return "", false
+	}
+	asset := chainID + caipSegmentSeparator + caipAssetNamespaces[blockchainFamilies[blockchain]] + caipPartSeparator + contract
+	if tokenID != "" {
+		asset += caipSegmentSeparator + tokenID
+	}
+	return asset, true
+}
+
+// CAIPIDs returns the CAIP counterparts of the collection id, nil when its blockchain has no CAIP-2 id
+func (id CollectionID) CAIPIDs() *CAIPIDsDTO {
+	chainID, ok := id.Blockchain.CAIP2()
+	if !ok {
+		return nil
+	}
+	collection, _ := id.CAIP19()
+	return &CAIPIDsDTO{Chain: chainID, Collection: collection}
+}
+
+// CAIPIDs returns the CAIP counterparts of the item id, nil when its blockchain has no CAIP-2 id
+func (id ItemID) CAIPIDs() *CAIPIDsDTO {
+	chainID, ok := id.Blockchain.CAIP2()
+	if !ok {
+		return nil
+	}
+	ids := &CAIPIDsDTO{Chain: chainID}
+	ids.Asset, _ = id.CAIP19()
+	if collection, ok := id.Collection(); ok {
+		ids.Collection, _ = collection.CAIP19()
+	}
+	return ids
+}
+
+// CAIPIDs returns the CAIP counterparts of the ownership id, nil when its blockchain has no CAIP-2 id
+func (id OwnershipID) CAIPIDs() *CAIPIDsDTO {
+	ids := id.Item.CAIPIDs()
+	if ids == nil {
+		return nil
+	}
+	ids.Owner, _ = Address{Blockchain: id.Item.Blockchain, Value: id.Owner}.CAIP10()
+	return ids
+}
+
+// CAIP19 returns the CAIP form of the ownership: the item's CAIP-19 asset id
+// followed by the owner's CAIP-10 account id, e.g. eip155:1/erc721:0x.../123/eip155:1:0x...
+func (id OwnershipID) CAIP19() (string, bool) {
+	asset, ok := id.Item.CAIP19()
+	if !ok {
+		return "", false
+	}
+	owner, _ := Address{Blockchain: id.Item.Blockchain, Value: id.Owner}.CAIP10()
+	return asset + caipSegmentSeparator + owner, true
+}
+
+// CAIPAsset is what a CAIP-19 asset id refers to. Item is nil when the id
+// names a whole collection, Collection is nil for Solana items.
+type CAIPAsset struct {
+	Collection *CollectionID
+	Item       *ItemID
+}
+
+// ParseCAIP19 parses a CAIP-19 asset id such as eip155:1/erc721:0x.../123
+func ParseCAIP19(s string) (CAIPAsset, error) {
+	blockchain, reference, tokenID, err := parseCAIPAsset("caip-19 asset id", s)
+	if err != nil {
+		return CAIPAsset{}, err
+	}
+	raribleID := joinRaribleID(blockchain, reference, tokenID)
+
+	if tokenID == "" && blockchain.hasTokenID() {
+		collection, err := ParseCollectionID(raribleID)
+		if err != nil {
+			return CAIPAsset{}, caipError("caip-19 asset id", s, err)
+		}
+		return CAIPAsset{Collection: &collection}, nil
+	}
+
+	item, err := ParseItemID(raribleID)
+	if err != nil {
+		return CAIPAsset{}, caipError("caip-19 asset id", s, err)
+	}
+	asset := CAIPAsset{Item: &item}
+	if collection, ok := item.Collection(); ok {
+		asset.Collection = &collection
+	}
+	return asset, nil
+}
+
+// parseCAIPCollectionID parses the CAIP-19 form of a collection id
+func parseCAIPCollectionID(s string) (CollectionID, error) {
+	blockchain, reference, tokenID, err := parseCAIPAsset("collection id", s)
+	if err != nil {
+		return CollectionID{}, err
+	}
+	if tokenID != "" {
+		return CollectionID{}, &IDError{Kind: "collection id", Input: s, Reason: "unexpected token id, did you pass an item id?"}
+	}
+	id, err := ParseCollectionID(joinRaribleID(blockchain, reference, ""))
+	if err != nil {
+		return CollectionID{}, caipError("collection id", s, err)
+	}
+	return id, nil
+}
+
+// parseCAIPItemID parses the CAIP-19 form of an item id
+func parseCAIPItemID(s string) (ItemID, error) {
+	blockchain, reference, tokenID, err := parseCAIPAsset("item id", s)
+	if err != nil {
+		return ItemID{}, err
+	}
+	id, err := ParseItemID(joinRaribleID(blockchain, reference, tokenID))
+	if err != nil {
+		return ItemID{}, caipError("item id", s, err)
+	}
+	return id, nil
+}
+
+// parseCAIPOwnershipID parses an item's CAIP-19 asset id followed by the
+// owner as a CAIP-10 account id or a bare address
+func parseCAIPOwnershipID(s string) (OwnershipID, error) {
+	cut := strings.LastIndex(s, caipSegmentSeparator)
+	asset, owner := s[:cut], s[cut+1:]
+
+	item, err := parseCAIPItemID(asset)
+	if err != nil {
+		return OwnershipID{}, caipError("ownership id", s, err)
+	}
+
+	if strings.Contains(owner, caipPartSeparator) {
+		i := strings.LastIndex(owner, caipPartSeparator)
+		blockchain, err := ParseCAIP2(owner[:i])
+		if err != nil {
+			return OwnershipID{}, caipError("ownership id", s, err)
+		}
+		if blockchain != item.Blockchain {
+			return OwnershipID{}, &IDError{Kind: "ownership id", Input: s, Reason: "owner account is on a different chain than the asset"}
+		}
+		owner = owner[i+1:]
+	}
+
+	id, err := ParseOwnershipID(item.String() + idPartSeparator + owner)
+	if err != nil {
+		return OwnershipID{}, caipError("ownership id", s, err)
+	}
+	return id, nil
+}
+
+// parseCAIPAsset splits chain_id/asset_namespace:asset_reference[/token_id]
+func parseCAIPAsset(kind, s string) (Blockchain, string, string, error) {
+	parts := strings.Split(s, caipSegmentSeparator)
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", "", "", &IDError{Kind: kind, Input: s, Reason: "expected chain_id/asset_namespace:asset_reference[/token_id]"}
+	}
+
+	blockchain, ok := caipChains[parts[0]]
+	if !ok {
+		return "", "", "", &IDError{Kind: kind, Input: s, Reason: "unsupported chain id " + parts[0]}
+	}
+
+	_, reference, ok := strings.Cut(parts[1], caipPartSeparator)
+	if !ok || reference == "" {
+		return "", "", "", &IDError{Kind: kind, Input: s, Reason: "expected asset_namespace:asset_reference"}
+	}
+
+	tokenID := ""
+	if len(parts) == 3 {
+		if parts[2] == "" {
+			return "", "", "", &IDError{Kind: kind, Input: s, Reason: "token id must not be empty"}
+		}
+		tokenID = parts[2]
+	}
+	return blockchain, reference, tokenID, nil
+}
+
+func joinRaribleID(blockchain Blockchain, reference, tokenID string) string {
+	segments := []string{string(blockchain), reference}
+	if tokenID != "" {
+		segments = append(segments, tokenID)
+	}
+	return strings.Join(segments, idPartSeparator)
+}
+
+// caipError reports a failure to parse the Rarible form of a CAIP id against the original input
+func caipError(kind, input string, err error) error {
+	var idErr *IDError
+	if errors.As(err, &idErr) {
+		return &IDError{Kind: kind, Input: input, Reason: idErr.Reason}
+	}
+	return err
+}
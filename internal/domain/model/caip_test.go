@@ -0,0 +1,126 @@
+package model
+
+import (
+	"testing"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCAIP19(t *testing.T) {
+	t.Run("ShouldParseItemsAndCollections", func(t *testing.T) {
+		asset, err := ParseCAIP19("eip155:1/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d/771769")
+		require.NoError(t, err)
+		require.Equal(t, "ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:771769", asset.Item.String())
+		require.Equal(t, "ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d", asset.Collection.String())
+
+		asset, err = ParseCAIP19("eip155:137/erc1155:0x2953399124f0cbb46d2cbacd8a89cf0599974963")
+		require.NoError(t, err)
+		require.Nil(t, asset.Item)
+		require.Equal(t, "POLYGON:0x2953399124f0cbb46d2cbacd8a89cf0599974963", asset.Collection.String())
+
+		asset, err = ParseCAIP19("solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp/nft:7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+		require.NoError(t, err)
+		require.Nil(t, asset.Collection)
+		require.Equal(t, "SOLANA:7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU", asset.Item.String())
+	})
+
+	t.Run("ShouldRejectMalformedIDs", func(t *testing.T) {
+		cases := map[string]string{
+			"eip155:1": "expected chain_id/asset_namespace:asset_reference",
+			"eip155:999/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d": "unsupported chain id",
+			"eip155:1/erc721":         "expected asset_namespace:asset_reference",
+			"eip155:1/erc721:0x123/1": "contract: expected a 0x prefixed 20 byte hex address",
+			"eip155:1/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d/abc": "token id",
+		}
+		for input, reason := range cases {
+			_, err := ParseCAIP19(input)
+			require.ErrorIs(t, err, businesserrors.ErrInvalidRequest, input)
+			require.ErrorContains(t, err, reason, input)
+			require.ErrorContains(t, err, "caip-19 asset id", input)
+		}
+	})
+}
+
+func TestCAIPRoundTrip(t *testing.T) {
+	t.Run("ShouldConvertRaribleIDsToCAIPAndBack", func(t *testing.T) {
+		ownership, err := ParseOwnershipID("ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:123:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+		require.NoError(t, err)
+
+		caip, ok := ownership.CAIP19()
+		require.True(t, ok)
+		require.Equal(t, "eip155:1/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d/123/eip155:1:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10", caip)
+
+		parsed, err := ParseOwnershipID(caip)
+		require.NoError(t, err)
+		require.Equal(t, ownership, parsed)
+
+		item, ok := ownership.Item.CAIP19()
+		require.True(t, ok)
+		parsedItem, err := ParseItemID(item)
+		require.NoError(t, err)
+		require.Equal(t, ownership.Item, parsedItem)
+
+		collection, _ := ownership.Item.Collection()
+		caipCollection, ok := collection.CAIP19()
+		require.True(t, ok)
+		require.Equal(t, "eip155:1/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d", caipCollection)
+		parsedCollection, err := ParseCollectionID(caipCollection)
+		require.NoError(t, err)
+		require.Equal(t, collection, parsedCollection)
+	})
+
+	t.Run("ShouldAcceptBareOwnerAfterCAIPAsset", func(t *testing.T) {
+		id, err := ParseOwnershipID("solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp/token:7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU/9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+		require.NoError(t, err)
+		require.Equal(t, "SOLANA:7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU:9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", id.String())
+	})
+
+	t.Run("ShouldReturnCAIPIDs", func(t *testing.T) {
+		id, err := ParseOwnershipID("TEZOS:KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton:7:tz1burnburnburnburnburnburnburjAYjjX")
+		require.NoError(t, err)
+		require.Equal(t, &CAIPIDsDTO{
+			Chain:      "tezos:NetXdQprcVkpaWU",
+			Collection: "tezos:NetXdQprcVkpaWU/fa2:KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton",
+			Asset:      "tezos:NetXdQprcVkpaWU/fa2:KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton/7",
+			Owner:      "tezos:NetXdQprcVkpaWU:tz1burnburnburnburnburnburnburjAYjjX",
+		}, id.CAIPIDs())
+	})
+
+	t.Run("ShouldParseCAIP2Chains", func(t *testing.T) {
+		blockchain, err := ParseBlockchain("eip155:137")
+		require.NoError(t, err)
+		require.Equal(t, BlockchainPolygon, blockchain)
+
+		chainID, ok := BlockchainBase.CAIP2()
+		require.True(t, ok)
+		require.Equal(t, "eip155:8453", chainID)
+
+		id, err := NewOwnershipID("eip155:1", "0x06012c8cf97bead5deae237070f9587f8e7a266d", "123", "0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+		require.NoError(t, err)
+		require.Equal(t, BlockchainEthereum, id.Item.Blockchain)
+	})
+
+	t.Run("ShouldRejectMismatchedCAIPIDs", func(t *testing.T) {
+		cases := map[string]func(string) error{
+			"eip155:1/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d/123/eip155:137:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10": func(s string) error {
+				_, err := ParseOwnershipID(s)
+				return err
+			},
+			"eip155:1/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d/123": func(s string) error {
+				_, err := ParseCollectionID(s)
+				return err
+			},
+			"eip155:1/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d": func(s string) error {
+				_, err := ParseItemID(s)
+				return err
+			},
+		}
+		for input, parse := range cases {
+			require.ErrorIs(t, parse(input), businesserrors.ErrInvalidRequest, input)
+		}
+
+		_, err := ParseBlockchain("eip155:999")
+		require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+	})
+}
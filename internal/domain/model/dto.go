@@ -1,12 +1,41 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
 
 type TraitRarityRequestDTO struct {
+	// CollectionID is a Rarible collection id or its CAIP-19 form
 	CollectionID string               `json:"collectionId"`
 	Properties   []TraitPropertyInput `json:"properties"`
+
+	TraitRarityOptions
+}
+
+// TraitRarityOptions are sorting and filtering options applied by this service and never sent upstream
+type TraitRarityOptions struct {
+	Sort      string   `json:"-" query:"sort"`
+	Order     string   `json:"-" query:"order"`
+	MinRarity *float64 `json:"-" query:"minRarity"`
+	MaxRarity *float64 `json:"-" query:"maxRarity"`
+	Tiers     []string `json:"-" query:"tier"`
 }
 
+// Trait rarity sort keys
+const (
+	TraitSortRequest = "request"
+	TraitSortRarity  = "rarity"
+	TraitSortKey     = "key"
+)
+
+// Sort orders
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
 type TraitPropertyInput struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -23,7 +52,13 @@ type TraitRarityResponseDTO struct {
 type ExtendedTraitProperty struct {
 	Key    string `json:"key"`
 	Value  string `json:"value"`
-	Rarity string `json:"rarity"`
+	Rarity Rarity `json:"rarity" swaggertype:"string" example:"1.2"`
+	// Rank, Percentile and Tier are computed by this service and not sent by upstream.
+	// Rank is 1 for the rarest trait in the request, ties share a rank.
+	Rank int `json:"rank,omitempty"`
+	// Percentile is the share of traits in the request at least as common as this one, the rarest is 100
+	Percentile float64 `json:"percentile,omitempty"`
+	Tier       string  `json:"tier,omitempty"`
 }
 
 type CreatorDTO struct {
@@ -39,12 +74,498 @@ type OwnershipDTO struct {
 	Collection    string       `json:"collection"`
 	TokenID       string       `json:"tokenId"`
 	Owner         string       `json:"owner"`
-	Value         string       `json:"value"`
+	Value         Quantity     `json:"value" swaggertype:"string" example:"1"`
 	CreatedAt     time.Time    `json:"createdAt"`
 	LastUpdatedAt time.Time    `json:"lastUpdatedAt"`
 	Creators      []CreatorDTO `json:"creators"`
-	LazyValue     string       `json:"lazyValue"`
+	LazyValue     Quantity     `json:"lazyValue" swaggertype:"string" example:"0"`
+	// MintedValue is computed as Value minus LazyValue and not sent by upstream, so it is nil until computed
+	MintedValue *Quantity   `json:"mintedValue,omitempty" swaggertype:"string" example:"1"`
+	CAIP        *CAIPIDsDTO `json:"caip,omitempty"`
+	Code        string      `json:"code,omitempty"`
+	Message     string      `json:"message,omitempty"`
+	StatusCode  int         `json:"-"`
+}
+
+type MetaAttributeDTO struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+type ItemMetaDTO struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Attributes  []MetaAttributeDTO `json:"attributes"`
+}
+
+type ItemDTO struct {
+	ID         string       `json:"id"`
+	Blockchain string       `json:"blockchain"`
+	Collection string       `json:"collection,omitempty"`
+	Contract   string       `json:"contract,omitempty"`
+	TokenID    string       `json:"tokenId,omitempty"`
+	Creators   []CreatorDTO `json:"creators"`
+	LazySupply Quantity     `json:"lazySupply" swaggertype:"string" example:"0"`
+	Supply     Quantity     `json:"supply" swaggertype:"string" example:"1"`
+	// MintedSupply is computed as Supply minus LazySupply and not sent by upstream, so it is nil until computed
+	MintedSupply  *Quantity    `json:"mintedSupply,omitempty" swaggertype:"string" example:"1"`
+	MintedAt      time.Time    `json:"mintedAt"`
+	LastUpdatedAt time.Time    `json:"lastUpdatedAt"`
+	Deleted       bool         `json:"deleted"`
+	Meta          *ItemMetaDTO `json:"meta,omitempty"`
+	CAIP          *CAIPIDsDTO  `json:"caip,omitempty"`
 	Code          string       `json:"code,omitempty"`
 	Message       string       `json:"message,omitempty"`
 	StatusCode    int          `json:"-"`
 }
+
+type ItemsDTO struct {
+	Total        int       `json:"total,omitempty"`
+	Continuation string    `json:"continuation,omitempty"`
+	Items        []ItemDTO `json:"items"`
+	Code         string    `json:"code,omitempty"`
+	Message      string    `json:"message,omitempty"`
+	StatusCode   int       `json:"-"`
+}
+
+type TraitValueCountDTO struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type TraitDTO struct {
+	Key    TraitValueCountDTO   `json:"key"`
+	Values []TraitValueCountDTO `json:"values"`
+}
+
+type TraitsDTO struct {
+	Continuation string     `json:"continuation,omitempty"`
+	Traits       []TraitDTO `json:"traits"`
+	Code         string     `json:"code,omitempty"`
+	Message      string     `json:"message,omitempty"`
+	StatusCode   int        `json:"-"`
+}
+
+type ResolveRequestDTO struct {
+	Input   string `query:"input"`
+	Owner   string `query:"owner"`
+	Include bool   `query:"include"`
+	CAIP    bool   `query:"caip"`
+}
+
+type ResolveResponseDTO struct {
+	Input        string `json:"input"`
+	Source       string `json:"source"`
+	Blockchain   string `json:"blockchain"`
+	CollectionID string `json:"collectionId,omitempty"`
+	ItemID       string `json:"itemId,omitempty"`
+	OwnershipID  string `json:"ownershipId,omitempty"`
+	// Owner is the holder, as BLOCKCHAIN:address, named by an input that names no item
+	Owner     string        `json:"owner,omitempty"`
+	Item      *ItemDTO      `json:"item,omitempty"`
+	Ownership *OwnershipDTO `json:"ownership,omitempty"`
+	CAIP      *CAIPIDsDTO   `json:"caip,omitempty"`
+}
+
+// CAIPIDsDTO holds the chain-agnostic counterparts of Rarible ids
+type CAIPIDsDTO struct {
+	// Chain is the CAIP-2 chain id
+	Chain string `json:"chain"`
+	// Collection and Asset are CAIP-19 asset ids
+	Collection string `json:"collection,omitempty"`
+	Asset      string `json:"asset,omitempty"`
+	// Owner is a CAIP-10 account id
+	Owner string `json:"owner,omitempty"`
+}
+
+// Minted returns the part of the ownership that is minted on chain rather than lazily minted
+func (o *OwnershipDTO) Minted() Quantity {
+	return o.Value.Sub(o.LazyValue).Max(Quantity{})
+}
+
+// Minted returns the part of the supply that is minted on chain rather than lazily minted
+func (i *ItemDTO) Minted() Quantity {
+	return i.Supply.Sub(i.LazySupply).Max(Quantity{})
+}
+
+type RarityRankingsRequestDTO struct {
+	CollectionID string `json:"-"`
+	// Method is the scoring method items are ranked by, information_content when empty
+	Method string `query:"method"`
+	Offset int    `query:"offset"`
+	Limit  int    `query:"limit"`
+	// MissingTraits and TraitCount default to true when not set
+	MissingTraits *bool `query:"missingTraits"`
+	TraitCount    *bool `query:"traitCount"`
+	// Filter keeps only the items matching a trait filter such as Hat = "Halo" AND NOT Eyes IN ("Red"), ranks stay collection wide
+	Filter string `query:"filter"`
+}
+
+// Rarity rankings page limits
+const (
+	DefaultRarityRankingsLimit = 100
+	MaxRarityRankingsLimit     = 1000
+)
+
+type RarityRankingsResponseDTO struct {
+	CollectionID string `json:"collectionId"`
+	Method       string `json:"method"`
+	Total        int    `json:"total"`
+	// Matched is the number of items matching the filter, Total without one
+	Matched int             `json:"matched"`
+	Items   []ItemRarityDTO `json:"items"`
+}
+
+// ItemRarityDTO is an item's rarity within its collection. Rank 1 is the rarest item, ties share a rank.
+type ItemRarityDTO struct {
+	ItemID  string `json:"itemId"`
+	TokenID string `json:"tokenId,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Rank    int    `json:"rank"`
+	// Percentile is the share of the collection ranked at or below this item, the rarest is 100
+	Percentile float64 `json:"percentile"`
+	// Score is the item's score under the requested method
+	Score  float64         `json:"score"`
+	Scores RarityScoresDTO `json:"scores"`
+	Ranks  RarityRanksDTO  `json:"ranks"`
+}
+
+type RarityScoresDTO struct {
+	InformationContent float64 `json:"informationContent"`
+	RarityScore        float64 `json:"rarityScore"`
+	Statistical        float64 `json:"statistical"`
+}
+
+type RarityRanksDTO struct {
+	InformationContent int `json:"informationContent"`
+	RarityScore        int `json:"rarityScore"`
+	Statistical        int `json:"statistical"`
+}
+
+type ItemRarityRequestDTO struct {
+	ItemID string `json:"-"`
+	// Method is the scoring method the breakdown is given for, information_content when empty
+	Method string `query:"method"`
+	// MissingTraits and TraitCount default to true when not set
+	MissingTraits *bool `query:"missingTraits"`
+	TraitCount    *bool `query:"traitCount"`
+}
+
+// ItemRarityBreakdownDTO explains an item's rank by how much each of its traits adds to its score
+type ItemRarityBreakdownDTO struct {
+	CollectionID string `json:"collectionId"`
+	Method       string `json:"method"`
+	// Total is the number of items in the collection
+	Total int `json:"total"`
+	ItemRarityDTO
+	// Traits are sorted by share, the trait with the most impact on the score first
+	Traits []TraitContributionDTO `json:"traits"`
+}
+
+type TraitContributionDTO struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// Count is the number of items in the collection with the trait
+	Count int `json:"count"`
+	// Rarity is the share of the collection's items with the trait, in percent
+	Rarity Rarity `json:"rarity" swaggertype:"string" example:"1.2"`
+	Tier   string `json:"tier"`
+	// UpstreamRarity is the rarity Rarible reports for the trait, when available
+	UpstreamRarity *Rarity `json:"upstreamRarity,omitempty" swaggertype:"string" example:"1.2"`
+	// Synthetic is set for the missing trait and trait count meta-traits
+	Synthetic bool `json:"synthetic,omitempty"`
+	// Contribution is the trait's term of the score under the requested method
+	Contribution float64 `json:"contribution"`
+	// Share is the percentage of the score the trait accounts for
+	Share float64 `json:"share"`
+}
+
+type ItemTraitRarityRequestDTO struct {
+	ItemID string `json:"-"`
+
+	TraitRarityOptions
+}
+
+// Item trait statuses, telling why an item may have no annotated traits
+const (
+	ItemTraitsStatusOK           = "ok"
+	ItemTraitsStatusNoAttributes = "no_attributes"
+	ItemTraitsStatusUnrevealed   = "unrevealed"
+)
+
+type ItemTraitRarityResponseDTO struct {
+	ItemID       string `json:"itemId"`
+	CollectionID string `json:"collectionId"`
+	Name         string `json:"name,omitempty"`
+	// Status is unrevealed when the item's metadata is missing or a pre-reveal placeholder,
+	// no_attributes when it has none; traits are empty in both cases
+	Status string                  `json:"status"`
+	Traits []ExtendedTraitProperty `json:"traits"`
+}
+
+type TraitCombinationsRequestDTO struct {
+	CollectionID string `json:"-"`
+	// Size is the number of traits combined, 2 for pairs or 3 for triples
+	Size  int `query:"size"`
+	Limit int `query:"limit"`
+	// Filter counts combinations among the items matching a trait filter only
+	Filter string `query:"filter"`
+}
+
+// Trait combination limits
+const (
+	DefaultTraitCombinationSize  = 2
+	MaxTraitCombinationSize      = 3
+	DefaultTraitCombinationLimit = 20
+	MaxTraitCombinationLimit     = 1000
+)
+
+type TraitCombinationsResponseDTO struct {
+	CollectionID string `json:"collectionId"`
+	Size         int    `json:"size"`
+	// Total is the number of items in the collection, or matching the filter when one is set
+	Total int `json:"total"`
+	// Combinations are the rarest combinations found, rarest first
+	Combinations []TraitCombinationDTO `json:"combinations"`
+}
+
+type TraitCombinationDTO struct {
+	Traits []TraitPropertyInput `json:"traits"`
+	// Count is the number of items having every trait of the combination
+	Count int `json:"count"`
+	// Rarity is the share of the collection's items having the combination, in percent
+	Rarity Rarity `json:"rarity" swaggertype:"string" example:"0.4"`
+	Tier   string `json:"tier"`
+}
+
+type TraitCombinationRarityDTO struct {
+	CollectionID string `json:"collectionId"`
+	Total        int    `json:"total"`
+	TraitCombinationDTO
+	// ExpectedRarity is the combination's rarity if its traits occurred independently,
+	// a Rarity well below it means the traits rarely appear together
+	ExpectedRarity Rarity `json:"expectedRarity" swaggertype:"string" example:"1.2"`
+	// Individual holds the rarity of every trait of the combination on its own
+	Individual []ExtendedTraitProperty `json:"individual"`
+}
+
+type TraitStatsRequestDTO struct {
+	CollectionID string `json:"-"`
+	// Format is json or csv, json when empty
+	Format string `query:"format"`
+	// LongTail is the rarity, in percent, at or below which a value counts as long tail, DefaultLongTailRarity when not set
+	LongTail *float64 `query:"longTail"`
+	// Filter describes the items matching a trait filter only
+	Filter string `query:"filter"`
+}
+
+// Trait stats formats
+const (
+	TraitStatsFormatJSON = "json"
+	TraitStatsFormatCSV  = "csv"
+)
+
+// DefaultLongTailRarity is the rarity, in percent, at or below which a trait value is long tail
+const DefaultLongTailRarity = 1
+
+// TraitStatsResponseDTO describes how trait keys and values are distributed over a collection
+type TraitStatsResponseDTO struct {
+	CollectionID string `json:"collectionId,omitempty"`
+	// Items is the number of items in the collection, or matching the filter when one is set
+	Items int `json:"items"`
+	// Keys and Values are the number of distinct trait keys and key/value pairs
+	Keys   int `json:"keys"`
+	Values int `json:"values"`
+	// ComputedAt is when the stats were computed, they are cached per collection for a while
+	ComputedAt time.Time `json:"computedAt"`
+	// TraitCounts tells how many items have each number of traits
+	TraitCounts []TraitCountBucketDTO `json:"traitCounts"`
+	TraitKeys   []TraitKeyStatsDTO    `json:"traitKeys"`
+}
+
+type TraitCountBucketDTO struct {
+	Traits int `json:"traits"`
+	Items  int `json:"items"`
+}
+
+type TraitKeyStatsDTO struct {
+	Key string `json:"key"`
+	// Values is the number of distinct values of the key
+	Values int `json:"values"`
+	// Items is the number of items with the key, Missing the number without it
+	Items         int    `json:"items"`
+	Missing       int    `json:"missing"`
+	MissingRarity Rarity `json:"missingRarity" swaggertype:"string" example:"12.5"`
+	// Entropy is the Shannon entropy of the key's values in bits, items missing the key counting as one more value
+	Entropy float64 `json:"entropy"`
+	// NormalizedEntropy is Entropy over its maximum for as many values, from 0 for a constant key to 1 for a uniform one
+	NormalizedEntropy float64 `json:"normalizedEntropy"`
+	// LongTail are the values at or below the long tail rarity
+	LongTail []string `json:"longTail"`
+	// Histogram counts the key's values and the items holding them by rarity tier, legendary first
+	Histogram []TierBucketDTO `json:"histogram"`
+	// Distribution are the key's values, most common first
+	Distribution []TraitValueStatsDTO `json:"distribution"`
+}
+
+type TierBucketDTO struct {
+	Tier   string `json:"tier"`
+	Values int    `json:"values"`
+	Items  int    `json:"items"`
+}
+
+type TraitValueStatsDTO struct {
+	Value  string `json:"value"`
+	Count  int    `json:"count"`
+	Rarity Rarity `json:"rarity" swaggertype:"string" example:"1.2"`
+	Tier   string `json:"tier"`
+}
+
+type SimilarItemsRequestDTO struct {
+	ItemID string `json:"-"`
+	// Method is jaccard or weighted, weighted when empty
+	Method string `query:"method"`
+	// Exclude are trait keys left out of the comparison
+	Exclude []string `query:"exclude"`
+	Limit   int      `query:"limit"`
+	// Filter keeps only the similar items matching a trait filter
+	Filter string `query:"filter"`
+}
+
+// Similar items limits
+const (
+	DefaultSimilarItemsLimit = 10
+	MaxSimilarItemsLimit     = 100
+)
+
+type SimilarItemsResponseDTO struct {
+	ItemID       string `json:"itemId"`
+	CollectionID string `json:"collectionId"`
+	Method       string `json:"method"`
+	// Total is the number of items sharing at least one trait with the item and matching the filter
+	Total int              `json:"total"`
+	Items []SimilarItemDTO `json:"items"`
+}
+
+type SimilarItemDTO struct {
+	ItemID  string `json:"itemId"`
+	TokenID string `json:"tokenId,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// Similarity goes from 0 for nothing in common to 1 for the same traits
+	Similarity float64              `json:"similarity"`
+	Shared     []TraitPropertyInput `json:"shared"`
+	// Differing holds, by key, the values only one of the two items has
+	Differing []TraitDifferenceDTO `json:"differing"`
+}
+
+type TraitDifferenceDTO struct {
+	Key string `json:"key"`
+	// Values are the item's own values of the key and SimilarValues the similar item's, either may be empty
+	Values        []string `json:"values"`
+	SimilarValues []string `json:"similarValues"`
+}
+
+// OpenRarityRankingsDTO is a collection's rarity ranking in the OpenRarity JSON layout: information
+// content scores over every trait, missing traits and the trait count meta-trait included
+type OpenRarityRankingsDTO struct {
+	CollectionID    string `json:"collection_id,omitempty"`
+	Chain           string `json:"chain,omitempty"`
+	ContractAddress string `json:"contract_address,omitempty"`
+	// TotalSupply is the number of tokens ranked
+	TotalSupply int                  `json:"total_supply"`
+	Tokens      []OpenRarityTokenDTO `json:"tokens"`
+}
+
+type OpenRarityTokenDTO struct {
+	TokenID OpenRarityTokenID `json:"token_id" swaggertype:"string" example:"1"`
+	// Rank 1 is the rarest token, ties share a rank
+	Rank  int     `json:"rank"`
+	Score float64 `json:"score"`
+	// UniqueAttributeCount is the number of the token's traits no other token has
+	UniqueAttributeCount int `json:"unique_attribute_count"`
+}
+
+// OpenRarityTokenID is a token id, encoded as a string and decoded from a string or a JSON number,
+// as tools exporting OpenRarity data write either
+type OpenRarityTokenID string
+
+func (id *OpenRarityTokenID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = OpenRarityTokenID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return &json.UnmarshalTypeError{Value: jsonKind(data), Type: reflect.TypeOf(*id)}
+	}
+	*id = OpenRarityTokenID(n.String())
+	return nil
+}
+
+type OpenRarityDiffRequestDTO struct {
+	CollectionID string `json:"-"`
+	// Rankings is the external ranking compared with ours
+	Rankings OpenRarityRankingsDTO
+}
+
+// OpenRarityDiffDTO compares an imported ranking with the one computed here, token by token
+type OpenRarityDiffDTO struct {
+	CollectionID string `json:"collectionId"`
+	// Total is the number of tokens in the collection and Imported the number in the imported ranking
+	Total    int `json:"total"`
+	Imported int `json:"imported"`
+	// Compared is the number of tokens in both, Identical the number of those ranked the same
+	Compared  int `json:"compared"`
+	Identical int `json:"identical"`
+	// MeanAbsRankDelta and MaxAbsRankDelta summarise how far the compared tokens' ranks are apart
+	MeanAbsRankDelta float64 `json:"meanAbsRankDelta"`
+	MaxAbsRankDelta  int     `json:"maxAbsRankDelta"`
+	// Missing are the collection's token ids absent from the import, Unknown the imported ones not in the collection
+	Missing []string `json:"missing"`
+	Unknown []string `json:"unknown"`
+	// Tokens are the compared tokens whose ranks differ, largest difference first
+	Tokens []OpenRarityTokenDiffDTO `json:"tokens"`
+}
+
+type OpenRarityTokenDiffDTO struct {
+	TokenID      string `json:"tokenId"`
+	ItemID       string `json:"itemId"`
+	Rank         int    `json:"rank"`
+	ImportedRank int    `json:"importedRank"`
+	// RankDelta is the imported rank minus ours, positive when the import ranks the token as more common
+	RankDelta     int     `json:"rankDelta"`
+	Score         float64 `json:"score"`
+	ImportedScore float64 `json:"importedScore"`
+}
+
+type MetadataRarityRequestDTO struct {
+	// Archive is a zip, tar or tar.gz of ERC-721 metadata JSON files named by token id
+	Archive []byte `json:"-"`
+	// CollectionID optionally selects the collection's trait normalisation rules
+	CollectionID string `query:"collection"`
+	// Method is the scoring method tokens are ranked by, information_content when empty
+	Method string `query:"method"`
+	// MissingTraits and TraitCount default to true when not set
+	MissingTraits *bool `query:"missingTraits"`
+	TraitCount    *bool `query:"traitCount"`
+	// LongTail is the rarity, in percent, at or below which a value counts as long tail, DefaultLongTailRarity when not set
+	LongTail *float64 `query:"longTail"`
+}
+
+// MetadataRarityResponseDTO ranks the tokens of a metadata archive, computed without any upstream call
+type MetadataRarityResponseDTO struct {
+	Method string `json:"method"`
+	// Total is the number of tokens read from the archive
+	Total int `json:"total"`
+	// Tokens are every token, rarest first; their ItemID is the token id
+	Tokens     []ItemRarityDTO       `json:"tokens"`
+	TraitStats TraitStatsResponseDTO `json:"traitStats"`
+	// Skipped are the archive's files that could not be read as token metadata
+	Skipped []SkippedFileDTO `json:"skipped"`
+}
+
+type SkippedFileDTO struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
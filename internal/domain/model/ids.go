@@ -0,0 +1,389 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+)
+
+// Blockchain is a Rarible blockchain name such as ETHEREUM or SOLANA
+type Blockchain string
+
+const (
+	BlockchainEthereum   Blockchain = "ETHEREUM"
+	BlockchainPolygon    Blockchain = "POLYGON"
+	BlockchainImmutableX Blockchain = "IMMUTABLEX"
+	BlockchainMantle     Blockchain = "MANTLE"
+	BlockchainArbitrum   Blockchain = "ARBITRUM"
+	BlockchainBase       Blockchain = "BASE"
+	BlockchainZkSync     Blockchain = "ZKSYNC"
+	BlockchainChiliz     Blockchain = "CHILIZ"
+	BlockchainLightlink  Blockchain = "LIGHTLINK"
+	BlockchainRari       Blockchain = "RARI"
+	BlockchainSolana     Blockchain = "SOLANA"
+	BlockchainTezos      Blockchain = "TEZOS"
+	BlockchainFlow       Blockchain = "FLOW"
+)
+
+// addressFamily groups blockchains sharing address and token id formats
+type addressFamily int
+
+const (
+	familyEVM addressFamily = iota
+	familySolana
+	familyTezos
+	familyFlow
+)
+
+var blockchainFamilies = map[Blockchain]addressFamily{
+	BlockchainEthereum:   familyEVM,
+	BlockchainPolygon:    familyEVM,
+	BlockchainImmutableX: familyEVM,
+	BlockchainMantle:     familyEVM,
+	BlockchainArbitrum:   familyEVM,
+	BlockchainBase:       familyEVM,
+	BlockchainZkSync:     familyEVM,
+	BlockchainChiliz:     familyEVM,
+	BlockchainLightlink:  familyEVM,
+	BlockchainRari:       familyEVM,
+	BlockchainSolana:     familySolana,
+	BlockchainTezos:      familyTezos,
+	BlockchainFlow:       familyFlow,
+}
+
+var (
+	evmAddressRe     = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	solanaAddressRe  = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{32,44}$`)
+	tezosAddressRe   = regexp.MustCompile(`^(tz1|tz2|tz3|KT1)[1-9A-HJ-NP-Za-km-z]{33}$`)
+	tezosContractRe  = regexp.MustCompile(`^KT1[1-9A-HJ-NP-Za-km-z]{33}$`)
+	flowAddressRe    = regexp.MustCompile(`^0x[0-9a-fA-F]{16}$`)
+	flowContractRe   = regexp.MustCompile(`^A\.[0-9a-fA-F]{16}\.[A-Za-z_][A-Za-z0-9_]*$`)
+	decimalTokenIDRe = regexp.MustCompile(`^[0-9]{1,78}$`)
+)
+
+const (
+	idPartSeparator      = ":"
+	supportedBlockchains = "ETHEREUM, POLYGON, IMMUTABLEX, MANTLE, ARBITRUM, BASE, ZKSYNC, CHILIZ, LIGHTLINK, RARI, SOLANA, TEZOS or FLOW"
+)
+
+// IDError describes why an identifier could not be parsed. It unwraps to
+// businesserrors.ErrInvalidRequest so callers can map it to a 400.
+type IDError struct {
+	Kind   string
+	Input  string
+	Reason string
+}
+
+func (e *IDError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Kind, e.Input, e.Reason)
+}
+
+func (e *IDError) Unwrap() error {
+	return businesserrors.ErrInvalidRequest
+}
+
+// ParseBlockchain parses a blockchain name case-insensitively, or a CAIP-2 chain id such as eip155:1
+func ParseBlockchain(s string) (Blockchain, error) {
+	if strings.Contains(s, caipPartSeparator) {
+		return ParseCAIP2(s)
+	}
+	return parseBlockchain("blockchain", s, s)
+}
+
+func parseBlockchain(kind, input, s string) (Blockchain, error) {
+	blockchain := Blockchain(strings.ToUpper(s))
+	if _, ok := blockchainFamilies[blockchain]; !ok {
+		return "", &IDError{Kind: kind, Input: input, Reason: fmt.Sprintf("unknown blockchain %q, expected %s", s, supportedBlockchains)}
+	}
+	return blockchain, nil
+}
+
+// validateAddress checks an account address against the blockchain's syntax
+func (b Blockchain) validateAddress(address string) string {
+	switch blockchainFamilies[b] {
+	case familySolana:
+		if !solanaAddressRe.MatchString(address) {
+			return "expected a base58 encoded 32 byte Solana address"
+		}
+	case familyTezos:
+		if !tezosAddressRe.MatchString(address) {
+			return "expected a tz1, tz2, tz3 or KT1 Tezos address"
+		}
+	case familyFlow:
+		if !flowAddressRe.MatchString(address) {
+			return "expected a 0x prefixed 8 byte hex Flow address"
+		}
+	default:
+		if !evmAddressRe.MatchString(address) {
+			return "expected a 0x prefixed 20 byte hex address"
+		}
+	}
+	return ""
+}
+
+// validateContract checks a contract or collection address against the blockchain's syntax
+func (b Blockchain) validateContract(contract string) string {
+	switch blockchainFamilies[b] {
+	case familyTezos:
+		if !tezosContractRe.MatchString(contract) {
+			return "expected a KT1 Tezos contract address"
+		}
+	case familyFlow:
+		if !flowContractRe.MatchString(contract) {
+			return "expected a Flow contract such as A.0b2a3299cc857e29.TopShot"
+		}
+	default:
+		return b.validateAddress(contract)
+	}
+	return ""
+}
+
+// hasTokenID reports whether item ids on the blockchain carry a token id after the contract
+func (b Blockchain) hasTokenID() bool {
+	return blockchainFamilies[b] != familySolana
+}
+
+// Address is an account address on a given blockchain, e.g. ETHEREUM:0x...
+type Address struct {
+	Blockchain Blockchain
+	Value      string
+}
+
+// ParseAddress parses a BLOCKCHAIN:address string
+func ParseAddress(s string) (Address, error) {
+	parts := strings.Split(s, idPartSeparator)
+	if len(parts) != 2 {
+		return Address{}, &IDError{Kind: "address", Input: s, Reason: "expected BLOCKCHAIN:address"}
+	}
+	blockchain, err := parseBlockchain("address", s, parts[0])
+	if err != nil {
+		return Address{}, err
+	}
+	if reason := blockchain.validateAddress(parts[1]); reason != "" {
+		return Address{}, &IDError{Kind: "address", Input: s, Reason: reason}
+	}
+	return Address{Blockchain: blockchain, Value: parts[1]}, nil
+}
+
+func (a Address) String() string {
+	return string(a.Blockchain) + idPartSeparator + a.Value
+}
+
+// Normalized returns the address lowercased when it is case-insensitive hex
+func (a Address) Normalized() Address {
+	switch blockchainFamilies[a.Blockchain] {
+	case familyEVM, familyFlow:
+		a.Value = strings.ToLower(a.Value)
+	}
+	return a
+}
+
+// CollectionID identifies a collection, e.g. ETHEREUM:0x...
+type CollectionID struct {
+	Blockchain Blockchain
+	Contract   string
+}
+
+// ParseCollectionID parses a BLOCKCHAIN:contract string, or its CAIP-19 form
+func ParseCollectionID(s string) (CollectionID, error) {
+	if isCAIP(s) {
+		return parseCAIPCollectionID(s)
+	}
+	parts := strings.Split(s, idPartSeparator)
+	if len(parts) != 2 {
+		return CollectionID{}, &IDError{Kind: "collection id", Input: s, Reason: "expected BLOCKCHAIN:contract"}
+	}
+	blockchain, err := parseBlockchain("collection id", s, parts[0])
+	if err != nil {
+		return CollectionID{}, err
+	}
+	if reason := blockchain.validateContract(parts[1]); reason != "" {
+		return CollectionID{}, &IDError{Kind: "collection id", Input: s, Reason: reason}
+	}
+	return CollectionID{Blockchain: blockchain, Contract: parts[1]}, nil
+}
+
+func (id CollectionID) String() string {
+	return string(id.Blockchain) + idPartSeparator + id.Contract
+}
+
+// Normalized returns the id with a case-insensitive hex contract lowercased
+func (id CollectionID) Normalized() CollectionID {
+	if blockchainFamilies[id.Blockchain] == familyEVM {
+		id.Contract = strings.ToLower(id.Contract)
+	}
+	return id
+}
+
+// ItemID identifies an item, e.g. ETHEREUM:0x...:123 or SOLANA:<mint>
+type ItemID struct {
+	Blockchain Blockchain
+	Contract   string
+	TokenID    string
+}
+
+// ParseItemID parses a BLOCKCHAIN:contract:tokenId string, or BLOCKCHAIN:mint on Solana,
+// or the CAIP-19 form of either
+func ParseItemID(s string) (ItemID, error) {
+	if isCAIP(s) {
+		return parseCAIPItemID(s)
+	}
+	id, rest, err := parseItemParts("item id", s, strings.Split(s, idPartSeparator))
+	if err != nil {
+		return ItemID{}, err
+	}
+	if len(rest) != 0 {
+		return ItemID{}, &IDError{Kind: "item id", Input: s, Reason: "unexpected trailing segments, did you pass an ownership id?"}
+	}
+	return id, nil
+}
+
+func (id ItemID) String() string {
+	if id.TokenID == "" {
+		return string(id.Blockchain) + idPartSeparator + id.Contract
+	}
+	return strings.Join([]string{string(id.Blockchain), id.Contract, id.TokenID}, idPartSeparator)
+}
+
+// Normalized returns the id with a case-insensitive hex contract lowercased
+func (id ItemID) Normalized() ItemID {
+	if blockchainFamilies[id.Blockchain] == familyEVM {
+		id.Contract = strings.ToLower(id.Contract)
+	}
+	return id
+}
+
+// Collection returns the collection the item belongs to. Solana items are
+// their own mint and carry no collection in the id.
+func (id ItemID) Collection() (CollectionID, bool) {
+	if !id.Blockchain.hasTokenID() {
+		return CollectionID{}, false
+	}
+	return CollectionID{Blockchain: id.Blockchain, Contract: id.Contract}, true
+}
+
+// OwnershipID identifies an ownership, e.g. ETHEREUM:0x...:123:0xowner
+type OwnershipID struct {
+	Item  ItemID
+	Owner string
+}
+
+// ParseOwnershipID parses a BLOCKCHAIN:contract:tokenId:owner string, or BLOCKCHAIN:mint:owner on Solana,
+// or an item's CAIP-19 asset id followed by /owner, the owner optionally as a CAIP-10 account id
+func ParseOwnershipID(s string) (OwnershipID, error) {
+	if isCAIP(s) {
+		return parseCAIPOwnershipID(s)
+	}
+	item, rest, err := parseItemParts("ownership id", s, strings.Split(s, idPartSeparator))
+	if err != nil {
+		return OwnershipID{}, err
+	}
+	if len(rest) != 1 {
+		return OwnershipID{}, &IDError{Kind: "ownership id", Input: s, Reason: "expected the owner address after the item id"}
+	}
+	if reason := item.Blockchain.validateAddress(rest[0]); reason != "" {
+		return OwnershipID{}, &IDError{Kind: "ownership id", Input: s, Reason: "owner: " + reason}
+	}
+	return OwnershipID{Item: item, Owner: rest[0]}, nil
+}
+
+// NewOwnershipID builds and validates an ownership id from its separate segments.
+// blockchain may be a CAIP-2 chain id. tokenID must be empty on blockchains
+// whose items carry no token id.
+func NewOwnershipID(blockchain, contract, tokenID, owner string) (OwnershipID, error) {
+	segments := []string{blockchain, contract, tokenID, owner}
+	input := strings.Join(segments, "/")
+	if strings.Contains(blockchain, caipPartSeparator) {
+		chain, err := ParseCAIP2(blockchain)
+		if err != nil {
+			return OwnershipID{}, caipError("ownership id", input, err)
+		}
+		blockchain = string(chain)
+	}
+	for _, segment := range []string{contract, tokenID, owner} {
+		if strings.Contains(segment, idPartSeparator) {
+			return OwnershipID{}, &IDError{Kind: "ownership id", Input: input, Reason: "segments must not contain ':'"}
+		}
+	}
+
+	chain, err := parseBlockchain("ownership id", input, blockchain)
+	if err != nil {
+		return OwnershipID{}, err
+	}
+	if !chain.hasTokenID() && tokenID != "" {
+		return OwnershipID{}, &IDError{Kind: "ownership id", Input: input, Reason: fmt.Sprintf("%s items have no token id", chain)}
+	}
+	if chain.hasTokenID() && tokenID == "" {
+		return OwnershipID{}, &IDError{Kind: "ownership id", Input: input, Reason: fmt.Sprintf("%s items need a token id", chain)}
+	}
+
+	segments = []string{blockchain, contract}
+	if tokenID != "" {
+		segments = append(segments, tokenID)
+	}
+	return ParseOwnershipID(strings.Join(append(segments, owner), idPartSeparator))
+}
+
+func (id OwnershipID) String() string {
+	return id.Item.String() + idPartSeparator + id.Owner
+}
+
+// Normalized returns the id with case-insensitive hex addresses lowercased,
+// leaving case-sensitive base58 addresses untouched
+func (id OwnershipID) Normalized() OwnershipID {
+	id.Item = id.Item.Normalized()
+	switch blockchainFamilies[id.Item.Blockchain] {
+	case familyEVM, familyFlow:
+		id.Owner = strings.ToLower(id.Owner)
+	}
+	return id
+}
+
+// parseItemParts parses the item part of an id and returns the remaining segments
+func parseItemParts(kind, input string, parts []string) (ItemID, []string, error) {
+	format := "BLOCKCHAIN:contract:tokenId"
+	if len(parts) < 2 {
+		return ItemID{}, nil, &IDError{Kind: kind, Input: input, Reason: "expected " + format}
+	}
+
+	blockchain, err := parseBlockchain(kind, input, parts[0])
+	if err != nil {
+		return ItemID{}, nil, err
+	}
+
+	if !blockchain.hasTokenID() {
+		if reason := blockchain.validateAddress(parts[1]); reason != "" {
+			return ItemID{}, nil, &IDError{Kind: kind, Input: input, Reason: "mint: " + reason}
+		}
+		return ItemID{Blockchain: blockchain, Contract: parts[1]}, parts[2:], nil
+	}
+
+	if len(parts) < 3 {
+		return ItemID{}, nil, &IDError{Kind: kind, Input: input, Reason: "expected " + format}
+	}
+	if reason := blockchain.validateContract(parts[1]); reason != "" {
+		return ItemID{}, nil, &IDError{Kind: kind, Input: input, Reason: "contract: " + reason}
+	}
+	if !decimalTokenIDRe.MatchString(parts[2]) {
+		return ItemID{}, nil, &IDError{Kind: kind, Input: input, Reason: "token id must be a non-negative decimal integer"}
+	}
+	return ItemID{Blockchain: blockchain, Contract: parts[1], TokenID: parts[2]}, parts[3:], nil
+}
+
+// CompareTokenIDs orders decimal token ids numerically and others after them, lexically
+func CompareTokenIDs(a, b string) int {
+	x, okA := new(big.Int).SetString(a, 10)
+	y, okB := new(big.Int).SetString(b, 10)
+	switch {
+	case okA && okB:
+		return x.Cmp(y)
+	case okA:
+		return -1
+	case okB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"slices"
+	"testing"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOwnershipID(t *testing.T) {
+	t.Run("ShouldParseValidIDs", func(t *testing.T) {
+		cases := map[string]OwnershipID{
+			"ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:123:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10": {
+				Item:  ItemID{Blockchain: BlockchainEthereum, Contract: "0x06012c8cf97bead5deae237070f9587f8e7a266d", TokenID: "123"},
+				Owner: "0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10",
+			},
+			"polygon:0x2953399124f0cbb46d2cbacd8a89cf0599974963:42:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10": {
+				Item:  ItemID{Blockchain: BlockchainPolygon, Contract: "0x2953399124f0cbb46d2cbacd8a89cf0599974963", TokenID: "42"},
+				Owner: "0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10",
+			},
+			"SOLANA:7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU:9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM": {
+				Item:  ItemID{Blockchain: BlockchainSolana, Contract: "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"},
+				Owner: "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM",
+			},
+			"TEZOS:KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton:7:tz1burnburnburnburnburnburnburjAYjjX": {
+				Item:  ItemID{Blockchain: BlockchainTezos, Contract: "KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton", TokenID: "7"},
+				Owner: "tz1burnburnburnburnburnburnburjAYjjX",
+			},
+			"FLOW:A.0b2a3299cc857e29.TopShot:123:0x0b2a3299cc857e29": {
+				Item:  ItemID{Blockchain: BlockchainFlow, Contract: "A.0b2a3299cc857e29.TopShot", TokenID: "123"},
+				Owner: "0x0b2a3299cc857e29",
+			},
+		}
+		for input, want := range cases {
+			id, err := ParseOwnershipID(input)
+			require.NoError(t, err, input)
+			require.Equal(t, want, id, input)
+		}
+	})
+
+	t.Run("ShouldRejectMalformedIDs", func(t *testing.T) {
+		cases := map[string]string{
+			"":                       "expected BLOCKCHAIN:contract:tokenId",
+			"id-123":                 "expected BLOCKCHAIN:contract:tokenId",
+			"BITCOIN:0x0:1:0x0":      "unknown blockchain",
+			"ETHEREUM:0x123:1:0xabc": "contract: expected a 0x prefixed 20 byte hex address",
+			"ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:abc:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10": "token id",
+			"ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:1":                                              "expected the owner address",
+			"ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:1:0xabc":                                        "owner: expected a 0x prefixed 20 byte hex address",
+			"TEZOS:0x06012c8cf97bead5deae237070f9587f8e7a266d:1:tz1burnburnburnburnburnburnburjAYjjX":            "contract: expected a KT1 Tezos contract address",
+		}
+		for input, reason := range cases {
+			_, err := ParseOwnershipID(input)
+			require.ErrorIs(t, err, businesserrors.ErrInvalidRequest, input)
+			require.ErrorContains(t, err, reason, input)
+		}
+	})
+}
+
+func TestParseItemID(t *testing.T) {
+	t.Run("ShouldParseAndFormat", func(t *testing.T) {
+		id, err := ParseItemID("ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:123")
+		require.NoError(t, err)
+		require.Equal(t, "ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:123", id.String())
+
+		collection, ok := id.Collection()
+		require.True(t, ok)
+		require.Equal(t, "ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d", collection.String())
+	})
+
+	t.Run("ShouldRejectOwnershipID", func(t *testing.T) {
+		_, err := ParseItemID("ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:123:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+		require.ErrorContains(t, err, "did you pass an ownership id")
+	})
+
+	t.Run("ShouldParseSolanaMint", func(t *testing.T) {
+		id, err := ParseItemID("SOLANA:7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+		require.NoError(t, err)
+		_, ok := id.Collection()
+		require.False(t, ok)
+	})
+}
+
+func TestParseCollectionIDAndAddress(t *testing.T) {
+	collection, err := ParseCollectionID("ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6")
+	require.NoError(t, err)
+	require.Equal(t, BlockchainEthereum, collection.Blockchain)
+
+	_, err = ParseCollectionID("ETHEREUM:0x123")
+	require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+
+	_, err = ParseCollectionID("")
+	require.ErrorContains(t, err, "expected BLOCKCHAIN:contract")
+
+	address, err := ParseAddress("TEZOS:tz1burnburnburnburnburnburnburjAYjjX")
+	require.NoError(t, err)
+	require.Equal(t, "TEZOS:tz1burnburnburnburnburnburnburjAYjjX", address.String())
+
+	_, err = ParseAddress("SOLANA:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+	require.ErrorContains(t, err, "Solana address")
+}
+
+func TestNewOwnershipID(t *testing.T) {
+	t.Run("ShouldBuildAndNormalise", func(t *testing.T) {
+		id, err := NewOwnershipID("ethereum", "0x06012C8cf97BEaD5deAe237070F9587f8E7A266d", "123", "0x4B7A2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+		require.NoError(t, err)
+		require.Equal(t,
+			"ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:123:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10",
+			id.Normalized().String())
+	})
+
+	t.Run("ShouldKeepBase58Casing", func(t *testing.T) {
+		id, err := NewOwnershipID("SOLANA", "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU", "", "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+		require.NoError(t, err)
+		require.Equal(t, "SOLANA:7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU:9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", id.Normalized().String())
+	})
+
+	t.Run("ShouldRejectInvalidSegments", func(t *testing.T) {
+		_, err := NewOwnershipID("ETHEREUM", "0x06012c8cf97bead5deae237070f9587f8e7a266d:1", "1", "0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+		require.ErrorContains(t, err, "must not contain ':'")
+
+		_, err = NewOwnershipID("SOLANA", "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU", "1", "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+		require.ErrorContains(t, err, "SOLANA items have no token id")
+
+		_, err = NewOwnershipID("ETHEREUM", "0x06012c8cf97bead5deae237070f9587f8e7a266d", "", "0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+		require.ErrorContains(t, err, "ETHEREUM items need a token id")
+	})
+}
+
+func TestCompareTokenIDs(t *testing.T) {
+	ids := []string{"b", "10", "2", "a", "100000000000000000000000000000"}
+	slices.SortFunc(ids, CompareTokenIDs)
+	require.Equal(t, []string{"2", "10", "100000000000000000000000000000", "a", "b"}, ids)
+}
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// ErrInvalidQuantity is returned for quantities that are not base 10 integers
+var ErrInvalidQuantity = errors.New("invalid quantity")
+
+// Quantity is an arbitrary-precision integer such as an ERC-1155 balance or
+// supply. It is immutable, its zero value is 0 and it is encoded in JSON as a
+// decimal string so no precision is lost to float parsing.
+type Quantity struct {
+	i *big.Int
+}
+
+// NewQuantity returns the quantity n
+func NewQuantity(n int64) Quantity {
+	return Quantity{i: big.NewInt(n)}
+}
+
+// ParseQuantity parses a base 10 integer of any size, with an optional sign
+func ParseQuantity(s string) (Quantity, error) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return Quantity{}, fmt.Errorf("%w: %q is not a base 10 integer", ErrInvalidQuantity, s)
+	}
+	return Quantity{i: i}, nil
+}
+
+// MustParseQuantity is like ParseQuantity but panics on invalid input, for constants and tests
+func MustParseQuantity(s string) Quantity {
+	q, err := ParseQuantity(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+func (q Quantity) big() *big.Int {
+	if q.i == nil {
+		return new(big.Int)
+	}
+	return q.i
+}
+
+// BigInt returns a copy of the quantity as a *big.Int
+func (q Quantity) BigInt() *big.Int {
+	return new(big.Int).Set(q.big())
+}
+
+// Add returns q + other
+func (q Quantity) Add(other Quantity) Quantity {
+	return Quantity{i: new(big.Int).Add(q.big(), other.big())}
+}
+
+// Sub returns q - other
+func (q Quantity) Sub(other Quantity) Quantity {
+	return Quantity{i: new(big.Int).Sub(q.big(), other.big())}
+}
+
+// Mul returns q * other
+func (q Quantity) Mul(other Quantity) Quantity {
+	return Quantity{i: new(big.Int).Mul(q.big(), other.big())}
+}
+
+// Cmp returns -1, 0 or +1 depending on whether q is less than, equal to or greater than other
+func (q Quantity) Cmp(other Quantity) int {
+	return q.big().Cmp(other.big())
+}
+
+// Equal reports whether q and other are the same number
+func (q Quantity) Equal(other Quantity) bool {
+	return q.Cmp(other) == 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of q
+func (q Quantity) Sign() int {
+	return q.big().Sign()
+}
+
+// IsZero reports whether q is 0
+func (q Quantity) IsZero() bool {
+	return q.Sign() == 0
+}
+
+// Max returns the larger of q and other
+func (q Quantity) Max(other Quantity) Quantity {
+	if q.Cmp(other) >= 0 {
+		return q
+	}
+	return other
+}
+
+// SumQuantities returns the sum of quantities, 0 when there are none
+func SumQuantities(quantities ...Quantity) Quantity {
+	sum := new(big.Int)
+	for _, q := range quantities {
+		sum.Add(sum, q.big())
+	}
+	return Quantity{i: sum}
+}
+
+func (q Quantity) String() string {
+	return q.big().String()
+}
+
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.String())
+}
+
+// UnmarshalJSON accepts a JSON string holding a base 10 integer. Other JSON
+// types fail with a *json.UnmarshalTypeError like a plain string field would,
+// non-numeric strings fail with ErrInvalidQuantity.
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return &json.UnmarshalTypeError{Value: jsonKind(data), Type: reflect.TypeOf(*q)}
+	}
+	parsed, err := ParseQuantity(s)
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+// jsonKind names the JSON type of data for type errors
+func jsonKind(data []byte) string {
+	switch data[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	default:
+		return "number"
+	}
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantity(t *testing.T) {
+	t.Run("ShouldKeepPrecisionBeyondFloat64", func(t *testing.T) {
+		a := MustParseQuantity("115792089237316195423570985008687907853269984665640564039457584007913129639935")
+		b := MustParseQuantity("1")
+
+		require.Equal(t, "115792089237316195423570985008687907853269984665640564039457584007913129639936", a.Add(b).String())
+		require.Equal(t, "115792089237316195423570985008687907853269984665640564039457584007913129639934", a.Sub(b).String())
+		require.Equal(t, 1, a.Cmp(b))
+		require.True(t, a.Sub(a).IsZero())
+		require.Equal(t, "9007199254740993", SumQuantities(MustParseQuantity("9007199254740992"), b).String())
+	})
+
+	t.Run("ShouldTreatZeroValueAsZero", func(t *testing.T) {
+		var q Quantity
+		require.True(t, q.IsZero())
+		require.Equal(t, "0", q.String())
+		require.True(t, q.Equal(NewQuantity(0)))
+		require.Equal(t, "5", q.Add(NewQuantity(5)).String())
+	})
+
+	t.Run("ShouldNotMutateOperands", func(t *testing.T) {
+		a := NewQuantity(10)
+		_ = a.Add(NewQuantity(5))
+		_ = a.Mul(NewQuantity(3))
+		require.Equal(t, "10", a.String())
+
+		big := a.BigInt()
+		big.SetInt64(99)
+		require.Equal(t, "10", a.String())
+	})
+
+	t.Run("ShouldMarshalAsString", func(t *testing.T) {
+		data, err := json.Marshal(struct {
+			Value Quantity `json:"value"`
+		}{Value: MustParseQuantity("18446744073709551617")})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"value":"18446744073709551617"}`, string(data))
+	})
+
+	t.Run("ShouldUnmarshalStrings", func(t *testing.T) {
+		var v struct {
+			Value Quantity `json:"value"`
+			Lazy  Quantity `json:"lazy"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{"value":"18446744073709551617","lazy":null}`), &v))
+		require.Equal(t, "18446744073709551617", v.Value.String())
+		require.True(t, v.Lazy.IsZero())
+	})
+
+	t.Run("ShouldRejectNonNumericValues", func(t *testing.T) {
+		for _, input := range []string{`"abc"`, `""`, `"1.5"`, `"1e18"`} {
+			var q Quantity
+			err := json.Unmarshal([]byte(input), &q)
+			require.ErrorIs(t, err, ErrInvalidQuantity, input)
+		}
+
+		var q Quantity
+		var typeErr *json.UnmarshalTypeError
+		require.ErrorAs(t, json.Unmarshal([]byte(`1`), &q), &typeErr)
+		require.Equal(t, "number", typeErr.Value)
+	})
+}
+
+func TestMinted(t *testing.T) {
+	ownership := OwnershipDTO{Value: MustParseQuantity("1000000000000000000001"), LazyValue: NewQuantity(1)}
+	require.Equal(t, "1000000000000000000000", ownership.Minted().String())
+
+	item := ItemDTO{Supply: NewQuantity(1), LazySupply: NewQuantity(3)}
+	require.True(t, item.Minted().IsZero())
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ErrInvalidRarity is returned for rarities that are not decimal numbers
+var ErrInvalidRarity = errors.New("invalid rarity")
+
+// Rarity is the share of a collection's items having a trait, in percent.
+// Lower is rarer. It is encoded in JSON as a decimal string, as upstream sends it.
+type Rarity float64
+
+// ParseRarity parses a decimal rarity such as "1.2"
+func ParseRarity(s string) (Rarity, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidRarity, s)
+	}
+	return Rarity(f), nil
+}
+
+func (r Rarity) String() string {
+	return strconv.FormatFloat(float64(r), 'f', -1, 64)
+}
+
+func (r Rarity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a JSON number
+func (r *Rarity) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var f float64
+		if err := json.Unmarshal(data, &f); err != nil {
+			return &json.UnmarshalTypeError{Value: jsonKind(data), Type: reflect.TypeOf(*r)}
+		}
+		*r = Rarity(f)
+		return nil
+	}
+	parsed, err := ParseRarity(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Rarity tier labels, from most to least rare
+const (
+	TierLegendary = "legendary"
+	TierEpic      = "epic"
+	TierRare      = "rare"
+	TierUncommon  = "uncommon"
+	TierCommon    = "common"
+)
+
+// RarityTierLabels lists the tier labels from most to least rare
+var RarityTierLabels = []string{TierLegendary, TierEpic, TierRare, TierUncommon, TierCommon}
+
+// RarityTiers holds the highest rarity, in percent, still labelled with each tier.
+// Anything above Uncommon is common.
+type RarityTiers struct {
+	Legendary Rarity
+	Epic      Rarity
+	Rare      Rarity
+	Uncommon  Rarity
+}
+
+// DefaultRarityTiers returns the thresholds used when none are configured
+func DefaultRarityTiers() RarityTiers {
+	return RarityTiers{Legendary: 1, Epic: 5, Rare: 15, Uncommon: 40}
+}
+
+// Validate reports thresholds outside 0-100 or not in ascending order
+func (t RarityTiers) Validate() error {
+	thresholds := []Rarity{t.Legendary, t.Epic, t.Rare, t.Uncommon}
+	for i, threshold := range thresholds {
+		if threshold < 0 || threshold > 100 {
+			return fmt.Errorf("rarity tier thresholds must be between 0 and 100, got %s", threshold)
+		}
+		if i > 0 && threshold < thresholds[i-1] {
+			return errors.New("rarity tier thresholds must ascend from legendary to uncommon")
+		}
+	}
+	return nil
+}
+
+// Tier returns the label of the rarest tier whose threshold r is within
+func (t RarityTiers) Tier(r Rarity) string {
+	switch {
+	case r <= t.Legendary:
+		return TierLegendary
+	case r <= t.Epic:
+		return TierEpic
+	case r <= t.Rare:
+		return TierRare
+	case r <= t.Uncommon:
+		return TierUncommon
+	default:
+		return TierCommon
+	}
+}
+
+// IsRarityTier reports whether tier is one of the tier labels
+func IsRarityTier(tier string) bool {
+	switch tier {
+	case TierLegendary, TierEpic, TierRare, TierUncommon, TierCommon:
+		return true
+	}
+	return false
+}
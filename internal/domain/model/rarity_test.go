@@ -0,0 +1,53 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRarity(t *testing.T) {
+	t.Run("ShouldKeepStringEncoding", func(t *testing.T) {
+		var trait ExtendedTraitProperty
+		require.NoError(t, json.Unmarshal([]byte(`{"key":"Hat","value":"Halo","rarity":"1.25"}`), &trait))
+		require.Equal(t, Rarity(1.25), trait.Rarity)
+
+		data, err := json.Marshal(trait)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"key":"Hat","value":"Halo","rarity":"1.25"}`, string(data))
+	})
+
+	t.Run("ShouldAcceptNumbers", func(t *testing.T) {
+		var r Rarity
+		require.NoError(t, json.Unmarshal([]byte(`0.5`), &r))
+		require.Equal(t, Rarity(0.5), r)
+	})
+
+	t.Run("ShouldRejectNonNumericValues", func(t *testing.T) {
+		var r Rarity
+		require.ErrorIs(t, json.Unmarshal([]byte(`"rare"`), &r), ErrInvalidRarity)
+
+		var typeErr *json.UnmarshalTypeError
+		require.ErrorAs(t, json.Unmarshal([]byte(`true`), &r), &typeErr)
+	})
+}
+
+func TestRarityTiers(t *testing.T) {
+	tiers := DefaultRarityTiers()
+	cases := map[Rarity]string{
+		0.5: TierLegendary,
+		1:   TierLegendary,
+		3:   TierEpic,
+		15:  TierRare,
+		20:  TierUncommon,
+		80:  TierCommon,
+	}
+	for rarity, tier := range cases {
+		require.Equal(t, tier, tiers.Tier(rarity), rarity.String())
+	}
+
+	require.NoError(t, tiers.Validate())
+	require.Error(t, RarityTiers{Legendary: 10, Epic: 5, Rare: 15, Uncommon: 40}.Validate())
+	require.Error(t, RarityTiers{Legendary: 1, Epic: 5, Rare: 15, Uncommon: 140}.Validate())
+}
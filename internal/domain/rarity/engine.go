@@ -0,0 +1,472 @@
+// Package rarity computes trait frequencies and rarity scores and ranks for
+// every item of a collection from the items' traits alone
+package rarity
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MissingValue is the value of the synthetic trait an item gets for every key it lacks
+	MissingValue = "none"
+	// TraitCountKey is the key of the meta-trait holding the number of traits an item has
+	TraitCountKey = "meta_trait:trait_count"
+)
+
+// Method is a scoring method items are ranked by
+type Method string
+
+const (
+	// MethodInformationContent is the OpenRarity score: the information content
+	// of an item's traits, -log2 of their probabilities, normalised by the
+	// collection's entropy. Higher is rarer.
+	MethodInformationContent Method = "information_content"
+	// MethodRarityScore is the sum of 1/frequency over an item's traits. Higher is rarer.
+	MethodRarityScore Method = "rarity_score"
+	// MethodStatistical is the product of an item's trait frequencies. Lower is rarer.
+	MethodStatistical Method = "statistical"
+)
+
+// Methods lists every scoring method
+var Methods = []Method{MethodInformationContent, MethodRarityScore, MethodStatistical}
+
+// Valid reports whether m is a known method
+func (m Method) Valid() bool {
+	return slices.Contains(Methods, m)
+}
+
+// Trait is a key/value attribute of an item
+type Trait struct {
+	Key   string
+	Value string
+}
+
+// Item is a collection item and its traits
+type Item struct {
+	ID     string
+	Traits []Trait
+}
+
+// Options controls which synthetic traits are scored alongside the item's own
+type Options struct {
+	// IncludeMissing scores a key an item lacks as the trait key=MissingValue
+	IncludeMissing bool
+	// IncludeTraitCount scores the number of traits an item has as the TraitCountKey meta-trait
+	IncludeTraitCount bool
+}
+
+// DefaultOptions returns the options matching OpenRarity's defaults
+func DefaultOptions() Options {
+	return Options{IncludeMissing: true, IncludeTraitCount: true}
+}
+
+// TraitFrequency is how many items have a trait and which share of the collection that is
+type TraitFrequency struct {
+	Key       string
+	Value     string
+	Count     int
+	Frequency float64
+}
+
+// Scores holds an item's score under every method
+type Scores struct {
+	InformationContent float64
+	RarityScore        float64
+	Statistical        float64
+}
+
+// Get returns the score under method
+func (s Scores) Get(method Method) float64 {
+	switch method {
+	case MethodRarityScore:
+		return s.RarityScore
+	case MethodStatistical:
+		return s.Statistical
+	default:
+		return s.InformationContent
+	}
+}
+
+// ItemScore is an item's scores and its rank under every method. Rank 1 is
+// the rarest item; items with equal scores share a rank.
+type ItemScore struct {
+	ID     string
+	Traits []Trait
+	Scores Scores
+	Ranks  map[Method]int
+}
+
+// Contribution is how much one trait of an item adds to its score
+type Contribution struct {
+	Trait     Trait
+	Count     int
+	Frequency float64
+	// Synthetic is set for the missing-value and trait count traits the options add
+	Synthetic bool
+	// Value is the trait's term of the score: its information content over the
+	// collection's entropy, its 1/frequency or, for the statistical method, its frequency
+	Value float64
+	// Share is the percentage of the item's score the trait accounts for. Statistical
+	// scores are products, so their shares are taken of the score's logarithm.
+	Share float64
+}
+
+// Collection is the result of scoring a collection
+type Collection struct {
+	options     Options
+	total       int
+	entropy     float64
+	counts      map[Trait]int
+	frequencies []TraitFrequency
+	items       []ItemScore
+	index       map[string]int
+	// own holds how many of each item's leading traits are its own rather than synthetic
+	own []int
+}
+
+// Compute scores every item of a collection. Items with the same ID are scored once.
+func Compute(items []Item, opts Options) *Collection {
+	c := &Collection{
+		options: opts,
+		counts:  make(map[Trait]int),
+		index:   make(map[string]int, len(items)),
+	}
+
+	var keys []string
+	seenKeys := make(map[string]bool)
+	for _, item := range items {
+		if _, ok := c.index[item.ID]; ok {
+			continue
+		}
+		c.index[item.ID] = len(c.items)
+		traits := dedupeTraits(item.Traits)
+		for _, trait := range traits {
+			if !seenKeys[trait.Key] {
+				seenKeys[trait.Key] = true
+				keys = append(keys, trait.Key)
+			}
+		}
+		c.items = append(c.items, ItemScore{ID: item.ID, Traits: traits})
+		c.own = append(c.own, len(traits))
+	}
+	c.total = len(c.items)
+
+	// expand every item's traits with the synthetic ones before counting
+	for i := range c.items {
+		c.items[i].Traits = c.scoredTraits(c.items[i].Traits, keys)
+		for _, trait := range c.items[i].Traits {
+			c.counts[trait]++
+		}
+	}
+
+	for trait, count := range c.counts {
+		c.frequencies = append(c.frequencies, TraitFrequency{Key: trait.Key, Value: trait.Value, Count: count, Frequency: c.probability(count)})
+	}
+	slices.SortFunc(c.frequencies, func(a, b TraitFrequency) int {
+		return cmp.Or(cmp.Compare(a.Key, b.Key), cmp.Compare(a.Count, b.Count), cmp.Compare(a.Value, b.Value))
+	})
+	// summed in sorted order, map order would make the float sum and so the scores vary between runs
+	for _, frequency := range c.frequencies {
+		c.entropy -= frequency.Frequency * math.Log2(frequency.Frequency)
+	}
+
+	for i := range c.items {
+		c.items[i].Scores = c.score(c.items[i].Traits)
+		c.items[i].Ranks = make(map[Method]int, len(Methods))
+	}
+	for _, method := range Methods {
+		c.rank(method)
+	}
+	return c
+}
+
+// scoredTraits returns the item's traits followed by the synthetic traits the options ask for
+func (c *Collection) scoredTraits(traits []Trait, keys []string) []Trait {
+	scored := slices.Clone(traits)
+	if c.options.IncludeMissing {
+		for _, key := range keys {
+			if !slices.ContainsFunc(traits, func(t Trait) bool { return t.Key == key }) {
+				scored = append(scored, Trait{Key: key, Value: MissingValue})
+			}
+		}
+	}
+	if c.options.IncludeTraitCount {
+		scored = append(scored, Trait{Key: TraitCountKey, Value: strconv.Itoa(len(traits))})
+	}
+	return scored
+}
+
+func (c *Collection) score(traits []Trait) Scores {
+	scores := Scores{Statistical: 1}
+	information := 0.0
+	for _, trait := range traits {
+		p := c.probability(c.counts[trait])
+		information -= math.Log2(p)
+		scores.RarityScore += 1 / p
+		scores.Statistical *= p
+	}
+	if c.entropy > 0 {
+		scores.InformationContent = information / c.entropy
+	}
+	return scores
+}
+
+// rank assigns competition ranks by method, the rarest item first
+func (c *Collection) rank(method Method) {
+	order := make([]int, len(c.items))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return compareScores(method, c.items[a].Scores.Get(method), c.items[b].Scores.Get(method))
+	})
+
+	for position, i := range order {
+		rank := position + 1
+		if position > 0 {
+			previous := c.items[order[position-1]]
+			if compareScores(method, previous.Scores.Get(method), c.items[i].Scores.Get(method)) == 0 {
+				rank = previous.Ranks[method]
+			}
+		}
+		c.items[i].Ranks[method] = rank
+	}
+}
+
+// compareScores orders a before b when a is rarer under method. Scores within
+// a relative 1e-9 of each other are equal, so float noise does not split ties.
+func compareScores(method Method, a, b float64) int {
+	if math.Abs(a-b) <= 1e-9*math.Max(math.Abs(a), math.Abs(b)) {
+		return 0
+	}
+	if method == MethodStatistical {
+		return cmp.Compare(a, b)
+	}
+	return cmp.Compare(b, a)
+}
+
+func (c *Collection) probability(count int) float64 {
+	if c.total == 0 {
+		return 0
+	}
+	return float64(count) / float64(c.total)
+}
+
+// Total returns the number of scored items
+func (c *Collection) Total() int {
+	return c.total
+}
+
+// Frequencies returns every trait, including synthetic ones, sorted by key and rarest value first
+func (c *Collection) Frequencies() []TraitFrequency {
+	return slices.Clone(c.frequencies)
+}
+
+// Frequency returns how many items have trait
+func (c *Collection) Frequency(trait Trait) TraitFrequency {
+	count := c.counts[trait]
+	return TraitFrequency{Key: trait.Key, Value: trait.Value, Count: count, Frequency: c.probability(count)}
+}
+
+// Item returns the scores of the item with id
+func (c *Collection) Item(id string) (ItemScore, bool) {
+	i, ok := c.index[id]
+	if !ok {
+		return ItemScore{}, false
+	}
+	return c.items[i], true
+}
+
+// UniqueTraits returns how many of the item with id's own traits no other item has
+func (c *Collection) UniqueTraits(id string) int {
+	i, ok := c.index[id]
+	if !ok {
+		return 0
+	}
+	unique := 0
+	for _, trait := range c.items[i].Traits[:c.own[i]] {
+		if c.counts[trait] == 1 {
+			unique++
+		}
+	}
+	return unique
+}
+
+// Contributions breaks the score of the item with id down by trait, largest share first
+func (c *Collection) Contributions(id string, method Method) ([]Contribution, bool) {
+	item, ok := c.Item(id)
+	if !ok {
+		return nil, false
+	}
+
+	own := c.own[c.index[id]]
+	contributions := make([]Contribution, 0, len(item.Traits))
+	// weights are the traits' additive parts of the score, shares are taken of their total
+	weights := make([]float64, 0, len(item.Traits))
+	total := 0.0
+	for i, trait := range item.Traits {
+		frequency := c.Frequency(trait)
+		contribution := Contribution{
+			Trait:     trait,
+			Count:     frequency.Count,
+			Frequency: frequency.Frequency,
+			Synthetic: i >= own,
+		}
+		information := -math.Log2(frequency.Frequency)
+		switch method {
+		case MethodRarityScore:
+			contribution.Value = 1 / frequency.Frequency
+		case MethodStatistical:
+			contribution.Value = frequency.Frequency
+		default:
+			if c.entropy > 0 {
+				contribution.Value = information / c.entropy
+			}
+		}
+		weight := contribution.Value
+		if method == MethodStatistical {
+			weight = information
+		}
+		weights = append(weights, weight)
+		total += weight
+		contributions = append(contributions, contribution)
+	}
+
+	if total > 0 {
+		for i := range contributions {
+			contributions[i].Share = math.Round(weights[i]/total*100*100) / 100
+		}
+	}
+	slices.SortStableFunc(contributions, func(a, b Contribution) int {
+		return cmp.Or(cmp.Compare(b.Share, a.Share), cmp.Compare(a.Frequency, b.Frequency))
+	})
+	return contributions, true
+}
+
+// Ranked returns every item ordered by rank under method, ties in input order
+func (c *Collection) Ranked(method Method) []ItemScore {
+	items := slices.Clone(c.items)
+	slices.SortStableFunc(items, func(a, b ItemScore) int {
+		return cmp.Compare(a.Ranks[method], b.Ranks[method])
+	})
+	return items
+}
+
+// Percentile returns the share of items, in percent, ranked at or below rank; the rarest item is 100
+func (c *Collection) Percentile(rank int) float64 {
+	if c.total == 0 {
+		return 0
+	}
+	return math.Round(float64(c.total-rank+1)/float64(c.total)*100*100) / 100
+}
+
+// dedupeTraits drops repeated key/value pairs and traits with an empty key
+func dedupeTraits(traits []Trait) []Trait {
+	deduped := make([]Trait, 0, len(traits))
+	for _, trait := range traits {
+		if trait.Key == "" || slices.Contains(deduped, trait) {
+			continue
+		}
+		deduped = append(deduped, trait)
+	}
+	return deduped
+}
+
+// Combination is a set of traits and how many items have all of them
+type Combination struct {
+	// Traits are sorted by key, then value
+	Traits    []Trait
+	Count     int
+	Frequency float64
+}
+
+// Combinations counts every combination of size traits with distinct keys that
+// items have, rarest first. Only the items' own traits are combined, synthetic ones are not.
+func (c *Collection) Combinations(size int) []Combination {
+	if size < 1 {
+		return nil
+	}
+
+	counts := make(map[string]*Combination)
+	var order []string
+	for i, item := range c.items {
+		own := slices.Clone(item.Traits[:c.own[i]])
+		slices.SortFunc(own, compareTraits)
+		eachCombination(own, size, func(traits []Trait) {
+			key := combinationKey(traits)
+			combination, ok := counts[key]
+			if !ok {
+				combination = &Combination{Traits: slices.Clone(traits)}
+				counts[key] = combination
+				order = append(order, key)
+			}
+			combination.Count++
+		})
+	}
+
+	combinations := make([]Combination, 0, len(order))
+	for _, key := range order {
+		combination := *counts[key]
+		combination.Frequency = c.probability(combination.Count)
+		combinations = append(combinations, combination)
+	}
+	slices.SortStableFunc(combinations, func(a, b Combination) int {
+		return cmp.Or(cmp.Compare(a.Count, b.Count), slices.CompareFunc(a.Traits, b.Traits, compareTraits))
+	})
+	return combinations
+}
+
+// Combination counts the items having every one of traits. Synthetic traits,
+// such as a key with MissingValue, match the items they were added to.
+func (c *Collection) Combination(traits []Trait) Combination {
+	combination := Combination{Traits: dedupeTraits(traits)}
+	slices.SortFunc(combination.Traits, compareTraits)
+	for _, item := range c.items {
+		if len(combination.Traits) > 0 && !slices.ContainsFunc(combination.Traits, func(t Trait) bool { return !slices.Contains(item.Traits, t) }) {
+			combination.Count++
+		}
+	}
+	combination.Frequency = c.probability(combination.Count)
+	return combination
+}
+
+// eachCombination calls fn with every combination of size traits with distinct keys, in order.
+// fn must not keep the slice it is given.
+func eachCombination(traits []Trait, size int, fn func([]Trait)) {
+	combination := make([]Trait, 0, size)
+	var walk func(start int)
+	walk = func(start int) {
+		if len(combination) == size {
+			fn(combination)
+			return
+		}
+		for i := start; i < len(traits); i++ {
+			if slices.ContainsFunc(combination, func(t Trait) bool { return t.Key == traits[i].Key }) {
+				continue
+			}
+			combination = append(combination, traits[i])
+			walk(i + 1)
+			combination = combination[:len(combination)-1]
+		}
+	}
+	walk(0)
+}
+
+func combinationKey(traits []Trait) string {
+	var b strings.Builder
+	for _, trait := range traits {
+		b.WriteString(trait.Key)
+		b.WriteByte(0)
+		b.WriteString(trait.Value)
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+func compareTraits(a, b Trait) int {
+	return cmp.Or(cmp.Compare(a.Key, b.Key), cmp.Compare(a.Value, b.Value))
+}
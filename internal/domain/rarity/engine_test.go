@@ -0,0 +1,270 @@
+package rarity
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testItems() []Item {
+	return []Item{
+		{ID: "a", Traits: []Trait{{Key: "Hat", Value: "Cap"}, {Key: "Eyes", Value: "Blue"}}},
+		{ID: "b", Traits: []Trait{{Key: "Hat", Value: "Cap"}, {Key: "Eyes", Value: "Blue"}}},
+		{ID: "c", Traits: []Trait{{Key: "Hat", Value: "Crown"}, {Key: "Eyes", Value: "Blue"}}},
+		{ID: "d", Traits: []Trait{{Key: "Eyes", Value: "Red"}}},
+	}
+}
+
+func ranks(c *Collection, method Method) []int {
+	var result []int
+	for _, id := range []string{"a", "b", "c", "d"} {
+		item, _ := c.Item(id)
+		result = append(result, item.Ranks[method])
+	}
+	return result
+}
+
+func TestCompute(t *testing.T) {
+	t.Run("ShouldCountMissingTraitsAndTraitCount", func(t *testing.T) {
+		c := Compute(testItems(), DefaultOptions())
+
+		require.Equal(t, 4, c.Total())
+		require.Equal(t, 1, c.Frequency(Trait{Key: "Hat", Value: MissingValue}).Count)
+		require.Equal(t, 3, c.Frequency(Trait{Key: TraitCountKey, Value: "2"}).Count)
+		require.Equal(t, 0.25, c.Frequency(Trait{Key: TraitCountKey, Value: "1"}).Frequency)
+		require.Len(t, c.Frequencies(), 7)
+		require.Equal(t, TraitFrequency{Key: "Eyes", Value: "Red", Count: 1, Frequency: 0.25}, c.Frequencies()[0])
+	})
+
+	t.Run("ShouldScoreEveryMethod", func(t *testing.T) {
+		c := Compute(testItems(), DefaultOptions())
+
+		d, ok := c.Item("d")
+		require.True(t, ok)
+		require.InDelta(t, 12, d.Scores.RarityScore, 1e-9)
+		require.InDelta(t, 0.015625, d.Scores.Statistical, 1e-9)
+		// 6 bits of information over an entropy of 1.5 + 2*0.811278 bits
+		require.InDelta(t, 1.921502, d.Scores.InformationContent, 1e-6)
+
+		a, _ := c.Item("a")
+		require.InDelta(t, 2+4.0/3+4.0/3, a.Scores.RarityScore, 1e-9)
+		require.InDelta(t, 0.28125, a.Scores.Statistical, 1e-9)
+	})
+
+	t.Run("ShouldRankRarestFirstWithTies", func(t *testing.T) {
+		c := Compute(testItems(), DefaultOptions())
+
+		for _, method := range Methods {
+			require.Equal(t, []int{3, 3, 2, 1}, ranks(c, method), method)
+		}
+		ranked := c.Ranked(MethodStatistical)
+		require.Equal(t, []string{"d", "c", "a", "b"}, []string{ranked[0].ID, ranked[1].ID, ranked[2].ID, ranked[3].ID})
+		require.Equal(t, 100.0, c.Percentile(1))
+		require.Equal(t, 50.0, c.Percentile(3))
+	})
+
+	t.Run("ShouldScoreOnlyOwnTraitsWithoutOptions", func(t *testing.T) {
+		c := Compute(testItems(), Options{})
+
+		require.Zero(t, c.Frequency(Trait{Key: "Hat", Value: MissingValue}).Count)
+		require.Zero(t, c.Frequency(Trait{Key: TraitCountKey, Value: "1"}).Count)
+		d, _ := c.Item("d")
+		require.Equal(t, []Trait{{Key: "Eyes", Value: "Red"}}, d.Traits)
+		require.InDelta(t, 4, d.Scores.RarityScore, 1e-9)
+		// c's Crown and Blue outweigh d's single Red trait
+		require.Equal(t, []int{3, 3, 1, 2}, ranks(c, MethodRarityScore))
+	})
+
+	t.Run("ShouldCountUniqueTraits", func(t *testing.T) {
+		c := Compute(testItems(), DefaultOptions())
+		// d's missing hat and trait count of 1 are unique too, but not its own
+		require.Equal(t, 1, c.UniqueTraits("d"))
+		require.Equal(t, 1, c.UniqueTraits("c"))
+		require.Zero(t, c.UniqueTraits("a"))
+		require.Zero(t, c.UniqueTraits("z"))
+	})
+
+	t.Run("ShouldIgnoreDuplicates", func(t *testing.T) {
+		items := append(testItems(), Item{ID: "a", Traits: []Trait{{Key: "Hat", Value: "Halo"}}})
+		items[1].Traits = append(items[1].Traits, Trait{Key: "Hat", Value: "Cap"}, Trait{Value: "no key"})
+
+		c := Compute(items, DefaultOptions())
+		require.Equal(t, 4, c.Total())
+		require.Zero(t, c.Frequency(Trait{Key: "Hat", Value: "Halo"}).Count)
+		require.Equal(t, 3, c.Frequency(Trait{Key: TraitCountKey, Value: "2"}).Count)
+	})
+
+	t.Run("ShouldTieEveryItemOfUniformCollection", func(t *testing.T) {
+		c := Compute([]Item{
+			{ID: "a", Traits: []Trait{{Key: "Hat", Value: "Cap"}}},
+			{ID: "b", Traits: []Trait{{Key: "Hat", Value: "Cap"}}},
+		}, DefaultOptions())
+
+		a, _ := c.Item("a")
+		require.Zero(t, a.Scores.InformationContent)
+		require.Equal(t, 1, a.Ranks[MethodInformationContent])
+		b, _ := c.Item("b")
+		require.Equal(t, 1, b.Ranks[MethodStatistical])
+	})
+
+	t.Run("ShouldScoreIdenticallyOnEveryRun", func(t *testing.T) {
+		var items []Item
+		for i := range 50 {
+			items = append(items, Item{ID: strconv.Itoa(i), Traits: []Trait{
+				{Key: "Hat", Value: strconv.Itoa(i % 7)},
+				{Key: "Eyes", Value: strconv.Itoa(i % 11)},
+				{Key: "Mouth", Value: strconv.Itoa(i % 13)},
+			}})
+		}
+
+		want, _ := Compute(items, DefaultOptions()).Item("0")
+		for range 20 {
+			got, _ := Compute(items, DefaultOptions()).Item("0")
+			require.Equal(t, math.Float64bits(want.Scores.InformationContent), math.Float64bits(got.Scores.InformationContent))
+		}
+	})
+
+	t.Run("ShouldHandleEmptyCollection", func(t *testing.T) {
+		c := Compute(nil, DefaultOptions())
+		require.Zero(t, c.Total())
+		require.Empty(t, c.Ranked(MethodInformationContent))
+		_, ok := c.Item("a")
+		require.False(t, ok)
+	})
+}
+
+func TestContributions(t *testing.T) {
+	c := Compute(testItems(), DefaultOptions())
+
+	t.Run("ShouldSplitScoreByTrait", func(t *testing.T) {
+		contributions, ok := c.Contributions("a", MethodRarityScore)
+		require.True(t, ok)
+		require.Len(t, contributions, 3)
+
+		require.Equal(t, Trait{Key: "Hat", Value: "Cap"}, contributions[0].Trait)
+		require.Equal(t, 2, contributions[0].Count)
+		require.InDelta(t, 2, contributions[0].Value, 1e-9)
+		require.Equal(t, 42.86, contributions[0].Share)
+		require.False(t, contributions[0].Synthetic)
+		require.Equal(t, Trait{Key: TraitCountKey, Value: "2"}, contributions[2].Trait)
+		require.True(t, contributions[2].Synthetic)
+
+		item, _ := c.Item("a")
+		sum := 0.0
+		for _, contribution := range contributions {
+			sum += contribution.Value
+		}
+		require.InDelta(t, item.Scores.RarityScore, sum, 1e-9)
+	})
+
+	t.Run("ShouldShareStatisticalScoreByInformation", func(t *testing.T) {
+		contributions, _ := c.Contributions("a", MethodStatistical)
+		require.Equal(t, 0.5, contributions[0].Value)
+		require.Equal(t, []float64{54.64, 22.68, 22.68}, []float64{contributions[0].Share, contributions[1].Share, contributions[2].Share})
+	})
+
+	t.Run("ShouldMarkMissingTraitsSynthetic", func(t *testing.T) {
+		contributions, _ := c.Contributions("d", MethodInformationContent)
+		require.Equal(t, []Trait{{Key: "Eyes", Value: "Red"}, {Key: "Hat", Value: MissingValue}, {Key: TraitCountKey, Value: "1"}},
+			[]Trait{contributions[0].Trait, contributions[1].Trait, contributions[2].Trait})
+		require.Equal(t, []bool{false, true, true}, []bool{contributions[0].Synthetic, contributions[1].Synthetic, contributions[2].Synthetic})
+		require.Equal(t, 33.33, contributions[0].Share)
+	})
+
+	t.Run("ShouldReportUnknownItem", func(t *testing.T) {
+		_, ok := c.Contributions("z", MethodInformationContent)
+		require.False(t, ok)
+	})
+}
+
+func TestCombinations(t *testing.T) {
+	c := Compute(testItems(), DefaultOptions())
+
+	t.Run("ShouldCountPairsRarestFirst", func(t *testing.T) {
+		combinations := c.Combinations(2)
+		require.Equal(t, []Combination{
+			{Traits: []Trait{{Key: "Eyes", Value: "Blue"}, {Key: "Hat", Value: "Crown"}}, Count: 1, Frequency: 0.25},
+			{Traits: []Trait{{Key: "Eyes", Value: "Blue"}, {Key: "Hat", Value: "Cap"}}, Count: 2, Frequency: 0.5},
+		}, combinations)
+	})
+
+	t.Run("ShouldNotCombineTraitsOfTheSameKey", func(t *testing.T) {
+		multi := Compute([]Item{{ID: "a", Traits: []Trait{{Key: "Hat", Value: "Cap"}, {Key: "Hat", Value: "Crown"}, {Key: "Eyes", Value: "Red"}}}}, DefaultOptions())
+		require.Len(t, multi.Combinations(2), 2)
+		require.Empty(t, multi.Combinations(3))
+	})
+
+	t.Run("ShouldCountSpecificCombination", func(t *testing.T) {
+		combination := c.Combination([]Trait{{Key: "Hat", Value: "Cap"}, {Key: "Eyes", Value: "Blue"}})
+		require.Equal(t, []Trait{{Key: "Eyes", Value: "Blue"}, {Key: "Hat", Value: "Cap"}}, combination.Traits)
+		require.Equal(t, 2, combination.Count)
+		require.Equal(t, 0.5, combination.Frequency)
+
+		require.Equal(t, 1, c.Combination([]Trait{{Key: "Hat", Value: MissingValue}, {Key: "Eyes", Value: "Red"}}).Count)
+		require.Zero(t, c.Combination([]Trait{{Key: "Hat", Value: "Cap"}, {Key: "Eyes", Value: "Red"}}).Count)
+		require.Zero(t, c.Combination(nil).Count)
+	})
+}
+
+func TestStats(t *testing.T) {
+	stats := Compute(testItems(), DefaultOptions()).Stats()
+
+	require.Equal(t, 4, stats.Items)
+	require.Equal(t, map[int]int{1: 1, 2: 3}, stats.TraitCounts)
+	require.Equal(t, []string{"Eyes", "Hat"}, []string{stats.Keys[0].Key, stats.Keys[1].Key})
+
+	eyes := stats.Keys[0]
+	require.Equal(t, 4, eyes.Items)
+	require.Zero(t, eyes.Missing)
+	require.Equal(t, []TraitFrequency{
+		{Key: "Eyes", Value: "Blue", Count: 3, Frequency: 0.75},
+		{Key: "Eyes", Value: "Red", Count: 1, Frequency: 0.25},
+	}, eyes.Values)
+	require.InDelta(t, 0.811278, eyes.Entropy, 1e-6)
+
+	hat := stats.Keys[1]
+	require.Equal(t, 3, hat.Items)
+	require.Equal(t, 1, hat.Missing)
+	require.Len(t, hat.Values, 2)
+	// Cap, Crown and the missing hat split the collection 2:1:1
+	require.InDelta(t, 1.5, hat.Entropy, 1e-9)
+}
+
+func TestSimilar(t *testing.T) {
+	c := Compute(testItems(), DefaultOptions())
+
+	t.Run("ShouldRankByJaccard", func(t *testing.T) {
+		matches, ok := c.Similar("a", SimilarityJaccard, nil)
+		require.True(t, ok)
+		// d shares no trait with a and is left out
+		require.Equal(t, []string{"b", "c"}, []string{matches[0].ID, matches[1].ID})
+		require.Equal(t, 1.0, matches[0].Score)
+		require.InDelta(t, 1.0/3, matches[1].Score, 1e-9)
+
+		require.Equal(t, []Trait{{Key: "Eyes", Value: "Blue"}}, matches[1].Shared)
+		require.Equal(t, []Trait{{Key: "Hat", Value: "Cap"}}, matches[1].Only)
+		require.Equal(t, []Trait{{Key: "Hat", Value: "Crown"}}, matches[1].Other)
+	})
+
+	t.Run("ShouldWeighRareTraitsMore", func(t *testing.T) {
+		matches, _ := c.Similar("c", SimilarityWeighted, nil)
+		// c shares only common Blue eyes with a and b, whose Cap is less rare than c's Crown
+		require.Equal(t, []string{"a", "b"}, []string{matches[0].ID, matches[1].ID})
+		blue, capHat, crown := math.Log2(4.0/3), 1.0, 2.0
+		require.InDelta(t, blue/(blue+capHat+crown), matches[0].Score, 1e-9)
+	})
+
+	t.Run("ShouldExcludeKeys", func(t *testing.T) {
+		matches, _ := c.Similar("c", SimilarityJaccard, []string{"Hat"})
+		require.Len(t, matches, 2)
+		require.Equal(t, 1.0, matches[0].Score)
+		require.Empty(t, matches[0].Other)
+	})
+
+	t.Run("ShouldReportUnknownItem", func(t *testing.T) {
+		_, ok := c.Similar("z", SimilarityJaccard, nil)
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,207 @@
+package rarity
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Unicode normalisation forms traits can be normalised to
+const (
+	UnicodeNFC  = "NFC"
+	UnicodeNFD  = "NFD"
+	UnicodeNFKC = "NFKC"
+	UnicodeNFKD = "NFKD"
+)
+
+var unicodeForms = map[string]norm.Form{
+	UnicodeNFC:  norm.NFC,
+	UnicodeNFD:  norm.NFD,
+	UnicodeNFKC: norm.NFKC,
+	UnicodeNFKD: norm.NFKD,
+}
+
+// NormalizationRules controls how trait keys and values are cleaned up before
+// they are counted, so spellings such as "Gold", "gold " and "GOLD" count as one trait
+type NormalizationRules struct {
+	// UnicodeForm is one of NFC, NFD, NFKC or NFKD, empty leaves text as is
+	UnicodeForm string
+	// TrimSpace trims keys and values and collapses inner runs of whitespace
+	TrimSpace bool
+	// CaseFold folds keys and values to lower case
+	CaseFold bool
+	// IgnoredKeys are dropped from every collection, e.g. "Generation"
+	IgnoredKeys []string
+	// Collections holds extra rules by collection id
+	Collections map[string]CollectionRules
+}
+
+// CollectionRules are normalisation rules specific to one collection. Every key
+// and value in them goes through the text rules too, so they match and merge with
+// traits however they are spelled.
+type CollectionRules struct {
+	IgnoredKeys []string `json:"ignoredKeys"`
+	// KeyAliases maps alternative keys to their canonical key
+	KeyAliases map[string]string `json:"keyAliases"`
+	// ValueAliases maps, by canonical key, alternative values to their canonical value
+	ValueAliases map[string]map[string]string `json:"valueAliases"`
+	// Buckets groups numeric values of a key, by canonical key, into ranges split at the ascending bounds
+	Buckets map[string][]float64 `json:"buckets"`
+}
+
+// Normalizer applies NormalizationRules to traits. A nil Normalizer leaves traits unchanged.
+type Normalizer struct {
+	form        *norm.Form
+	trimSpace   bool
+	caseFold    bool
+	ignoredKeys []string
+	collections map[string]collectionRules
+}
+
+// collectionRules are CollectionRules with every key and value replaced by its normalised text
+type collectionRules struct {
+	ignoredKeys  []string
+	keyAliases   map[string]string
+	valueAliases map[string]map[string]string
+	buckets      map[string][]float64
+}
+
+// NewNormalizer validates rules and returns a Normalizer applying them
+func NewNormalizer(rules NormalizationRules) (*Normalizer, error) {
+	n := &Normalizer{
+		trimSpace:   rules.TrimSpace,
+		caseFold:    rules.CaseFold,
+		collections: make(map[string]collectionRules, len(rules.Collections)),
+	}
+	if rules.UnicodeForm != "" {
+		form, ok := unicodeForms[strings.ToUpper(rules.UnicodeForm)]
+		if !ok {
+			return nil, fmt.Errorf("unicode form must be %s, %s, %s or %s", UnicodeNFC, UnicodeNFD, UnicodeNFKC, UnicodeNFKD)
+		}
+		n.form = &form
+	}
+	n.ignoredKeys = n.texts(rules.IgnoredKeys)
+
+	for collectionID, collection := range rules.Collections {
+		compiled := collectionRules{
+			ignoredKeys:  n.texts(collection.IgnoredKeys),
+			keyAliases:   make(map[string]string, len(collection.KeyAliases)),
+			valueAliases: make(map[string]map[string]string, len(collection.ValueAliases)),
+			buckets:      make(map[string][]float64, len(collection.Buckets)),
+		}
+		for alias, key := range collection.KeyAliases {
+			compiled.keyAliases[n.text(alias)] = n.text(key)
+		}
+		for key, aliases := range collection.ValueAliases {
+			values := make(map[string]string, len(aliases))
+			for alias, value := range aliases {
+				values[n.text(alias)] = n.text(value)
+			}
+			compiled.valueAliases[n.text(key)] = values
+		}
+		for key, bounds := range collection.Buckets {
+			if len(bounds) == 0 || !slices.IsSorted(bounds) || len(slices.Compact(slices.Clone(bounds))) != len(bounds) {
+				return nil, fmt.Errorf("collection %s: buckets of %q must be strictly ascending bounds", collectionID, key)
+			}
+			compiled.buckets[n.text(key)] = bounds
+		}
+		n.collections[collectionID] = compiled
+	}
+	return n, nil
+}
+
+// Normalize applies every rule, bucketing included, to the traits of an item of collectionID.
+// Ignored traits are dropped and traits that end up the same are kept once.
+func (n *Normalizer) Normalize(collectionID string, traits []Trait) []Trait {
+	return n.normalize(collectionID, traits, true)
+}
+
+// NormalizeText applies every rule but bucketing, for traits matched against raw values,
+// such as the ones Rarible reports rarity for, which knows no bucket labels
+func (n *Normalizer) NormalizeText(collectionID string, traits []Trait) []Trait {
+	return n.normalize(collectionID, traits, false)
+}
+
+// Bucketed reports whether the values of key, in its normalised form, are bucketed in collectionID
+func (n *Normalizer) Bucketed(collectionID, key string) bool {
+	if n == nil {
+		return false
+	}
+	_, ok := n.collections[collectionID].buckets[key]
+	return ok
+}
+
+func (n *Normalizer) normalize(collectionID string, traits []Trait, bucket bool) []Trait {
+	if n == nil {
+		return traits
+	}
+
+	rules := n.collections[collectionID]
+	normalized := make([]Trait, 0, len(traits))
+	for _, trait := range traits {
+		key := n.text(trait.Key)
+		if alias, ok := rules.keyAliases[key]; ok {
+			key = alias
+		}
+		if slices.Contains(n.ignoredKeys, key) || slices.Contains(rules.ignoredKeys, key) {
+			continue
+		}
+
+		value := n.text(trait.Value)
+		if alias, ok := rules.valueAliases[key][value]; ok {
+			value = alias
+		}
+		if bounds, ok := rules.buckets[key]; ok && bucket {
+			value = bucketLabel(value, bounds)
+		}
+
+		normalized = append(normalized, Trait{Key: key, Value: value})
+	}
+	return dedupeTraits(normalized)
+}
+
+// text applies the unicode, whitespace and case rules to s
+func (n *Normalizer) text(s string) string {
+	if n.form != nil {
+		s = n.form.String(s)
+	}
+	if n.trimSpace {
+		s = strings.Join(strings.Fields(s), " ")
+	}
+	if n.caseFold {
+		// a Caser keeps state, so one is made per call to stay safe for concurrent use
+		s = cases.Fold().String(s)
+	}
+	return s
+}
+
+func (n *Normalizer) texts(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, n.text(value))
+	}
+	return result
+}
+
+// bucketLabel returns the range value falls in, "<b0", "b0-b1", ..., ">=bn", or value itself when it is not a number
+func bucketLabel(value string, bounds []float64) string {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return value
+	}
+
+	format := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	if f < bounds[0] {
+		return "<" + format(bounds[0])
+	}
+	for i := 1; i < len(bounds); i++ {
+		if f < bounds[i] {
+			return format(bounds[i-1]) + "-" + format(bounds[i])
+		}
+	}
+	return ">=" + format(bounds[len(bounds)-1])
+}
@@ -0,0 +1,77 @@
+package rarity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizer(t *testing.T) {
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+	normalizer, err := NewNormalizer(NormalizationRules{
+		UnicodeForm: "nfkc",
+		TrimSpace:   true,
+		CaseFold:    true,
+		IgnoredKeys: []string{"Generation"},
+		Collections: map[string]CollectionRules{
+			collectionID: {
+				IgnoredKeys:  []string{"Serial"},
+				KeyAliases:   map[string]string{"BG": "Background"},
+				ValueAliases: map[string]map[string]string{"Background": {"Golden": "Gold"}},
+				Buckets:      map[string][]float64{"Level": {10, 50}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("ShouldMergeSpellings", func(t *testing.T) {
+		for _, value := range []string{"Gold", "gold ", "GOLD", " g o l d"[0:0] + "Gold "} {
+			traits := normalizer.Normalize("", []Trait{{Key: " Background", Value: value}})
+			require.Equal(t, []Trait{{Key: "background", Value: "gold"}}, traits, value)
+		}
+		// NFKC turns the ligature into plain letters before case folding
+		require.Equal(t, []Trait{{Key: "eyes", Value: "fire"}}, normalizer.Normalize("", []Trait{{Key: "Eyes", Value: "ﬁre"}}))
+	})
+
+	t.Run("ShouldApplyCollectionRules", func(t *testing.T) {
+		traits := normalizer.Normalize(collectionID, []Trait{
+			{Key: "bg", Value: "GOLDEN"},
+			{Key: "Background", Value: "gold"},
+			{Key: "Generation", Value: "1"},
+			{Key: "serial", Value: "42"},
+			{Key: "Level", Value: "12"},
+		})
+		require.Equal(t, []Trait{{Key: "background", Value: "gold"}, {Key: "level", Value: "10-50"}}, traits)
+	})
+
+	t.Run("ShouldBucketNumericValuesOnly", func(t *testing.T) {
+		bucket := func(value string) string {
+			return normalizer.Normalize(collectionID, []Trait{{Key: "Level", Value: value}})[0].Value
+		}
+		require.Equal(t, "<10", bucket("3"))
+		require.Equal(t, "10-50", bucket("10"))
+		require.Equal(t, ">=50", bucket("50.5"))
+		require.Equal(t, "max", bucket("MAX"))
+
+		require.Equal(t, "12", normalizer.NormalizeText(collectionID, []Trait{{Key: "Level", Value: "12"}})[0].Value)
+		require.True(t, normalizer.Bucketed(collectionID, "level"))
+		require.False(t, normalizer.Bucketed("", "level"))
+	})
+
+	t.Run("ShouldPassThroughWhenNil", func(t *testing.T) {
+		var nilNormalizer *Normalizer
+		traits := []Trait{{Key: "Background", Value: "Gold "}}
+		require.Equal(t, traits, nilNormalizer.Normalize(collectionID, traits))
+	})
+
+	t.Run("ShouldRejectInvalidRules", func(t *testing.T) {
+		_, err := NewNormalizer(NormalizationRules{UnicodeForm: "NFX"})
+		require.Error(t, err)
+
+		_, err = NewNormalizer(NormalizationRules{Collections: map[string]CollectionRules{
+			collectionID: {Buckets: map[string][]float64{"Level": {50, 10}}},
+		}})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,110 @@
+package rarity
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Similarity measures how alike two items' traits are, from 0 for nothing in common to 1 for the same traits
+type Similarity string
+
+const (
+	// SimilarityJaccard is the number of shared traits over the number of traits either item has
+	SimilarityJaccard Similarity = "jaccard"
+	// SimilarityWeighted is Jaccard with every trait weighted by its information, -log2 p,
+	// so sharing a rare trait counts for more than sharing a common one
+	SimilarityWeighted Similarity = "weighted"
+)
+
+// Similarities lists every similarity measure
+var Similarities = []Similarity{SimilarityJaccard, SimilarityWeighted}
+
+// Valid reports whether s is a known similarity measure
+func (s Similarity) Valid() bool {
+	return slices.Contains(Similarities, s)
+}
+
+// Match is an item similar to another one
+type Match struct {
+	ID    string
+	Score float64
+	// Shared are the traits both items have, Only the ones only the compared item has and Other the ones only the match has
+	Shared []Trait
+	Only   []Trait
+	Other  []Trait
+}
+
+// Similar compares the item with id with every other item of the collection by their own traits,
+// leaving out traits whose key is in excludedKeys, and returns the items sharing at least one trait
+// with it, most similar first. It reports false when the collection has no item with id.
+func (c *Collection) Similar(id string, measure Similarity, excludedKeys []string) ([]Match, bool) {
+	i, ok := c.index[id]
+	if !ok {
+		return nil, false
+	}
+
+	traits := c.similarityTraits(i, excludedKeys)
+	var matches []Match
+	for j := range c.items {
+		if j == i {
+			continue
+		}
+
+		other := c.similarityTraits(j, excludedKeys)
+		match := Match{ID: c.items[j].ID}
+		var shared, union float64
+		for _, trait := range traits {
+			weight := c.similarityWeight(trait, measure)
+			union += weight
+			if slices.Contains(other, trait) {
+				shared += weight
+				match.Shared = append(match.Shared, trait)
+			} else {
+				match.Only = append(match.Only, trait)
+			}
+		}
+		for _, trait := range other {
+			if !slices.Contains(traits, trait) {
+				union += c.similarityWeight(trait, measure)
+				match.Other = append(match.Other, trait)
+			}
+		}
+		if len(match.Shared) == 0 {
+			continue
+		}
+		// traits every item has weigh nothing, items sharing only those are alike in every respect that counts
+		match.Score = 1
+		if union > 0 {
+			match.Score = shared / union
+		}
+		matches = append(matches, match)
+	}
+
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		if math.Abs(a.Score-b.Score) <= 1e-9*math.Max(a.Score, b.Score) {
+			return cmp.Compare(a.ID, b.ID)
+		}
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return matches, true
+}
+
+// similarityTraits returns the own traits of the item at index i whose key is not excluded
+func (c *Collection) similarityTraits(i int, excludedKeys []string) []Trait {
+	own := c.items[i].Traits[:c.own[i]]
+	traits := make([]Trait, 0, len(own))
+	for _, trait := range own {
+		if !slices.Contains(excludedKeys, trait.Key) {
+			traits = append(traits, trait)
+		}
+	}
+	return traits
+}
+
+func (c *Collection) similarityWeight(trait Trait, measure Similarity) float64 {
+	if measure == SimilarityWeighted {
+		return -math.Log2(c.probability(c.counts[trait]))
+	}
+	return 1
+}
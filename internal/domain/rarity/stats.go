@@ -0,0 +1,82 @@
+package rarity
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// KeyStats describes how the values of one trait key are distributed over a collection
+type KeyStats struct {
+	Key string
+	// Items is the number of items with the key, Missing the number without it
+	Items   int
+	Missing int
+	// Values are the key's values, most common first
+	Values []TraitFrequency
+	// Entropy is the Shannon entropy, in bits, of the key's values over every
+	// item, items missing the key counting as one more value
+	Entropy float64
+}
+
+// Stats describes the traits of a collection. Only the items' own traits are
+// described, never the synthetic ones scoring may add.
+type Stats struct {
+	Items int
+	// Keys are sorted by key
+	Keys []KeyStats
+	// TraitCounts maps a number of traits to how many items have that many
+	TraitCounts map[int]int
+}
+
+// Stats computes the distribution of every trait key of the collection
+func (c *Collection) Stats() Stats {
+	stats := Stats{Items: c.total, TraitCounts: make(map[int]int)}
+
+	counts := make(map[string]map[string]int)
+	itemsWithKey := make(map[string]int)
+	for i, item := range c.items {
+		own := item.Traits[:c.own[i]]
+		stats.TraitCounts[len(own)]++
+
+		seen := make(map[string]bool, len(own))
+		for _, trait := range own {
+			if counts[trait.Key] == nil {
+				counts[trait.Key] = make(map[string]int)
+			}
+			counts[trait.Key][trait.Value]++
+			if !seen[trait.Key] {
+				seen[trait.Key] = true
+				itemsWithKey[trait.Key]++
+			}
+		}
+	}
+
+	for key, values := range counts {
+		keyStats := KeyStats{
+			Key:     key,
+			Items:   itemsWithKey[key],
+			Missing: c.total - itemsWithKey[key],
+			Values:  make([]TraitFrequency, 0, len(values)),
+		}
+		for value, count := range values {
+			keyStats.Values = append(keyStats.Values, TraitFrequency{Key: key, Value: value, Count: count, Frequency: c.probability(count)})
+		}
+		slices.SortFunc(keyStats.Values, func(a, b TraitFrequency) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Value, b.Value))
+		})
+
+		for _, value := range keyStats.Values {
+			keyStats.Entropy -= value.Frequency * math.Log2(value.Frequency)
+		}
+		if keyStats.Missing > 0 {
+			p := c.probability(keyStats.Missing)
+			keyStats.Entropy -= p * math.Log2(p)
+		}
+		stats.Keys = append(stats.Keys, keyStats)
+	}
+	slices.SortFunc(stats.Keys, func(a, b KeyStats) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return stats
+}
@@ -0,0 +1,67 @@
+package resolver
+
+import (
+	"regexp"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+)
+
+// defaultPatterns lists the URL layouts recognised out of the box. Support for
+// another site is added by appending its patterns here or registering them at runtime.
+func defaultPatterns() []Pattern {
+	rarible := map[string]model.Blockchain{
+		"rarible.com":         model.BlockchainEthereum,
+		"testnet.rarible.com": model.BlockchainEthereum,
+	}
+	opensea := map[string]model.Blockchain{
+		"opensea.io":          model.BlockchainEthereum,
+		"testnets.opensea.io": model.BlockchainEthereum,
+	}
+	explorers := map[string]model.Blockchain{
+		"etherscan.io":                   model.BlockchainEthereum,
+		"polygonscan.com":                model.BlockchainPolygon,
+		"arbiscan.io":                    model.BlockchainArbitrum,
+		"basescan.org":                   model.BlockchainBase,
+		"explorer.mantle.xyz":            model.BlockchainMantle,
+		"era.zksync.network":             model.BlockchainZkSync,
+		"explorer.zksync.io":             model.BlockchainZkSync,
+		"phoenix.lightlink.io":           model.BlockchainLightlink,
+		"mainnet.explorer.rarichain.org": model.BlockchainRari,
+	}
+	magicEden := map[string]model.Blockchain{
+		"magiceden.io": "",
+		"magiceden.us": "",
+	}
+
+	return []Pattern{
+		// rarible.com/token/solana/<mint>
+		{Source: "rarible", Hosts: rarible, Path: regexp.MustCompile(`^/token/solana/(?P<mint>[1-9A-HJ-NP-Za-km-z]{32,44})/?$`)},
+		// rarible.com/token/0x...:123, rarible.com/token/polygon/0x...:123
+		{Source: "rarible", Hosts: rarible, Path: regexp.MustCompile(`^/token/(?:(?P<chain>[a-z]+)/)?(?P<contract>[^/:]+):(?P<token>[^/:]+)/?$`)},
+		// rarible.com/collection/0x..., rarible.com/collection/polygon/0x.../items
+		{Source: "rarible", Hosts: rarible, Path: regexp.MustCompile(`^/collection/(?:(?P<chain>[a-z]+)/)?(?P<contract>[^/:]+)(?:/.*)?$`)},
+		// rarible.com/ethereum/items/0x...:123
+		{Source: "rarible", Hosts: rarible, Path: regexp.MustCompile(`^/(?P<chain>[a-z]+)/items/(?P<contract>[^/:]+):(?P<token>[^/:]+)/?$`)},
+		// rarible.com/ethereum/collections/0x...
+		{Source: "rarible", Hosts: rarible, Path: regexp.MustCompile(`^/(?P<chain>[a-z]+)/collections/(?P<contract>[^/:]+)(?:/.*)?$`)},
+
+		// opensea.io/assets/solana/<mint>
+		{Source: "opensea", Hosts: opensea, Path: regexp.MustCompile(`^/(?:assets|item)/solana/(?P<mint>[1-9A-HJ-NP-Za-km-z]{32,44})/?$`)},
+		// opensea.io/assets/ethereum/0x.../123, opensea.io/item/matic/0x.../123
+		{Source: "opensea", Hosts: opensea, Path: regexp.MustCompile(`^/(?:assets|item)/(?P<chain>[a-z_]+)/(?P<contract>[^/]+)/(?P<token>[^/]+)/?$`)},
+		// opensea.io/assets/0x.../123, the legacy Ethereum-only layout
+		{Source: "opensea", Hosts: opensea, Path: regexp.MustCompile(`^/assets/(?P<contract>0x[0-9a-fA-F]{40})/(?P<token>[0-9]+)/?$`)},
+
+		// etherscan.io/nft/0x.../123
+		{Source: "etherscan", Hosts: explorers, Path: regexp.MustCompile(`^/nft/(?P<contract>0x[0-9a-fA-F]{40})/(?P<token>[0-9]+)/?$`)},
+		// etherscan.io/token/0x..., etherscan.io/token/0x...?a=123
+		{Source: "etherscan", Hosts: explorers, Path: regexp.MustCompile(`^/token/(?P<contract>0x[0-9a-fA-F]{40})/?$`), Query: map[string]string{"a": groupToken}},
+
+		// magiceden.io/item-details/<mint>
+		{Source: "magiceden", Hosts: magicEden, Path: regexp.MustCompile(`^/item-details/(?P<mint>[1-9A-HJ-NP-Za-km-z]{32,44})/?$`)},
+		// magiceden.io/item-details/ethereum/0x.../123
+		{Source: "magiceden", Hosts: magicEden, Path: regexp.MustCompile(`^/item-details/(?P<chain>[a-z]+)/(?P<contract>[^/]+)/(?P<token>[^/]+)/?$`)},
+		// magiceden.io/collections/ethereum/0x...
+		{Source: "magiceden", Hosts: magicEden, Path: regexp.MustCompile(`^/collections/(?P<chain>[a-z]+)/(?P<contract>0x[0-9a-fA-F]{40})/?$`)},
+	}
+}
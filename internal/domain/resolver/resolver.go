@@ -0,0 +1,269 @@
+// Package resolver turns marketplace and explorer URLs, CAIP-19 asset ids and
+// Rarible ids into normalised Rarible identifiers
+package resolver
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/Megidy/rarible/internal/domain/model"
+)
+
+const (
+	SourceRaribleID = "rarible-id"
+	SourceCAIP19    = "caip-19"
+)
+
+// Groups a Pattern path may capture
+const (
+	groupChain    = "chain"
+	groupContract = "contract"
+	groupToken    = "token"
+	groupMint     = "mint"
+	groupOwner    = "owner"
+)
+
+var evmAccountRe = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// chainAliases maps chain names used in marketplace URLs that differ from Rarible's
+var chainAliases = map[string]model.Blockchain{
+	"eth":        model.BlockchainEthereum,
+	"matic":      model.BlockchainPolygon,
+	"arbitrum1":  model.BlockchainArbitrum,
+	"arb":        model.BlockchainArbitrum,
+	"imx":        model.BlockchainImmutableX,
+	"zksync_era": model.BlockchainZkSync,
+}
+
+// Pattern recognises one URL layout of a site. Path captures the named groups
+// chain, contract, token, mint and owner; a URL naming a contract and token
+// resolves to an item, a contract alone to a collection and a mint to a Solana item.
+type Pattern struct {
+	// Source is reported back to the caller, e.g. "opensea"
+	Source string
+	// Hosts maps each host the pattern applies to, without a www. prefix, to the
+	// blockchain assumed when the path has no chain group. Use "" for none.
+	Hosts map[string]model.Blockchain
+	Path  *regexp.Regexp
+	// Query maps query parameters to the group they fill, e.g. {"a": "token"}
+	Query map[string]string
+}
+
+// Identifiers are the normalised ids an input resolves to. Collection is nil
+// for Solana items, Item is nil for collections and Ownership is only set
+// once an owner is known. Owner is set instead of Ownership when the input
+// names a holder but no item, such as an explorer's holder view of a collection.
+type Identifiers struct {
+	Source     string
+	Blockchain model.Blockchain
+	Collection *model.CollectionID
+	Item       *model.ItemID
+	Ownership  *model.OwnershipID
+	Owner      *model.Address
+}
+
+// WithOwner narrows item identifiers down to the ownership of owner
+func (ids Identifiers) WithOwner(owner string) (Identifiers, error) {
+	if ids.Item == nil {
+		return Identifiers{}, fmt.Errorf("%w: owner requires an input resolving to an item", businesserrors.ErrInvalidRequest)
+	}
+	ownership, err := model.ParseOwnershipID(ids.Item.String() + ":" + owner)
+	if err != nil {
+		return Identifiers{}, err
+	}
+	ownership = ownership.Normalized()
+	ids.Ownership = &ownership
+	return ids, nil
+}
+
+// Registry holds the URL patterns tried in registration order
+type Registry struct {
+	mu       sync.RWMutex
+	patterns []Pattern
+}
+
+func NewRegistry(patterns ...Pattern) *Registry {
+	return &Registry{patterns: patterns}
+}
+
+// DefaultRegistry returns a registry recognising Rarible, OpenSea, Etherscan-family and Magic Eden URLs
+func DefaultRegistry() *Registry {
+	return NewRegistry(defaultPatterns()...)
+}
+
+// Register appends a pattern, tried after every pattern registered before it
+func (r *Registry) Register(pattern Pattern) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.patterns = append(r.patterns, pattern)
+}
+
+// Resolve resolves a URL, CAIP-19 asset id or Rarible id. Inputs nothing
+// recognises fail with businesserrors.ErrInvalidRequest.
+func (r *Registry) Resolve(input string) (Identifiers, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return Identifiers{}, fmt.Errorf("%w: input is required", businesserrors.ErrInvalidRequest)
+	}
+
+	if !strings.Contains(input, "://") {
+		return resolveID(input)
+	}
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return Identifiers{}, fmt.Errorf("%w: invalid url: %v", businesserrors.ErrInvalidRequest, err)
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, pattern := range r.patterns {
+		hostChain, ok := pattern.Hosts[host]
+		if !ok {
+			continue
+		}
+		match := pattern.Path.FindStringSubmatch(u.Path)
+		if match == nil {
+			continue
+		}
+
+		groups := make(map[string]string)
+		for i, name := range pattern.Path.SubexpNames() {
+			if name != "" && match[i] != "" {
+				groups[name] = match[i]
+			}
+		}
+		for param, group := range pattern.Query {
+			value := u.Query().Get(param)
+			// explorers reuse the same parameter for token ids and holder addresses
+			if group == groupToken && evmAccountRe.MatchString(value) {
+				group = groupOwner
+			}
+			if value != "" {
+				groups[group] = value
+			}
+		}
+		return pattern.build(groups, hostChain)
+	}
+	return Identifiers{}, fmt.Errorf("%w: no resolver pattern matches %q", businesserrors.ErrInvalidRequest, input)
+}
+
+func (p Pattern) build(groups map[string]string, chain model.Blockchain) (Identifiers, error) {
+	if mint := groups[groupMint]; mint != "" {
+		item, err := model.ParseItemID(string(model.BlockchainSolana) + ":" + mint)
+		if err != nil {
+			return Identifiers{}, err
+		}
+		return withOwner(fromAsset(p.Source, nil, &item), groups[groupOwner])
+	}
+
+	if name := groups[groupChain]; name != "" {
+		var err error
+		if chain, err = lookupBlockchain(name); err != nil {
+			return Identifiers{}, err
+		}
+	}
+	if chain == "" {
+		return Identifiers{}, fmt.Errorf("%w: %s url does not name a blockchain", businesserrors.ErrInvalidRequest, p.Source)
+	}
+
+	contract, token := groups[groupContract], groups[groupToken]
+	if token == "" {
+		collection, err := model.ParseCollectionID(string(chain) + ":" + contract)
+		if err != nil {
+			return Identifiers{}, err
+		}
+		ids := fromAsset(p.Source, &collection, nil)
+		if owner := groups[groupOwner]; owner != "" {
+			address, err := model.ParseAddress(string(chain) + ":" + owner)
+			if err != nil {
+				return Identifiers{}, err
+			}
+			address = address.Normalized()
+			ids.Owner = &address
+		}
+		return ids, nil
+	}
+
+	item, err := model.ParseItemID(strings.Join([]string{string(chain), contract, token}, ":"))
+	if err != nil {
+		return Identifiers{}, err
+	}
+	ids := fromAsset(p.Source, nil, &item)
+	return withOwner(ids, groups[groupOwner])
+}
+
+func withOwner(ids Identifiers, owner string) (Identifiers, error) {
+	if owner == "" {
+		return ids, nil
+	}
+	return ids.WithOwner(owner)
+}
+
+// resolveID resolves an ownership, item or collection id in either Rarible or CAIP form
+func resolveID(input string) (Identifiers, error) {
+	source := SourceRaribleID
+	if strings.Contains(input, "/") {
+		source = SourceCAIP19
+	}
+
+	ownership, ownershipErr := model.ParseOwnershipID(input)
+	if ownershipErr == nil {
+		ownership = ownership.Normalized()
+		ids := fromAsset(source, nil, &ownership.Item)
+		ids.Ownership = &ownership
+		return ids, nil
+	}
+	item, itemErr := model.ParseItemID(input)
+	if itemErr == nil {
+		return fromAsset(source, nil, &item), nil
+	}
+	collection, collectionErr := model.ParseCollectionID(input)
+	if collectionErr == nil {
+		return fromAsset(source, &collection, nil), nil
+	}
+
+	if source == SourceRaribleID {
+		return Identifiers{}, fmt.Errorf("%w: %q is neither a url, a caip-19 asset id nor a rarible id", businesserrors.ErrInvalidRequest, input)
+	}
+	// report the error of the id kind the number of CAIP segments points at
+	switch strings.Count(input, "/") {
+	case 1:
+		return Identifiers{}, collectionErr
+	case 2:
+		return Identifiers{}, itemErr
+	default:
+		return Identifiers{}, ownershipErr
+	}
+}
+
+// fromAsset builds normalised identifiers, deriving the collection from the item when it has one
+func fromAsset(source string, collection *model.CollectionID, item *model.ItemID) Identifiers {
+	ids := Identifiers{Source: source}
+	if item != nil {
+		normalized := item.Normalized()
+		ids.Item = &normalized
+		ids.Blockchain = normalized.Blockchain
+		if c, ok := normalized.Collection(); ok {
+			collection = &c
+		}
+	}
+	if collection != nil {
+		normalized := collection.Normalized()
+		ids.Collection = &normalized
+		ids.Blockchain = normalized.Blockchain
+	}
+	return ids
+}
+
+func lookupBlockchain(name string) (model.Blockchain, error) {
+	if blockchain, ok := chainAliases[strings.ToLower(name)]; ok {
+		return blockchain, nil
+	}
+	return model.ParseBlockchain(name)
+}
@@ -0,0 +1,170 @@
+package resolver
+
+import (
+	"regexp"
+	"testing"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testContract = "0x06012c8cf97bead5deae237070f9587f8e7a266d"
+	testOwner    = "0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10"
+	testMint     = "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"
+)
+
+type resolved struct {
+	source     string
+	collection string
+	item       string
+	ownership  string
+}
+
+func flatten(ids Identifiers) resolved {
+	r := resolved{source: ids.Source}
+	if ids.Collection != nil {
+		r.collection = ids.Collection.String()
+	}
+	if ids.Item != nil {
+		r.item = ids.Item.String()
+	}
+	if ids.Ownership != nil {
+		r.ownership = ids.Ownership.String()
+	}
+	return r
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	registry := DefaultRegistry()
+
+	t.Run("ShouldResolveKnownURLs", func(t *testing.T) {
+		ethItem := resolved{collection: "ETHEREUM:" + testContract, item: "ETHEREUM:" + testContract + ":123"}
+		polygonItem := resolved{collection: "POLYGON:" + testContract, item: "POLYGON:" + testContract + ":42"}
+		ethCollection := resolved{collection: "ETHEREUM:" + testContract}
+		solanaItem := resolved{item: "SOLANA:" + testMint}
+
+		cases := map[string]resolved{
+			"https://rarible.com/token/0x06012C8cf97BEaD5deAe237070F9587f8E7A266d:123": ethItem,
+			"https://rarible.com/token/polygon/" + testContract + ":42":                polygonItem,
+			"https://rarible.com/collection/" + testContract + "/items":                ethCollection,
+			"https://rarible.com/ethereum/items/" + testContract + ":123":              ethItem,
+			"https://rarible.com/token/solana/" + testMint:                             solanaItem,
+			"https://opensea.io/assets/ethereum/" + testContract + "/123":              ethItem,
+			"https://www.opensea.io/item/matic/" + testContract + "/42":                polygonItem,
+			"https://opensea.io/assets/" + testContract + "/123":                       ethItem,
+			"https://opensea.io/assets/solana/" + testMint:                             solanaItem,
+			"https://etherscan.io/nft/" + testContract + "/123":                        ethItem,
+			"https://etherscan.io/token/" + testContract + "?a=123":                    ethItem,
+			"https://etherscan.io/token/" + testContract:                               ethCollection,
+			"https://polygonscan.com/nft/" + testContract + "/42":                      polygonItem,
+			"https://magiceden.io/item-details/" + testMint:                            solanaItem,
+			"https://magiceden.io/item-details/ethereum/" + testContract + "/123":      ethItem,
+			"https://magiceden.io/collections/ethereum/" + testContract:                ethCollection,
+		}
+		for input, want := range cases {
+			ids, err := registry.Resolve(input)
+			require.NoError(t, err, input)
+
+			got := flatten(ids)
+			got.source = ""
+			require.Equal(t, want, got, input)
+		}
+	})
+
+	t.Run("ShouldReportSource", func(t *testing.T) {
+		ids, err := registry.Resolve("https://magiceden.io/item-details/" + testMint)
+		require.NoError(t, err)
+		require.Equal(t, "magiceden", ids.Source)
+		require.Equal(t, model.BlockchainSolana, ids.Blockchain)
+	})
+
+	t.Run("ShouldKeepHolderQueryOnCollection", func(t *testing.T) {
+		ids, err := registry.Resolve("https://etherscan.io/token/" + testContract + "?a=0x4B7A2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+		require.NoError(t, err)
+		require.Equal(t, resolved{source: "etherscan", collection: "ETHEREUM:" + testContract}, flatten(ids))
+		require.NotNil(t, ids.Owner)
+		require.Equal(t, "ETHEREUM:"+testOwner, ids.Owner.String())
+
+		ids, err = registry.Resolve("https://etherscan.io/token/" + testContract + "?a=123")
+		require.NoError(t, err)
+		require.Nil(t, ids.Owner)
+		require.Equal(t, "ETHEREUM:"+testContract+":123", ids.Item.String())
+	})
+
+	t.Run("ShouldNarrowItemToOwnership", func(t *testing.T) {
+		ids, err := registry.Resolve("https://etherscan.io/nft/" + testContract + "/123")
+		require.NoError(t, err)
+
+		ids, err = ids.WithOwner("0x4B7A2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+		require.NoError(t, err)
+		require.Equal(t, "ETHEREUM:"+testContract+":123:"+testOwner, ids.Ownership.String())
+	})
+
+	t.Run("ShouldResolveCAIP19AndRaribleIDs", func(t *testing.T) {
+		cases := map[string]resolved{
+			"eip155:1/erc721:0x06012C8cf97BEaD5deAe237070F9587f8E7A266d/123": {source: SourceCAIP19, collection: "ETHEREUM:" + testContract, item: "ETHEREUM:" + testContract + ":123"},
+			"eip155:137/erc721:" + testContract:                              {source: SourceCAIP19, collection: "POLYGON:" + testContract},
+			"ETHEREUM:" + testContract + ":123:" + testOwner: {
+				source:     SourceRaribleID,
+				collection: "ETHEREUM:" + testContract,
+				item:       "ETHEREUM:" + testContract + ":123",
+				ownership:  "ETHEREUM:" + testContract + ":123:" + testOwner,
+			},
+			"ETHEREUM:" + testContract: {source: SourceRaribleID, collection: "ETHEREUM:" + testContract},
+			"eip155:1/erc721:" + testContract + "/123/eip155:1:" + testOwner: {
+				source:     SourceCAIP19,
+				collection: "ETHEREUM:" + testContract,
+				item:       "ETHEREUM:" + testContract + ":123",
+				ownership:  "ETHEREUM:" + testContract + ":123:" + testOwner,
+			},
+		}
+		for input, want := range cases {
+			ids, err := registry.Resolve(input)
+			require.NoError(t, err, input)
+			require.Equal(t, want, flatten(ids), input)
+		}
+	})
+
+	t.Run("ShouldRejectUnknownInputs", func(t *testing.T) {
+		cases := map[string]string{
+			"":                                       "input is required",
+			"https://example.com/token/0x1:1":        "no resolver pattern matches",
+			"https://opensea.io/collection/boredape": "no resolver pattern matches",
+			"https://opensea.io/assets/bitcoin/" + testContract + "/1": "unknown blockchain",
+			"https://magiceden.io/item-details/ethereum/0x123/1":       "contract",
+			"not-an-id": "neither a url",
+		}
+		for input, reason := range cases {
+			_, err := registry.Resolve(input)
+			require.ErrorIs(t, err, businesserrors.ErrInvalidRequest, input)
+			require.ErrorContains(t, err, reason, input)
+		}
+	})
+
+	t.Run("ShouldRejectOwnerWithoutItem", func(t *testing.T) {
+		ids, err := registry.Resolve("https://etherscan.io/token/" + testContract)
+		require.NoError(t, err)
+
+		_, err = ids.WithOwner(testOwner)
+		require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+	})
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := NewRegistry()
+	_, err := registry.Resolve("https://explorer.example/asset/" + testContract + "/7")
+	require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+
+	registry.Register(Pattern{
+		Source: "example",
+		Hosts:  map[string]model.Blockchain{"explorer.example": model.BlockchainBase},
+		Path:   regexp.MustCompile(`^/asset/(?P<contract>0x[0-9a-fA-F]{40})/(?P<token>[0-9]+)$`),
+	})
+
+	ids, err := registry.Resolve("https://explorer.example/asset/" + testContract + "/7")
+	require.NoError(t, err)
+	require.Equal(t, "example", ids.Source)
+	require.Equal(t, "BASE:"+testContract+":7", ids.Item.String())
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Megidy/rarible/internal/client"
+	"github.com/Megidy/rarible/internal/domain/constants"
+	"github.com/Megidy/rarible/internal/handler/dto"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminHandler struct {
+	faultInjector *client.FaultInjector
+	keyPools      map[string]*client.KeyPool
+	shadow        *client.ShadowClient
+	drift         map[string]*client.DriftDetector
+}
+
+// AdminOption configures the upstream components whose state the admin routes expose
+type AdminOption func(*AdminHandler)
+
+// WithKeyPools exposes the usage of each environment's API key pool, keyed by environment
+func WithKeyPools(keyPools map[string]*client.KeyPool) AdminOption {
+	return func(h *AdminHandler) {
+		h.keyPools = keyPools
+	}
+}
+
+// WithShadowClient exposes the response diffs of the shadow upstream
+func WithShadowClient(shadow *client.ShadowClient) AdminOption {
+	return func(h *AdminHandler) {
+		h.shadow = shadow
+	}
+}
+
+// WithDriftDetectors exposes the schema drift seen by each environment's client, keyed by environment
+func WithDriftDetectors(drift map[string]*client.DriftDetector) AdminOption {
+	return func(h *AdminHandler) {
+		h.drift = drift
+	}
+}
+
+// NewAdminHandler creates the admin handler. The fault routes are only served
+// when faultInjector is set, which must never be the case in production.
+func NewAdminHandler(faultInjector *client.FaultInjector, opts ...AdminOption) *AdminHandler {
+	h := &AdminHandler{
+		faultInjector: faultInjector,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// GetFaults godoc
+// @Summary Get injected upstream faults
+// @Description Returns the faults currently injected into upstream Rarible calls. Only served outside production, under /admin rather than /v1.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token set in ADMIN_TOKEN"
+// @Success 200 {object} dto.GeneralResponse{data=client.FaultConfig} "Successfully retrieved fault config"
+// @Failure 401 {object} dto.GeneralResponse "Missing or invalid admin token"
+// @Router /admin/faults [get]
+func (h *AdminHandler) GetFaults(ctx echo.Context) error {
+	resp := dto.NewGeneralResponse(h.faultInjector.Config(), constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// SetFaults godoc
+// @Summary Set injected upstream faults
+// @Description Replaces the faults injected into upstream Rarible calls. Only served outside production, under /admin rather than /v1.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token set in ADMIN_TOKEN"
+// @Param faults body client.FaultConfig true "Fault injection config"
+// @Success 200 {object} dto.GeneralResponse{data=client.FaultConfig} "Successfully updated fault config"
+// @Failure 400 {object} dto.GeneralResponse "Invalid fault config"
+// @Failure 401 {object} dto.GeneralResponse "Missing or invalid admin token"
+// @Router /admin/faults [put]
+func (h *AdminHandler) SetFaults(ctx echo.Context) error {
+	var req client.FaultConfig
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid request body", err.Error(), http.StatusBadRequest))
+	}
+
+	err = h.faultInjector.SetConfig(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid fault config", err.Error(), http.StatusBadRequest))
+	}
+
+	log.Warn().Interface("faults", req).Msg("upstream fault injection reconfigured")
+
+	resp := dto.NewGeneralResponse(h.faultInjector.Config(), constants.StatusUpdated, "successfully updated data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetKeyStats godoc
+// @Summary Get upstream API key usage
+// @Description Returns request and failure counts and bench state of every pooled Rarible API key per environment. Keys are identified by fingerprint only. Served under /admin rather than /v1.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token set in ADMIN_TOKEN"
+// @Success 200 {object} dto.GeneralResponse{data=map[string][]client.KeyStats} "Successfully retrieved key usage"
+// @Failure 401 {object} dto.GeneralResponse "Missing or invalid admin token"
+// @Router /admin/upstream/keys [get]
+func (h *AdminHandler) GetKeyStats(ctx echo.Context) error {
+	stats := make(map[string][]client.KeyStats, len(h.keyPools))
+	for environment, pool := range h.keyPools {
+		stats[environment] = pool.Stats()
+	}
+
+	resp := dto.NewGeneralResponse(stats, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetShadowSummary godoc
+// @Summary Get shadow upstream diffs
+// @Description Returns how many mirrored mainnet reads matched the shadow upstream, with the most recent mismatch and failure reports. Served under /admin rather than /v1.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token set in ADMIN_TOKEN"
+// @Success 200 {object} dto.GeneralResponse{data=client.ShadowSummary} "Successfully retrieved shadow diffs"
+// @Failure 401 {object} dto.GeneralResponse "Missing or invalid admin token"
+// @Failure 404 {object} dto.GeneralResponse "Shadow traffic is not enabled"
+// @Router /admin/upstream/shadow [get]
+func (h *AdminHandler) GetShadowSummary(ctx echo.Context) error {
+	if h.shadow == nil {
+		return ctx.JSON(http.StatusNotFound, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"shadow traffic is not enabled", "SHADOW_BASE_URL is not set", http.StatusNotFound))
+	}
+
+	resp := dto.NewGeneralResponse(h.shadow.Summary(), constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetDriftSummary godoc
+// @Summary Get upstream schema drift
+// @Description Returns the drift counters and distinct drifted fields per environment and upstream endpoint. Served under /admin rather than /v1.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token set in ADMIN_TOKEN"
+// @Success 200 {object} dto.GeneralResponse{data=map[string]client.DriftSummary} "Successfully retrieved schema drift"
+// @Failure 401 {object} dto.GeneralResponse "Missing or invalid admin token"
+// @Failure 404 {object} dto.GeneralResponse "Strict decoding is not enabled"
+// @Router /admin/upstream/drift [get]
+func (h *AdminHandler) GetDriftSummary(ctx echo.Context) error {
+	if len(h.drift) == 0 {
+		return ctx.JSON(http.StatusNotFound, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"strict decoding is not enabled", "STRICT_DECODING is not set", http.StatusNotFound))
+	}
+
+	summaries := make(map[string]client.DriftSummary, len(h.drift))
+	for environment, detector := range h.drift {
+		summaries[environment] = detector.Summary()
+	}
+
+	resp := dto.NewGeneralResponse(summaries, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Megidy/rarible/internal/client"
+	"github.com/Megidy/rarible/internal/domain/constants"
+	"github.com/Megidy/rarible/internal/handler/dto"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_Faults(t *testing.T) {
+	injector := client.NewFaultInjector()
+	h := NewAdminHandler(injector)
+
+	t.Run("SetFaults", func(t *testing.T) {
+		e := echo.New()
+		body := `{"enabled":true,"rules":[{"endpoint":"ownerships","errorProbability":0.5,"errorStatusCode":503}]}`
+		req := httptest.NewRequest(http.MethodPut, "/admin/faults", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.SetFaults(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.True(t, injector.Config().Enabled)
+		require.Equal(t, http.StatusServiceUnavailable, injector.Config().Rules[0].ErrorStatusCode)
+	})
+
+	t.Run("GetFaults", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/admin/faults", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.GetFaults(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp dto.GeneralResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		require.Equal(t, constants.StatusRetrieved, resp.Status.Status)
+	})
+
+	t.Run("BadRequestInvalidConfig", func(t *testing.T) {
+		e := echo.New()
+		body := `{"enabled":true,"rules":[{"dropProbability":3}]}`
+		req := httptest.NewRequest(http.MethodPut, "/admin/faults", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.SetFaults(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestAdminHandler_GetKeyStats(t *testing.T) {
+	pool := client.NewKeyPool([]string{"super-secret-key", "other-key"}, client.KeySelectionRoundRobin, time.Minute)
+	h := NewAdminHandler(nil, WithKeyPools(map[string]*client.KeyPool{"mainnet": pool}))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/admin/upstream/keys", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := h.GetKeyStats(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), "super-secret-key")
+
+	var resp struct {
+		Data map[string][]client.KeyStats `json:"data"`
+	}
+	err = json.Unmarshal(rec.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	require.Len(t, resp.Data["mainnet"], 2)
+}
+
+func TestAdminHandler_GetShadowSummary(t *testing.T) {
+	t.Run("ReturnsStatsAndReports", func(t *testing.T) {
+		newServer := func(owner string) *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"id":"id-123","owner":"` + owner + `"}`))
+			}))
+		}
+		primaryServer := newServer("0xabc")
+		defer primaryServer.Close()
+		shadowServer := newServer("0xdef")
+		defer shadowServer.Close()
+
+		shadow := client.NewShadowClient(client.NewRaribleClient("key", primaryServer.URL), client.NewRaribleClient("key", shadowServer.URL), 1)
+		_, err := shadow.GetOwnershipByID(context.Background(), "id-123")
+		require.NoError(t, err)
+		shadow.Wait()
+
+		h := NewAdminHandler(nil, WithShadowClient(shadow))
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/admin/upstream/shadow", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = h.GetShadowSummary(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp struct {
+			Data client.ShadowSummary `json:"data"`
+		}
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		require.Equal(t, client.ShadowStats{Compared: 1, Mismatched: 1}, resp.Data.Stats)
+		require.Len(t, resp.Data.Reports, 1)
+		require.Equal(t, "Owner", resp.Data.Reports[0].Diffs[0].Field)
+	})
+
+	t.Run("NotFoundWhenDisabled", func(t *testing.T) {
+		h := NewAdminHandler(nil)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/admin/upstream/shadow", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.GetShadowSummary(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestAdminHandler_GetDriftSummary(t *testing.T) {
+	t.Run("ReturnsCountsAndIssues", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"id":"id-123","version":2}`))
+		}))
+		defer server.Close()
+
+		detector := client.NewDriftDetector(false)
+		_, err := client.NewRaribleClient("key", server.URL, client.WithDriftDetector(detector)).GetOwnershipByID(context.Background(), "id-123")
+		require.NoError(t, err)
+
+		h := NewAdminHandler(nil, WithDriftDetectors(map[string]*client.DriftDetector{"mainnet": detector}))
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/admin/upstream/drift", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = h.GetDriftSummary(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp struct {
+			Data map[string]client.DriftSummary `json:"data"`
+		}
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		summary := resp.Data["mainnet"]
+		require.Equal(t, uint64(1), summary.Counts["ownerships"][client.DriftUnknownField])
+		require.Contains(t, summary.Issues["ownerships"], client.DriftIssue{Kind: client.DriftUnknownField, Field: "version"})
+	})
+
+	t.Run("NotFoundWhenDisabled", func(t *testing.T) {
+		h := NewAdminHandler(nil)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/admin/upstream/drift", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.GetDriftSummary(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+)
+
+var traitStatsCSVHeader = []string{
+	"key", "value", "count", "rarity", "tier", "long_tail",
+	"key_values", "key_items", "key_missing", "key_entropy", "key_normalized_entropy",
+}
+
+// traitStatsCSV writes one row per trait key and value, repeating the key's stats on each of its rows
+func traitStatsCSV(stats *model.TraitStatsResponseDTO) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(traitStatsCSVHeader); err != nil {
+		return nil, err
+	}
+
+	formatFloat := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	for _, key := range stats.TraitKeys {
+		keyColumns := []string{
+			strconv.Itoa(key.Values),
+			strconv.Itoa(key.Items),
+			strconv.Itoa(key.Missing),
+			formatFloat(key.Entropy),
+			formatFloat(key.NormalizedEntropy),
+		}
+		longTail := make(map[string]bool, len(key.LongTail))
+		for _, value := range key.LongTail {
+			longTail[value] = true
+		}
+		for _, value := range key.Distribution {
+			row := []string{
+				key.Key,
+				value.Value,
+				strconv.Itoa(value.Count),
+				value.Rarity.String(),
+				value.Tier,
+				strconv.FormatBool(longTail[value.Value]),
+			}
+			if err := w.Write(append(row, keyColumns...)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
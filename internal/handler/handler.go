@@ -4,10 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Megidy/rarible/internal/domain/constants"
 	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/Megidy/rarible/internal/domain/filter"
 	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
 	"github.com/Megidy/rarible/internal/handler/dto"
 	"github.com/Megidy/rarible/internal/service"
 	"github.com/labstack/echo/v4"
@@ -15,7 +18,14 @@ import (
 )
 
 const (
-	idParam = "id"
+	caipQueryParam = "caip"
+
+	idParam         = "id"
+	blockchainParam = "blockchain"
+	contractParam   = "contract"
+	tokenIDParam    = "tokenId"
+	mintParam       = "mint"
+	ownerParam      = "owner"
 )
 
 type NFTHandler struct {
@@ -34,32 +44,112 @@ func NewNFTHandler(nftService service.NFTService) *NFTHandler {
 // @Tags NFT
 // @Accept json
 // @Produce json
-// @Param id path string true "NFT ID" Example(0x06012c8cf97bead5deae237070f9587f8e7a266d:123456)
+// @Param id path string true "Ownership ID as BLOCKCHAIN:contract:tokenId:owner, or a URL-encoded CAIP-19 asset ID followed by /owner" Example(ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:123456:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10)
+// @Param caip query bool false "Include CAIP-2, CAIP-19 and CAIP-10 IDs alongside Rarible IDs"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
 // @Success 200 {object} dto.GeneralResponse{data=model.OwnershipDTO} "Successfully retrieved ownership data"
 // @Failure 400 {object} dto.GeneralResponse "Invalid request parameters"
 // @Failure 404 {object} dto.GeneralResponse "NFT ownership not found"
 // @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
 // @Router /ownerships/{id} [get]
 func (h *NFTHandler) GetOwnership(ctx echo.Context) error {
-	id := getFromParam(ctx, idParam)
-
-	ownership, err := h.nftService.GetOwnershipByID(ctx.Request().Context(), id)
-	if err != nil {
-		msg := "failed to get ownership by id"
-		log.Error().Err(err).Msg(msg)
-
-		resp := dto.NewGeneralResponse(nil, constants.StatusFailed, msg, err.Error(), http.StatusInternalServerError)
-		switch {
-		case errors.Is(err, businesserrors.ErrInvalidRequest):
-			resp.Status.StatusCode = http.StatusBadRequest
-			return ctx.JSON(http.StatusBadRequest, resp)
-		case errors.Is(err, businesserrors.ErrNotFound):
-			resp.Status.StatusCode = http.StatusNotFound
-			return ctx.JSON(http.StatusNotFound, resp)
-		default:
-			resp.Status.StatusCode = http.StatusInternalServerError
-			return ctx.JSON(http.StatusInternalServerError, resp)
-		}
+	id, err := model.ParseOwnershipID(getFromParam(ctx, idParam))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid ownership id", err.Error(), http.StatusBadRequest))
+	}
+
+	// the id is forwarded with the caller's casing, as it always has been on this route
+	return h.getOwnership(ctx, id)
+}
+
+// GetOwnershipBySegments godoc
+// @Summary Get NFT ownership information by its segments
+// @Description Builds the ownership ID from separate blockchain, contract, token and owner segments, validates it and retrieves the ownership details.
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param blockchain path string true "Blockchain name or CAIP-2 chain ID" Example(ETHEREUM)
+// @Param contract path string true "Contract address" Example(0x06012c8cf97bead5deae237070f9587f8e7a266d)
+// @Param tokenId path string true "Token ID" Example(123456)
+// @Param owner path string true "Owner address" Example(0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10)
+// @Param caip query bool false "Include CAIP-2, CAIP-19 and CAIP-10 IDs alongside Rarible IDs"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.OwnershipDTO} "Successfully retrieved ownership data"
+// @Failure 400 {object} dto.GeneralResponse "Invalid request parameters"
+// @Failure 404 {object} dto.GeneralResponse "NFT ownership not found"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /ownerships/{blockchain}/{contract}/{tokenId}/{owner} [get]
+func (h *NFTHandler) GetOwnershipBySegments(ctx echo.Context) error {
+	id, err := model.NewOwnershipID(
+		getFromParam(ctx, blockchainParam),
+		getFromParam(ctx, contractParam),
+		getFromParam(ctx, tokenIDParam),
+		getFromParam(ctx, ownerParam),
+	)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid ownership id", err.Error(), http.StatusBadRequest))
+	}
+
+	return h.getOwnership(ctx, id.Normalized())
+}
+
+// GetOwnershipByMintSegments godoc
+// @Summary Get Solana NFT ownership information by its segments
+// @Description Builds the ownership ID of an item without token ID, such as a Solana mint, from separate blockchain, mint and owner segments, validates it and retrieves the ownership details
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param blockchain path string true "Blockchain name or CAIP-2 chain ID" Example(SOLANA)
+// @Param mint path string true "Mint address" Example(7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU)
+// @Param owner path string true "Owner address" Example(9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM)
+// @Param caip query bool false "Include CAIP-2, CAIP-19 and CAIP-10 IDs alongside Rarible IDs"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.OwnershipDTO} "Successfully retrieved ownership data"
+// @Failure 400 {object} dto.GeneralResponse "Invalid request parameters"
+// @Failure 404 {object} dto.GeneralResponse "NFT ownership not found"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /ownerships/{blockchain}/{mint}/{owner} [get]
+func (h *NFTHandler) GetOwnershipByMintSegments(ctx echo.Context) error {
+	id, err := model.NewOwnershipID(
+		getFromParam(ctx, blockchainParam),
+		getFromParam(ctx, mintParam),
+		"",
+		getFromParam(ctx, ownerParam),
+	)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid ownership id", err.Error(), http.StatusBadRequest))
+	}
+
+	return h.getOwnership(ctx, id.Normalized())
+}
+
+func (h *NFTHandler) getOwnership(ctx echo.Context, id model.OwnershipID) error {
+	includeCAIP, err := getBoolFromQuery(ctx, caipQueryParam)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	ownership, err := h.nftService.GetOwnershipByID(ctx.Request().Context(), id.String())
+	if err != nil {
+		return failed(ctx, "failed to get ownership by id", err)
+	}
+	if includeCAIP {
+		ownership.CAIP = id.CAIPIDs()
 	}
 
 	resp := dto.NewGeneralResponse(ownership, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
@@ -68,15 +158,22 @@ func (h *NFTHandler) GetOwnership(ctx echo.Context) error {
 
 // GetTraitRarities godoc
 // @Summary Get trait rarities for NFTs
-// @Description returns rarity scores for specified traits in an NFT collection
+// @Description returns rarity scores for specified traits in an NFT collection, ranked within the request and labelled with a rarity tier
 // @Tags NFT
 // @Accept json
 // @Produce json
 // @Param request body model.TraitRarityRequestDTO true "Trait rarity request parameters"
+// @Param sort query string false "Sort traits by request order, rarity or key" Enums(request, rarity, key)
+// @Param order query string false "Sort order, rarity ascending lists the rarest first" Enums(asc, desc)
+// @Param minRarity query number false "Only return traits with at least this rarity, in percent"
+// @Param maxRarity query number false "Only return traits with at most this rarity, in percent"
+// @Param tier query []string false "Only return traits in these tiers" collectionFormat(multi) Enums(legendary, epic, rare, uncommon, common)
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
 // @Success 200 {object} dto.GeneralResponse{data=model.TraitRarityResponseDTO} "Successfully calculated trait rarities"
 // @Failure 400 {object} dto.GeneralResponse "Invalid request body or parameters"
 // @Failure 404 {object} dto.GeneralResponse "Collection or traits not found"
 // @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
 // @Router /trait-rarities [post]
 func (h *NFTHandler) GetTraitRarities(ctx echo.Context) error {
 	var req model.TraitRarityRequestDTO
@@ -99,21 +196,7 @@ func (h *NFTHandler) GetTraitRarities(ctx echo.Context) error {
 
 	traitRarityResponse, err := h.nftService.GetTraitRarity(ctx.Request().Context(), req)
 	if err != nil {
-		msg := "failed to get trarity rarity"
-		log.Error().Err(err).Msg(msg)
-
-		resp := dto.NewGeneralResponse(nil, constants.StatusFailed, msg, err.Error(), http.StatusInternalServerError)
-		switch {
-		case errors.Is(err, businesserrors.ErrInvalidRequest):
-			resp.Status.StatusCode = http.StatusBadRequest
-			return ctx.JSON(http.StatusBadRequest, resp)
-		case errors.Is(err, businesserrors.ErrNotFound):
-			resp.Status.StatusCode = http.StatusNotFound
-			return ctx.JSON(http.StatusNotFound, resp)
-		default:
-			resp.Status.StatusCode = http.StatusInternalServerError
-			return ctx.JSON(http.StatusInternalServerError, resp)
-		}
+		return failed(ctx, "failed to get trarity rarity", err)
 	}
 
 	resp := dto.NewGeneralResponse(traitRarityResponse, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
@@ -121,15 +204,752 @@ func (h *NFTHandler) GetTraitRarities(ctx echo.Context) error {
 
 }
 
+// Resolve godoc
+// @Summary Resolve a marketplace URL into Rarible identifiers
+// @Description Resolves a Rarible, OpenSea, Etherscan-family or Magic Eden URL, a CAIP-19 asset ID or a Rarible ID into normalised collection, item and ownership IDs, optionally fetching the ownership or item in the same call. A holder named without an item, as in an Etherscan token holder view, is returned as owner
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param input query string true "URL, CAIP-19 asset ID or Rarible ID" Example(https://opensea.io/assets/ethereum/0x06012c8cf97bead5deae237070f9587f8e7a266d/123456)
+// @Param owner query string false "Owner address, narrows an item down to its ownership"
+// @Param include query bool false "Fetch the resolved ownership, or the item when no owner is known"
+// @Param caip query bool false "Include CAIP-2, CAIP-19 and CAIP-10 IDs alongside Rarible IDs"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.ResolveResponseDTO} "Successfully resolved identifiers"
+// @Failure 400 {object} dto.GeneralResponse "Input not recognised or invalid"
+// @Failure 404 {object} dto.GeneralResponse "Resolved item or ownership not found"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /resolve [get]
+func (h *NFTHandler) Resolve(ctx echo.Context) error {
+	var req model.ResolveRequestDTO
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	resolved, err := h.nftService.Resolve(ctx.Request().Context(), req)
+	if err != nil {
+		return failed(ctx, "failed to resolve input", err)
+	}
+
+	resp := dto.NewGeneralResponse(resolved, constants.StatusRetrieved, "successfully resolved input", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetRarityRankings godoc
+// @Summary Rank the items of a collection by rarity
+// @Description Fetches every item of a collection and scores it from its traits with information content (OpenRarity), rarity score sum and statistical rarity, returning a page of items rarest first. Traits an item lacks and its trait count are scored as extra traits unless disabled.
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param id path string true "Collection ID, or its URL-encoded CAIP-19 form" Example(ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6)
+// @Param method query string false "Scoring method items are ranked by, defaults to information_content" Enums(information_content, rarity_score, statistical)
+// @Param offset query int false "Number of ranked items to skip"
+// @Param limit query int false "Number of ranked items to return, defaults to 100, at most 1000"
+// @Param missingTraits query bool false "Score traits an item lacks as the value none, defaults to true"
+// @Param traitCount query bool false "Score the number of traits an item has as a meta-trait, defaults to true"
+// @Param filter query string false "Trait filter the listed items must match, e.g. Hat = Halo AND NOT Eyes IN (Red, Laser); ranks stay collection wide. Bucketed traits only compare with =, != or IN"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.RarityRankingsResponseDTO} "Successfully ranked the collection"
+// @Failure 400 {object} dto.GeneralResponse "Invalid request parameters or collection too large"
+// @Failure 404 {object} dto.GeneralResponse "Collection not found or empty"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /collections/{id}/rarity-rankings [get]
+func (h *NFTHandler) GetRarityRankings(ctx echo.Context) error {
+	var req model.RarityRankingsRequestDTO
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+	req.CollectionID = getFromParam(ctx, idParam)
+
+	err = h.validateRarityRankingsRequest(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	rankings, err := h.nftService.GetRarityRankings(ctx.Request().Context(), req)
+	if err != nil {
+		return failed(ctx, "failed to get rarity rankings", err)
+	}
+
+	resp := dto.NewGeneralResponse(rankings, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetItemRarity godoc
+// @Summary Explain an item's rarity
+// @Description Scores an item within its collection and breaks the score down by trait, showing each trait's frequency, its contribution to the score and its share of it, most impactful first, alongside the item's collection rank and percentile and Rarible's own rarity of each trait
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID, or its URL-encoded CAIP-19 form" Example(ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6:1)
+// @Param method query string false "Scoring method the breakdown is given for, defaults to information_content" Enums(information_content, rarity_score, statistical)
+// @Param missingTraits query bool false "Score traits an item lacks as the value none, defaults to true"
+// @Param traitCount query bool false "Score the number of traits an item has as a meta-trait, defaults to true"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.ItemRarityBreakdownDTO} "Successfully explained the item's rarity"
+// @Failure 400 {object} dto.GeneralResponse "Invalid request parameters or collection too large"
+// @Failure 404 {object} dto.GeneralResponse "Item not found"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /items/{id}/rarity [get]
+func (h *NFTHandler) GetItemRarity(ctx echo.Context) error {
+	var req model.ItemRarityRequestDTO
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	id, err := model.ParseItemID(getFromParam(ctx, idParam))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid item id", err.Error(), http.StatusBadRequest))
+	}
+	req.ItemID = id.Normalized().String()
+
+	err = validateRarityMethod(req.Method)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	breakdown, err := h.nftService.GetItemRarity(ctx.Request().Context(), req)
+	if err != nil {
+		return failed(ctx, "failed to get item rarity", err)
+	}
+
+	resp := dto.NewGeneralResponse(breakdown, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetSimilarItems godoc
+// @Summary Find items similar to an item
+// @Description Ranks the other items of an item's collection by how alike their traits are, by Jaccard similarity or by overlap weighted by trait rarity, and returns the closest matches with the traits they share and the ones that differ
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID, or its URL-encoded CAIP-19 form" Example(ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6:1)
+// @Param method query string false "Similarity measure, defaults to weighted" Enums(jaccard, weighted)
+// @Param exclude query []string false "Trait keys left out of the comparison, repeated or comma separated" collectionFormat(multi)
+// @Param limit query int false "Number of similar items to return, defaults to 10, at most 100"
+// @Param filter query string false "Trait filter the similar items must match, e.g. Hat = Halo AND NOT Eyes IN (Red, Laser). Bucketed traits only compare with =, != or IN"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.SimilarItemsResponseDTO} "Successfully found similar items"
+// @Failure 400 {object} dto.GeneralResponse "Invalid request parameters or collection too large"
+// @Failure 404 {object} dto.GeneralResponse "Item not found"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /items/{id}/similar [get]
+func (h *NFTHandler) GetSimilarItems(ctx echo.Context) error {
+	var req model.SimilarItemsRequestDTO
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	id, err := model.ParseItemID(getFromParam(ctx, idParam))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid item id", err.Error(), http.StatusBadRequest))
+	}
+	req.ItemID = id.Normalized().String()
+
+	err = validateSimilarItemsRequest(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	similar, err := h.nftService.GetSimilarItems(ctx.Request().Context(), req)
+	if err != nil {
+		return failed(ctx, "failed to get similar items", err)
+	}
+
+	resp := dto.NewGeneralResponse(similar, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetItemTraitRarities godoc
+// @Summary Get the trait rarities of an item
+// @Description Fetches an item's attributes, derives its collection and returns the attributes annotated with their rarity, rank and tier. Items with unrevealed metadata or without attributes are returned with a status explaining why their traits are empty.
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID, or its URL-encoded CAIP-19 form" Example(ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6:1)
+// @Param sort query string false "Sort traits by attribute order, rarity or key" Enums(request, rarity, key)
+// @Param order query string false "Sort order, rarity ascending lists the rarest first" Enums(asc, desc)
+// @Param minRarity query number false "Only return traits with at least this rarity, in percent"
+// @Param maxRarity query number false "Only return traits with at most this rarity, in percent"
+// @Param tier query []string false "Only return traits in these tiers" collectionFormat(multi) Enums(legendary, epic, rare, uncommon, common)
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.ItemTraitRarityResponseDTO} "Successfully annotated the item's traits"
+// @Failure 400 {object} dto.GeneralResponse "Invalid request parameters"
+// @Failure 404 {object} dto.GeneralResponse "Item not found"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /items/{id}/trait-rarities [get]
+func (h *NFTHandler) GetItemTraitRarities(ctx echo.Context) error {
+	var req model.ItemTraitRarityRequestDTO
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	id, err := model.ParseItemID(getFromParam(ctx, idParam))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid item id", err.Error(), http.StatusBadRequest))
+	}
+	req.ItemID = id.Normalized().String()
+
+	err = validateTraitRarityOptions(req.TraitRarityOptions)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	traits, err := h.nftService.GetItemTraitRarity(ctx.Request().Context(), req)
+	if err != nil {
+		return failed(ctx, "failed to get item trait rarities", err)
+	}
+
+	resp := dto.NewGeneralResponse(traits, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetTraitCombinations godoc
+// @Summary Find the rarest trait combinations of a collection
+// @Description Counts how many items of a collection have each pair, or triple, of traits and returns the rarest combinations with their counts. With a filter only the matching items are counted
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param id path string true "Collection ID, or its URL-encoded CAIP-19 form" Example(ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6)
+// @Param size query int false "Number of traits combined, defaults to 2" Enums(2, 3)
+// @Param limit query int false "Number of combinations to return, defaults to 20, at most 1000"
+// @Param filter query string false "Trait filter the counted items must match, e.g. Hat = Halo AND NOT Eyes IN (Red, Laser). Bucketed traits only compare with =, != or IN"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.TraitCombinationsResponseDTO} "Successfully counted trait combinations"
+// @Failure 400 {object} dto.GeneralResponse "Invalid request parameters or collection too large"
+// @Failure 404 {object} dto.GeneralResponse "Collection not found or empty"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /collections/{id}/trait-combinations [get]
+func (h *NFTHandler) GetTraitCombinations(ctx echo.Context) error {
+	var req model.TraitCombinationsRequestDTO
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+	req.CollectionID = getFromParam(ctx, idParam)
+
+	err = h.validateTraitCombinationsRequest(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	combinations, err := h.nftService.GetTraitCombinations(ctx.Request().Context(), req)
+	if err != nil {
+		return failed(ctx, "failed to get trait combinations", err)
+	}
+
+	resp := dto.NewGeneralResponse(combinations, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetTraitStats godoc
+// @Summary Get the trait distribution of a collection
+// @Description Describes how a collection's trait keys and values are distributed: values per key, entropy, items missing each key, long-tail values and a histogram of values by rarity tier. With a filter only the matching items are described. Stats are cached per collection and filter for a while. With format=csv one row per key and value is returned instead.
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Produce text/csv
+// @Param id path string true "Collection ID, or its URL-encoded CAIP-19 form" Example(ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6)
+// @Param format query string false "Response format, defaults to json" Enums(json, csv)
+// @Param longTail query number false "Rarity, in percent, at or below which a value is long tail, defaults to 1"
+// @Param filter query string false "Trait filter the described items must match, e.g. Hat = Halo AND NOT Eyes IN (Red, Laser). Bucketed traits only compare with =, != or IN"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.TraitStatsResponseDTO} "Successfully computed trait stats"
+// @Failure 400 {object} dto.GeneralResponse "Invalid request parameters or collection too large"
+// @Failure 404 {object} dto.GeneralResponse "Collection not found or empty"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /collections/{id}/trait-stats [get]
+func (h *NFTHandler) GetTraitStats(ctx echo.Context) error {
+	var req model.TraitStatsRequestDTO
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+	req.CollectionID = getFromParam(ctx, idParam)
+
+	err = validateTraitStatsRequest(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	stats, err := h.nftService.GetTraitStats(ctx.Request().Context(), req)
+	if err != nil {
+		return failed(ctx, "failed to get trait stats", err)
+	}
+
+	if req.Format == model.TraitStatsFormatCSV {
+		data, err := traitStatsCSV(stats)
+		if err != nil {
+			return failed(ctx, "failed to write trait stats", err)
+		}
+		ctx.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="trait-stats.csv"`)
+		return ctx.Blob(http.StatusOK, "text/csv; charset=utf-8", data)
+	}
+
+	resp := dto.NewGeneralResponse(stats, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// ExportOpenRarity godoc
+// @Summary Export a collection's rarity ranking in OpenRarity format
+// @Description Ranks a collection as OpenRarity does, tokens with more unique attributes first and then by information content over every trait, missing traits and the trait count included, and returns the ranking as an OpenRarity-compatible JSON file
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param id path string true "Collection ID, or its URL-encoded CAIP-19 form" Example(ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6)
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} model.OpenRarityRankingsDTO "OpenRarity ranking of the collection"
+// @Failure 400 {object} dto.GeneralResponse "Invalid collection id or collection too large"
+// @Failure 404 {object} dto.GeneralResponse "Collection not found or empty"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /collections/{id}/openrarity [get]
+func (h *NFTHandler) ExportOpenRarity(ctx echo.Context) error {
+	collectionID, err := model.ParseCollectionID(getFromParam(ctx, idParam))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid collection id", err.Error(), http.StatusBadRequest))
+	}
+
+	rankings, err := h.nftService.ExportOpenRarity(ctx.Request().Context(), collectionID.String())
+	if err != nil {
+		return failed(ctx, "failed to export openrarity ranking", err)
+	}
+
+	// the file is served bare so OpenRarity tooling can read it as is
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="openrarity.json"`)
+	return ctx.JSON(http.StatusOK, rankings)
+}
+
+// DiffOpenRarity godoc
+// @Summary Compare an OpenRarity ranking with ours
+// @Description Loads an external ranking of a collection in OpenRarity format and reports, token by token, where its ranks differ from the ranking computed here, along with the tokens only one side has
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param id path string true "Collection ID, or its URL-encoded CAIP-19 form" Example(ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6)
+// @Param request body model.OpenRarityRankingsDTO true "OpenRarity ranking of the collection"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.OpenRarityDiffDTO} "Successfully compared the rankings"
+// @Failure 400 {object} dto.GeneralResponse "Invalid ranking file or collection too large"
+// @Failure 404 {object} dto.GeneralResponse "Collection not found or empty"
+// @Failure 413 {object} dto.GeneralResponse "Ranking file too large"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /collections/{id}/openrarity/diff [post]
+func (h *NFTHandler) DiffOpenRarity(ctx echo.Context) error {
+	var req model.OpenRarityDiffRequestDTO
+
+	err := ctx.Bind(&req.Rankings)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid request body", err.Error(), http.StatusBadRequest))
+	}
+
+	collectionID, err := model.ParseCollectionID(getFromParam(ctx, idParam))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid collection id", err.Error(), http.StatusBadRequest))
+	}
+	req.CollectionID = collectionID.String()
+
+	err = validateOpenRarityRankings(req.Rankings)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid request body", err.Error(), http.StatusBadRequest))
+	}
+
+	diff, err := h.nftService.DiffOpenRarity(ctx.Request().Context(), req)
+	if err != nil {
+		return failed(ctx, "failed to compare openrarity ranking", err)
+	}
+
+	resp := dto.NewGeneralResponse(diff, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetMetadataRarity godoc
+// @Summary Rank tokens from uploaded metadata files
+// @Description Reads a zip, tar or tar.gz of ERC-721 metadata JSON files named by token id, takes each token's traits from its OpenSea attributes, normalises them and ranks the tokens with the rarity engine, along with trait statistics. Nothing is fetched upstream, so collections can be ranked before they are revealed. The archive is the request body or the archive field of a multipart form.
+// @Tags NFT
+// @Accept application/zip
+// @Accept application/gzip
+// @Accept application/x-tar
+// @Accept multipart/form-data
+// @Produce json
+// @Param archive formData file false "Metadata archive, when uploaded as a multipart form"
+// @Param collection query string false "Collection whose trait normalisation rules apply" Example(ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6)
+// @Param method query string false "Scoring method tokens are ranked by, defaults to information_content" Enums(information_content, rarity_score, statistical)
+// @Param missingTraits query bool false "Score traits a token lacks as the value none, defaults to true"
+// @Param traitCount query bool false "Score the number of traits a token has as a meta-trait, defaults to true"
+// @Param longTail query number false "Rarity, in percent, at or below which a value is long tail, defaults to 1"
+// @Success 200 {object} dto.GeneralResponse{data=model.MetadataRarityResponseDTO} "Successfully ranked the tokens"
+// @Failure 400 {object} dto.GeneralResponse "Invalid parameters, unreadable archive or too many tokens"
+// @Failure 413 {object} dto.GeneralResponse "Archive too large"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Router /metadata/rarity [post]
+func (h *NFTHandler) GetMetadataRarity(ctx echo.Context) error {
+	var req model.MetadataRarityRequestDTO
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	err = validateMetadataRarityRequest(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid query parameters", err.Error(), http.StatusBadRequest))
+	}
+
+	req.Archive, err = readArchive(ctx)
+	if errors.Is(err, echo.ErrStatusRequestEntityTooLarge) {
+		return ctx.JSON(http.StatusRequestEntityTooLarge, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"archive too large", err.Error(), http.StatusRequestEntityTooLarge))
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid request body", err.Error(), http.StatusBadRequest))
+	}
+
+	rankings, err := h.nftService.GetMetadataRarity(ctx.Request().Context(), req)
+	if err != nil {
+		return failed(ctx, "failed to rank metadata", err)
+	}
+
+	resp := dto.NewGeneralResponse(rankings, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetTraitCombinationRarity godoc
+// @Summary Get the rarity of a trait combination
+// @Description Counts how many items of a collection have every given trait and compares it with the rarity the combination would have if its traits occurred independently
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param request body model.TraitRarityRequestDTO true "Collection and the traits combined"
+// @Param X-Rarible-Environment header string false "Upstream environment, defaults to mainnet"
+// @Success 200 {object} dto.GeneralResponse{data=model.TraitCombinationRarityDTO} "Successfully calculated the combination's rarity"
+// @Failure 400 {object} dto.GeneralResponse "Invalid request body or collection too large"
+// @Failure 404 {object} dto.GeneralResponse "Collection not found or empty"
+// @Failure 500 {object} dto.GeneralResponse "Internal server error"
+// @Failure 503 {object} dto.GeneralResponse "Upstream capacity exhausted"
+// @Router /trait-combinations/rarity [post]
+func (h *NFTHandler) GetTraitCombinationRarity(ctx echo.Context) error {
+	var req model.TraitRarityRequestDTO
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid request body", err.Error(), http.StatusBadRequest))
+	}
+
+	err = h.validateTraitRarityRequest(&req)
+	if err == nil && len(req.Properties) == 0 {
+		err = fmt.Errorf("at least one property is required")
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+			nil,
+			constants.StatusFailed,
+			"invalid request body", err.Error(), http.StatusBadRequest))
+	}
+
+	combination, err := h.nftService.GetTraitCombinationRarity(ctx.Request().Context(), req)
+	if err != nil {
+		return failed(ctx, "failed to get trait combination rarity", err)
+	}
+
+	resp := dto.NewGeneralResponse(combination, constants.StatusRetrieved, "successfully retrieved data", constants.StrEmpty, http.StatusOK)
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// failed logs err and responds with the status matching its business error
+func failed(ctx echo.Context, msg string, err error) error {
+	log.Error().Err(err).Msg(msg)
+
+	statusCode := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, businesserrors.ErrInvalidRequest):
+		statusCode = http.StatusBadRequest
+	case errors.Is(err, businesserrors.ErrNotFound):
+		statusCode = http.StatusNotFound
+	case errors.Is(err, businesserrors.ErrUpstreamOverloaded):
+		statusCode = http.StatusServiceUnavailable
+	}
+	return ctx.JSON(statusCode, dto.NewGeneralResponse(nil, constants.StatusFailed, msg, err.Error(), statusCode))
+}
+
+// validateTraitRarityRequest validates the request and rewrites a CAIP-19 collection id into its Rarible form
 func (h *NFTHandler) validateTraitRarityRequest(req *model.TraitRarityRequestDTO) error {
-	if req.CollectionID == "" {
-		return fmt.Errorf("invalid collection id")
+	collectionID, err := model.ParseCollectionID(req.CollectionID)
+	if err != nil {
+		return err
 	}
+	req.CollectionID = collectionID.String()
 
 	for _, property := range req.Properties {
 		if property.Key == "" || property.Value == "" {
 			return fmt.Errorf("invalid property param")
 		}
 	}
+
+	return validateTraitRarityOptions(req.TraitRarityOptions)
+}
+
+// validateTraitRarityOptions validates the sorting and filtering options of a trait rarity request
+func validateTraitRarityOptions(req model.TraitRarityOptions) error {
+	switch req.Sort {
+	case "", model.TraitSortRequest, model.TraitSortRarity, model.TraitSortKey:
+	default:
+		return fmt.Errorf("sort must be %s, %s or %s", model.TraitSortRequest, model.TraitSortRarity, model.TraitSortKey)
+	}
+	switch req.Order {
+	case "", model.OrderAsc, model.OrderDesc:
+	default:
+		return fmt.Errorf("order must be %s or %s", model.OrderAsc, model.OrderDesc)
+	}
+	if req.MinRarity != nil && req.MaxRarity != nil && *req.MinRarity > *req.MaxRarity {
+		return fmt.Errorf("minRarity must not exceed maxRarity")
+	}
+	for _, tier := range req.Tiers {
+		if !model.IsRarityTier(tier) {
+			return fmt.Errorf("unknown tier %q", tier)
+		}
+	}
+	return nil
+}
+
+// validateRarityRankingsRequest validates the request, rewrites the collection id into its Rarible form and applies the default limit
+func (h *NFTHandler) validateRarityRankingsRequest(req *model.RarityRankingsRequestDTO) error {
+	collectionID, err := model.ParseCollectionID(req.CollectionID)
+	if err != nil {
+		return err
+	}
+	req.CollectionID = collectionID.String()
+
+	if err := validateRarityMethod(req.Method); err != nil {
+		return err
+	}
+	if req.Offset < 0 {
+		return fmt.Errorf("offset must not be negative")
+	}
+	if req.Limit == 0 {
+		req.Limit = model.DefaultRarityRankingsLimit
+	}
+	if req.Limit < 0 || req.Limit > model.MaxRarityRankingsLimit {
+		return fmt.Errorf("limit must be between 1 and %d", model.MaxRarityRankingsLimit)
+	}
+	return validateTraitFilter(req.Filter)
+}
+
+// validateTraitFilter accepts an empty filter or one that parses, syntax errors give the offending position
+func validateTraitFilter(query string) error {
+	if query == "" {
+		return nil
+	}
+	if _, err := filter.Parse(query); err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	return nil
+}
+
+// validateRarityMethod accepts an empty method, which defaults to information content, or a known one
+func validateRarityMethod(method string) error {
+	if method != "" && !rarity.Method(method).Valid() {
+		return fmt.Errorf("method must be %s, %s or %s", rarity.MethodInformationContent, rarity.MethodRarityScore, rarity.MethodStatistical)
+	}
+	return nil
+}
+
+// validateTraitCombinationsRequest validates the request, rewrites the collection id into its Rarible form and applies the defaults
+func (h *NFTHandler) validateTraitCombinationsRequest(req *model.TraitCombinationsRequestDTO) error {
+	collectionID, err := model.ParseCollectionID(req.CollectionID)
+	if err != nil {
+		return err
+	}
+	req.CollectionID = collectionID.String()
+
+	if req.Size == 0 {
+		req.Size = model.DefaultTraitCombinationSize
+	}
+	if req.Size < 2 || req.Size > model.MaxTraitCombinationSize {
+		return fmt.Errorf("size must be between 2 and %d", model.MaxTraitCombinationSize)
+	}
+	if req.Limit == 0 {
+		req.Limit = model.DefaultTraitCombinationLimit
+	}
+	if req.Limit < 0 || req.Limit > model.MaxTraitCombinationLimit {
+		return fmt.Errorf("limit must be between 1 and %d", model.MaxTraitCombinationLimit)
+	}
+	return validateTraitFilter(req.Filter)
+}
+
+func validateTraitStatsRequest(req *model.TraitStatsRequestDTO) error {
+	collectionID, err := model.ParseCollectionID(req.CollectionID)
+	if err != nil {
+		return err
+	}
+	req.CollectionID = collectionID.String()
+
+	if req.Format == "" {
+		req.Format = model.TraitStatsFormatJSON
+	}
+	if req.Format != model.TraitStatsFormatJSON && req.Format != model.TraitStatsFormatCSV {
+		return fmt.Errorf("format must be %s or %s", model.TraitStatsFormatJSON, model.TraitStatsFormatCSV)
+	}
+	if req.LongTail != nil && (*req.LongTail < 0 || *req.LongTail > 100) {
+		return errors.New("longTail must be between 0 and 100")
+	}
+	return validateTraitFilter(req.Filter)
+}
+
+// validateSimilarItemsRequest validates the request, splits comma separated excluded keys and applies the defaults
+func validateSimilarItemsRequest(req *model.SimilarItemsRequestDTO) error {
+	if req.Method != "" && !rarity.Similarity(req.Method).Valid() {
+		return fmt.Errorf("method must be %s or %s", rarity.SimilarityJaccard, rarity.SimilarityWeighted)
+	}
+
+	var exclude []string
+	for _, keys := range req.Exclude {
+		for _, key := range strings.Split(keys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				exclude = append(exclude, key)
+			}
+		}
+	}
+	req.Exclude = exclude
+
+	if req.Limit == 0 {
+		req.Limit = model.DefaultSimilarItemsLimit
+	}
+	if req.Limit < 0 || req.Limit > model.MaxSimilarItemsLimit {
+		return fmt.Errorf("limit must be between 1 and %d", model.MaxSimilarItemsLimit)
+	}
+	return validateTraitFilter(req.Filter)
+}
+
+// validateOpenRarityRankings requires at least one token, every token with an id and a positive rank, and no id twice
+func validateOpenRarityRankings(rankings model.OpenRarityRankingsDTO) error {
+	if len(rankings.Tokens) == 0 {
+		return errors.New("tokens must not be empty")
+	}
+	seen := make(map[model.OpenRarityTokenID]bool, len(rankings.Tokens))
+	for i, token := range rankings.Tokens {
+		if strings.TrimSpace(string(token.TokenID)) == "" {
+			return fmt.Errorf("tokens[%d]: token_id is required", i)
+		}
+		if token.Rank < 1 {
+			return fmt.Errorf("tokens[%d]: rank must be positive", i)
+		}
+		if seen[token.TokenID] {
+			return fmt.Errorf("tokens[%d]: duplicate token_id %s", i, token.TokenID)
+		}
+		seen[token.TokenID] = true
+	}
+	return nil
+}
+
+// validateMetadataRarityRequest validates the request and rewrites a CAIP-19 collection id into its Rarible form
+func validateMetadataRarityRequest(req *model.MetadataRarityRequestDTO) error {
+	if req.CollectionID != "" {
+		collectionID, err := model.ParseCollectionID(req.CollectionID)
+		if err != nil {
+			return err
+		}
+		req.CollectionID = collectionID.String()
+	}
+	if err := validateRarityMethod(req.Method); err != nil {
+		return err
+	}
+	if req.LongTail != nil && (*req.LongTail < 0 || *req.LongTail > 100) {
+		return errors.New("longTail must be between 0 and 100")
+	}
 	return nil
 }
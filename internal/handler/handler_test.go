@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -19,6 +21,11 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+const (
+	testOwnershipID  = "ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:123456:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10"
+	testCollectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+)
+
 func TestNFTHandler_GetOwnership(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -27,15 +34,15 @@ func TestNFTHandler_GetOwnership(t *testing.T) {
 	h := NewNFTHandler(mockService)
 
 	t.Run("Success", func(t *testing.T) {
-		mockOwnership := &model.OwnershipDTO{ID: "id-123", Owner: "0xabc", StatusCode: http.StatusOK}
-		mockService.EXPECT().GetOwnershipByID(gomock.Any(), "id-123").Return(mockOwnership, nil)
+		mockOwnership := &model.OwnershipDTO{ID: testOwnershipID, Owner: "0xabc", StatusCode: http.StatusOK}
+		mockService.EXPECT().GetOwnershipByID(gomock.Any(), testOwnershipID).Return(mockOwnership, nil)
 
 		e := echo.New()
-		req := httptest.NewRequest(http.MethodGet, "/ownerships/id-123", http.NoBody)
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/"+testOwnershipID, http.NoBody)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
 		c.SetParamNames("id")
-		c.SetParamValues("id-123")
+		c.SetParamValues(testOwnershipID)
 
 		err := h.GetOwnership(c)
 		require.NoError(t, err)
@@ -47,15 +54,71 @@ func TestNFTHandler_GetOwnership(t *testing.T) {
 		require.Equal(t, constants.StatusRetrieved, resp.Status.Status)
 	})
 
+	t.Run("SuccessWithCAIPIDs", func(t *testing.T) {
+		mockOwnership := &model.OwnershipDTO{ID: testOwnershipID, StatusCode: http.StatusOK}
+		mockService.EXPECT().GetOwnershipByID(gomock.Any(), testOwnershipID).Return(mockOwnership, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/"+testOwnershipID+"?caip=true", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(testOwnershipID)
+
+		err := h.GetOwnership(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp struct {
+			Data model.OwnershipDTO `json:"data"`
+		}
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		require.NotNil(t, resp.Data.CAIP)
+		require.Equal(t, "eip155:1", resp.Data.CAIP.Chain)
+		require.Equal(t, "eip155:1/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d/123456", resp.Data.CAIP.Asset)
+		require.Equal(t, "eip155:1:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10", resp.Data.CAIP.Owner)
+	})
+
+	t.Run("KeepsCallerCasing", func(t *testing.T) {
+		mixedCaseID := "ETHEREUM:0x06012C8cf97BEaD5deAe237070F9587f8E7A266d:123456:0x4B7A2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10"
+		mockOwnership := &model.OwnershipDTO{ID: testOwnershipID, StatusCode: http.StatusOK}
+		mockService.EXPECT().GetOwnershipByID(gomock.Any(), mixedCaseID).Return(mockOwnership, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/"+mixedCaseID, http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(mixedCaseID)
+
+		err := h.GetOwnership(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("InvalidCAIPFlag", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/"+testOwnershipID+"?caip=maybe", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(testOwnershipID)
+
+		err := h.GetOwnership(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
 	t.Run("NotFoundError", func(t *testing.T) {
-		mockService.EXPECT().GetOwnershipByID(gomock.Any(), "missing-id").Return(nil, businesserrors.ErrNotFound)
+		mockService.EXPECT().GetOwnershipByID(gomock.Any(), testOwnershipID).Return(nil, businesserrors.ErrNotFound)
 
 		e := echo.New()
-		req := httptest.NewRequest(http.MethodGet, "/ownerships/missing-id", nil)
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/"+testOwnershipID, nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
 		c.SetParamNames("id")
-		c.SetParamValues("missing-id")
+		c.SetParamValues(testOwnershipID)
 
 		err := h.GetOwnership(c)
 		require.NoError(t, err)
@@ -63,29 +126,47 @@ func TestNFTHandler_GetOwnership(t *testing.T) {
 	})
 
 	t.Run("InvalidRequestError", func(t *testing.T) {
-		mockService.EXPECT().GetOwnershipByID(gomock.Any(), "bad-id").Return(nil, businesserrors.ErrInvalidRequest)
+		mockService.EXPECT().GetOwnershipByID(gomock.Any(), testOwnershipID).Return(nil, businesserrors.ErrInvalidRequest)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/"+testOwnershipID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(testOwnershipID)
 
+		err := h.GetOwnership(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("MalformedID", func(t *testing.T) {
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodGet, "/ownerships/bad-id", nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
 		c.SetParamNames("id")
-		c.SetParamValues("bad-id")
+		c.SetParamValues("ETHEREUM:0x123:1:0xabc")
 
 		err := h.GetOwnership(c)
 		require.NoError(t, err)
 		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var resp dto.GeneralResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		require.Contains(t, resp.Status.Error, "contract")
 	})
 
 	t.Run("InternalServerError", func(t *testing.T) {
-		mockService.EXPECT().GetOwnershipByID(gomock.Any(), "error-id").Return(nil, errors.New("some error"))
+		mockService.EXPECT().GetOwnershipByID(gomock.Any(), testOwnershipID).Return(nil, errors.New("some error"))
 
 		e := echo.New()
-		req := httptest.NewRequest(http.MethodGet, "/ownerships/error-id", nil)
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/"+testOwnershipID, nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
 		c.SetParamNames("id")
-		c.SetParamValues("error-id")
+		c.SetParamValues(testOwnershipID)
 
 		err := h.GetOwnership(c)
 		require.NoError(t, err)
@@ -93,6 +174,91 @@ func TestNFTHandler_GetOwnership(t *testing.T) {
 	})
 }
 
+func TestNFTHandler_GetOwnershipBySegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	t.Run("SuccessWithNormalisedCasing", func(t *testing.T) {
+		mockOwnership := &model.OwnershipDTO{ID: testOwnershipID, StatusCode: http.StatusOK}
+		mockService.EXPECT().GetOwnershipByID(gomock.Any(), testOwnershipID).Return(mockOwnership, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/ethereum/0x06012C8cf97BEaD5deAe237070F9587f8E7A266d/123456/0x4B7A2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("blockchain", "contract", "tokenId", "owner")
+		c.SetParamValues("ethereum", "0x06012C8cf97BEaD5deAe237070F9587f8E7A266d", "123456", "0x4B7A2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+
+		err := h.GetOwnershipBySegments(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("InvalidSegments", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/ETHEREUM/0x123/abc/0xabc", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("blockchain", "contract", "tokenId", "owner")
+		c.SetParamValues("ETHEREUM", "0x123", "abc", "0xabc")
+
+		err := h.GetOwnershipBySegments(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestNFTHandler_GetOwnershipByMintSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	const (
+		mint  = "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"
+		owner = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		solanaOwnershipID := "SOLANA:" + mint + ":" + owner
+		mockService.EXPECT().GetOwnershipByID(gomock.Any(), solanaOwnershipID).
+			Return(&model.OwnershipDTO{ID: solanaOwnershipID, StatusCode: http.StatusOK}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/SOLANA/"+mint+"/"+owner, http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("blockchain", "mint", "owner")
+		c.SetParamValues("SOLANA", mint, owner)
+
+		err := h.GetOwnershipByMintSegments(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("BadRequestForBlockchainWithTokenIDs", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/ETHEREUM/0x06012c8cf97bead5deae237070f9587f8e7a266d/0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("blockchain", "mint", "owner")
+		c.SetParamValues("ETHEREUM", "0x06012c8cf97bead5deae237070f9587f8e7a266d", "0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10")
+
+		err := h.GetOwnershipByMintSegments(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var resp dto.GeneralResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		require.Contains(t, resp.Status.Error, "ETHEREUM items need a token id")
+	})
+}
+
 func TestNFTHandler_GetTraitRarities(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -102,7 +268,7 @@ func TestNFTHandler_GetTraitRarities(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		reqBody := model.TraitRarityRequestDTO{
-			CollectionID: "ETHEREUM:0x123",
+			CollectionID: testCollectionID,
 			Properties: []model.TraitPropertyInput{
 				{Key: "Hat", Value: "Halo"},
 			},
@@ -111,7 +277,7 @@ func TestNFTHandler_GetTraitRarities(t *testing.T) {
 		mockResponse := &model.TraitRarityResponseDTO{
 			Continuation: "token123",
 			Traits: []model.ExtendedTraitProperty{
-				{Key: "Hat", Value: "Halo", Rarity: "1.2"},
+				{Key: "Hat", Value: "Halo", Rarity: 1.2},
 			},
 			StatusCode: http.StatusOK,
 		}
@@ -135,6 +301,28 @@ func TestNFTHandler_GetTraitRarities(t *testing.T) {
 		require.Equal(t, constants.StatusRetrieved, resp.Status.Status)
 	})
 
+	t.Run("SuccessWithCAIPCollectionID", func(t *testing.T) {
+		expected := model.TraitRarityRequestDTO{
+			CollectionID: testCollectionID,
+			Properties:   []model.TraitPropertyInput{{Key: "Hat", Value: "Halo"}},
+		}
+		mockService.EXPECT().GetTraitRarity(gomock.Any(), expected).Return(&model.TraitRarityResponseDTO{StatusCode: http.StatusOK}, nil)
+
+		reqBody := expected
+		reqBody.CollectionID = "eip155:1/erc721:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+		e := echo.New()
+		bodyBytes, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(http.MethodPost, "/traits/rarity", bytes.NewReader(bodyBytes))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.GetTraitRarities(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
 	t.Run("BadRequestInvalidBody", func(t *testing.T) {
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodPost, "/traits/rarity", bytes.NewReader([]byte("invalid json")))
@@ -164,9 +352,26 @@ func TestNFTHandler_GetTraitRarities(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, rec.Code)
 	})
 
+	t.Run("BadRequestInvalidOptions", func(t *testing.T) {
+		for _, query := range []string{"sort=price", "order=up", "minRarity=10&maxRarity=1", "tier=mythic"} {
+			reqBody := model.TraitRarityRequestDTO{CollectionID: testCollectionID}
+			bodyBytes, _ := json.Marshal(reqBody)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/trait-rarities?"+query, bytes.NewReader(bodyBytes))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := h.GetTraitRarities(c)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
 	t.Run("ServiceError", func(t *testing.T) {
 		reqBody := model.TraitRarityRequestDTO{
-			CollectionID: "ETHEREUM:0x123",
+			CollectionID: testCollectionID,
 			Properties: []model.TraitPropertyInput{
 				{Key: "Hat", Value: "Halo"},
 			},
@@ -186,3 +391,611 @@ func TestNFTHandler_GetTraitRarities(t *testing.T) {
 		require.Equal(t, http.StatusInternalServerError, rec.Code)
 	})
 }
+
+func TestNFTHandler_Resolve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	const input = "https://opensea.io/assets/ethereum/0x06012c8cf97bead5deae237070f9587f8e7a266d/123456"
+
+	t.Run("Success", func(t *testing.T) {
+		expected := model.ResolveRequestDTO{Input: input, Include: true}
+		mockService.EXPECT().Resolve(gomock.Any(), expected).Return(&model.ResolveResponseDTO{Input: input, Source: "opensea"}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/resolve?include=true&input="+url.QueryEscape(input), http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.Resolve(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp dto.GeneralResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		require.Equal(t, constants.StatusRetrieved, resp.Status.Status)
+	})
+
+	t.Run("UnrecognisedInput", func(t *testing.T) {
+		mockService.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(nil, businesserrors.ErrInvalidRequest)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/resolve?input=nope", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.Resolve(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("InvalidIncludeFlag", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/resolve?include=maybe&input=x", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.Resolve(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("UpstreamOverloaded", func(t *testing.T) {
+		mockService.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(nil, businesserrors.ErrUpstreamOverloaded)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/resolve?include=true&input="+url.QueryEscape(input), http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.Resolve(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
+
+func TestNFTHandler_GetRarityRankings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+	newContext := func(target, id string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(idParam)
+		c.SetParamValues(id)
+		return c, rec
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		traitCount := false
+		expected := model.RarityRankingsRequestDTO{
+			CollectionID: collectionID,
+			Method:       "statistical",
+			Offset:       10,
+			Limit:        model.DefaultRarityRankingsLimit,
+			TraitCount:   &traitCount,
+		}
+		mockService.EXPECT().GetRarityRankings(gomock.Any(), expected).
+			Return(&model.RarityRankingsResponseDTO{CollectionID: collectionID, Total: 1}, nil)
+
+		c, rec := newContext("/collections/x/rarity-rankings?method=statistical&offset=10&traitCount=false", collectionID)
+
+		err := h.GetRarityRankings(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("AcceptsCAIPCollectionID", func(t *testing.T) {
+		mockService.EXPECT().GetRarityRankings(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ any, req model.RarityRankingsRequestDTO) (*model.RarityRankingsResponseDTO, error) {
+				require.Equal(t, collectionID, req.CollectionID)
+				return &model.RarityRankingsResponseDTO{}, nil
+			})
+
+		c, rec := newContext("/", url.PathEscape("eip155:1/erc721:0x60e4d786628fea6478f785a6d7e704777c86a7c6"))
+
+		err := h.GetRarityRankings(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("BadRequestInvalidParams", func(t *testing.T) {
+		for _, target := range []string{
+			"/?method=popularity",
+			"/?limit=1001",
+			"/?offset=-1",
+			"/?missingTraits=maybe",
+		} {
+			c, rec := newContext(target, collectionID)
+
+			err := h.GetRarityRankings(c)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, rec.Code, target)
+		}
+
+		c, rec := newContext("/", "not-a-collection")
+		err := h.GetRarityRankings(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("BadRequestFilterSyntax", func(t *testing.T) {
+		c, rec := newContext("/?filter="+url.QueryEscape(`Hat = Halo AND (Eyes = Red`), collectionID)
+
+		err := h.GetRarityRankings(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), "invalid filter: syntax error at position 27: expected ')', got end of filter")
+	})
+
+	t.Run("CollectionTooLarge", func(t *testing.T) {
+		mockService.EXPECT().GetRarityRankings(gomock.Any(), gomock.Any()).Return(nil, businesserrors.ErrInvalidRequest)
+
+		c, rec := newContext("/", collectionID)
+
+		err := h.GetRarityRankings(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestNFTHandler_GetItemRarity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	const itemID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6:1"
+
+	newContext := func(target, id string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(idParam)
+		c.SetParamValues(id)
+		return c, rec
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.EXPECT().GetItemRarity(gomock.Any(), model.ItemRarityRequestDTO{ItemID: itemID, Method: "rarity_score"}).
+			Return(&model.ItemRarityBreakdownDTO{ItemRarityDTO: model.ItemRarityDTO{ItemID: itemID, Rank: 37}}, nil)
+
+		c, rec := newContext("/?method=rarity_score", "ETHEREUM:0x60E4D786628FEA6478F785A6D7E704777C86A7C6:1")
+
+		err := h.GetItemRarity(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Contains(t, rec.Body.String(), `"rank":37`)
+	})
+
+	t.Run("BadRequest", func(t *testing.T) {
+		c, rec := newContext("/", "not-an-item")
+		err := h.GetItemRarity(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		c, rec = newContext("/?method=popularity", itemID)
+		err = h.GetItemRarity(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockService.EXPECT().GetItemRarity(gomock.Any(), gomock.Any()).Return(nil, businesserrors.ErrNotFound)
+
+		c, rec := newContext("/", itemID)
+
+		err := h.GetItemRarity(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestNFTHandler_GetItemTraitRarities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	const itemID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6:1"
+
+	newContext := func(target, id string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(idParam)
+		c.SetParamValues(id)
+		return c, rec
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		expected := model.ItemTraitRarityRequestDTO{
+			ItemID:             itemID,
+			TraitRarityOptions: model.TraitRarityOptions{Sort: model.TraitSortRarity, Tiers: []string{model.TierEpic, model.TierRare}},
+		}
+		mockService.EXPECT().GetItemTraitRarity(gomock.Any(), expected).
+			Return(&model.ItemTraitRarityResponseDTO{ItemID: itemID, Status: model.ItemTraitsStatusUnrevealed}, nil)
+
+		c, rec := newContext("/?sort=rarity&tier=epic&tier=rare", itemID)
+
+		err := h.GetItemTraitRarities(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Contains(t, rec.Body.String(), `"status":"unrevealed"`)
+	})
+
+	t.Run("BadRequest", func(t *testing.T) {
+		c, rec := newContext("/", "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6")
+		err := h.GetItemTraitRarities(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		c, rec = newContext("/?tier=mythic", itemID)
+		err = h.GetItemTraitRarities(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestNFTHandler_TraitCombinations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+	t.Run("ShouldApplyDefaults", func(t *testing.T) {
+		mockService.EXPECT().GetTraitCombinations(gomock.Any(), model.TraitCombinationsRequestDTO{CollectionID: collectionID, Size: 2, Limit: 20}).
+			Return(&model.TraitCombinationsResponseDTO{}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(idParam)
+		c.SetParamValues(collectionID)
+
+		err := h.GetTraitCombinations(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("ShouldRejectInvalidSize", func(t *testing.T) {
+		for _, size := range []string{"1", "4"} {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/?size="+size, http.NoBody)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames(idParam)
+			c.SetParamValues(collectionID)
+
+			err := h.GetTraitCombinations(c)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, rec.Code, size)
+		}
+	})
+
+	t.Run("ShouldPassFilter", func(t *testing.T) {
+		mockService.EXPECT().GetTraitCombinations(gomock.Any(), model.TraitCombinationsRequestDTO{CollectionID: collectionID, Size: 2, Limit: 20, Filter: "Eyes = Laser"}).
+			Return(&model.TraitCombinationsResponseDTO{}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?filter=Eyes%20%3D%20Laser", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(idParam)
+		c.SetParamValues(collectionID)
+
+		err := h.GetTraitCombinations(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("ShouldRejectInvalidFilter", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?filter=Eyes%20%3D", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(idParam)
+		c.SetParamValues(collectionID)
+
+		err := h.GetTraitCombinations(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), "position")
+	})
+
+	t.Run("ShouldGetCombinationRarity", func(t *testing.T) {
+		body := model.TraitRarityRequestDTO{
+			CollectionID: collectionID,
+			Properties:   []model.TraitPropertyInput{{Key: "Eyes", Value: "Laser"}, {Key: "Background", Value: "Gold"}},
+		}
+		mockService.EXPECT().GetTraitCombinationRarity(gomock.Any(), body).Return(&model.TraitCombinationRarityDTO{Total: 4}, nil)
+
+		bodyBytes, _ := json.Marshal(body)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/trait-combinations/rarity", bytes.NewReader(bodyBytes))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.GetTraitCombinationRarity(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("ShouldRequireProperties", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/trait-combinations/rarity", strings.NewReader(`{"collectionId":"`+collectionID+`","properties":[]}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.GetTraitCombinationRarity(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestNFTHandler_GetTraitStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+	stats := &model.TraitStatsResponseDTO{
+		CollectionID: collectionID,
+		Items:        4,
+		TraitKeys: []model.TraitKeyStatsDTO{{
+			Key: "Hat", Values: 2, Items: 3, Missing: 1, Entropy: 1.5, NormalizedEntropy: 0.946395,
+			LongTail: []string{"Crown"},
+			Distribution: []model.TraitValueStatsDTO{
+				{Value: "Cap", Count: 2, Rarity: 50, Tier: model.TierCommon},
+				{Value: "Crown", Count: 1, Rarity: 25, Tier: model.TierUncommon},
+			},
+		}},
+	}
+
+	t.Run("ShouldReturnJSON", func(t *testing.T) {
+		mockService.EXPECT().GetTraitStats(gomock.Any(), model.TraitStatsRequestDTO{CollectionID: collectionID, Format: model.TraitStatsFormatJSON}).
+			Return(stats, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(idParam)
+		c.SetParamValues(collectionID)
+
+		err := h.GetTraitStats(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Contains(t, rec.Body.String(), `"normalizedEntropy":0.946395`)
+	})
+
+	t.Run("ShouldReturnCSV", func(t *testing.T) {
+		mockService.EXPECT().GetTraitStats(gomock.Any(), model.TraitStatsRequestDTO{CollectionID: collectionID, Format: model.TraitStatsFormatCSV}).
+			Return(stats, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?format=csv", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(idParam)
+		c.SetParamValues(collectionID)
+
+		err := h.GetTraitStats(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		require.Equal(t, "key,value,count,rarity,tier,long_tail,key_values,key_items,key_missing,key_entropy,key_normalized_entropy\n"+
+			"Hat,Cap,2,50,common,false,2,3,1,1.5,0.946395\n"+
+			"Hat,Crown,1,25,uncommon,true,2,3,1,1.5,0.946395\n", rec.Body.String())
+	})
+
+	t.Run("ShouldRejectInvalidParameters", func(t *testing.T) {
+		for _, query := range []string{"format=xml", "longTail=101", "longTail=abc", "filter=Hat%20%3D"} {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/?"+query, http.NoBody)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames(idParam)
+			c.SetParamValues(collectionID)
+
+			err := h.GetTraitStats(c)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+}
+
+func TestNFTHandler_GetSimilarItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	const itemID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6:1"
+
+	t.Run("ShouldSplitExcludedKeysAndApplyDefaults", func(t *testing.T) {
+		mockService.EXPECT().GetSimilarItems(gomock.Any(), model.SimilarItemsRequestDTO{ItemID: itemID, Exclude: []string{"Hat", "Eyes", "Background"}, Limit: 10}).
+			Return(&model.SimilarItemsResponseDTO{}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?exclude=Hat,Eyes&exclude=Background", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(idParam)
+		c.SetParamValues(itemID)
+
+		err := h.GetSimilarItems(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("ShouldRejectInvalidParameters", func(t *testing.T) {
+		for _, query := range []string{"method=cosine", "limit=101", "limit=-1"} {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/?"+query, http.NoBody)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames(idParam)
+			c.SetParamValues(itemID)
+
+			err := h.GetSimilarItems(c)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+}
+
+func TestNFTHandler_OpenRarity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+	newContext := func(method, body string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(idParam)
+		c.SetParamValues(collectionID)
+		return c, rec
+	}
+
+	t.Run("ShouldExportBareFile", func(t *testing.T) {
+		mockService.EXPECT().ExportOpenRarity(gomock.Any(), collectionID).Return(&model.OpenRarityRankingsDTO{
+			TotalSupply: 1,
+			Tokens:      []model.OpenRarityTokenDTO{{TokenID: "1", Rank: 1, Score: 1}},
+		}, nil)
+
+		c, rec := newContext(http.MethodGet, "")
+
+		err := h.ExportOpenRarity(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"total_supply":1,"tokens":[{"token_id":"1","rank":1,"score":1,"unique_attribute_count":0}]}`, rec.Body.String())
+	})
+
+	t.Run("ShouldAcceptNumericTokenIDs", func(t *testing.T) {
+		mockService.EXPECT().DiffOpenRarity(gomock.Any(), model.OpenRarityDiffRequestDTO{
+			CollectionID: collectionID,
+			Rankings:     model.OpenRarityRankingsDTO{Tokens: []model.OpenRarityTokenDTO{{TokenID: "7", Rank: 1, Score: 0.5}, {TokenID: "8", Rank: 2}}},
+		}).Return(&model.OpenRarityDiffDTO{}, nil)
+
+		c, rec := newContext(http.MethodPost, `{"tokens":[{"token_id":7,"rank":1,"score":0.5},{"token_id":"8","rank":2}]}`)
+
+		err := h.DiffOpenRarity(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("ShouldRejectInvalidFile", func(t *testing.T) {
+		for _, body := range []string{
+			`{"tokens":[]}`,
+			`{"tokens":[{"token_id":"1","rank":0}]}`,
+			`{"tokens":[{"rank":1}]}`,
+			`{"tokens":[{"token_id":"1","rank":1},{"token_id":"1","rank":2}]}`,
+			`{"tokens":[{"token_id":true,"rank":1}]}`,
+		} {
+			c, rec := newContext(http.MethodPost, body)
+
+			err := h.DiffOpenRarity(c)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, rec.Code, body)
+		}
+	})
+}
+
+func TestNFTHandler_GetMetadataRarity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	h := NewNFTHandler(mockService)
+
+	archive := []byte("PK\x03\x04 archive")
+
+	t.Run("ShouldReadRawBody", func(t *testing.T) {
+		mockService.EXPECT().GetMetadataRarity(gomock.Any(), model.MetadataRarityRequestDTO{
+			Archive:      archive,
+			CollectionID: testCollectionID,
+			Method:       "rarity_score",
+		}).Return(&model.MetadataRarityResponseDTO{Total: 1}, nil)
+
+		e := echo.New()
+		target := "/?method=rarity_score&collection=" + url.QueryEscape("eip155:1/erc721:0x60e4d786628fea6478f785a6d7e704777c86a7c6")
+		req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(archive))
+		req.Header.Set(echo.HeaderContentType, "application/zip")
+		rec := httptest.NewRecorder()
+
+		err := h.GetMetadataRarity(e.NewContext(req, rec))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("ShouldReadMultipartForm", func(t *testing.T) {
+		mockService.EXPECT().GetMetadataRarity(gomock.Any(), model.MetadataRarityRequestDTO{Archive: archive}).
+			Return(&model.MetadataRarityResponseDTO{Total: 1}, nil)
+
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		part, err := w.CreateFormFile("archive", "metadata.zip")
+		require.NoError(t, err)
+		_, err = part.Write(archive)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", &body)
+		req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+		rec := httptest.NewRecorder()
+
+		err = h.GetMetadataRarity(e.NewContext(req, rec))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("ShouldRejectInvalidRequests", func(t *testing.T) {
+		for target, body := range map[string]string{
+			"/?method=popularity":           "archive",
+			"/?collection=not-a-collection": "archive",
+			"/?longTail=-1":                 "archive",
+			"/":                             "",
+		} {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+			rec := httptest.NewRecorder()
+
+			err := h.GetMetadataRarity(e.NewContext(req, rec))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, rec.Code, target)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+
+	"github.com/Megidy/rarible/internal/client"
+	"github.com/Megidy/rarible/internal/domain/constants"
+	"github.com/Megidy/rarible/internal/handler/dto"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	callerAPIKeyHeader = "X-Rarible-Api-Key"
+	tenantTokenHeader  = "X-Rarible-Tenant-Token"
+	adminTokenHeader   = "X-Admin-Token"
+	environmentHeader  = "X-Rarible-Environment"
+	environmentParam   = "environment"
+)
+
+// CallerTenant is a partner allowed to bill upstream calls to its own Rarible API key
+type CallerTenant struct {
+	Token string
+	// APIKey is used when the tenant sends no key of its own, empty falls back to the shared keys
+	APIKey string
+}
+
+// callerAPIKey authenticates the caller by its tenant token and moves the
+// tenant's Rarible API key, from the X-Rarible-Api-Key header or the one stored
+// for the tenant, into the request context so upstream calls for this request
+// are billed to it. Keys from unauthenticated callers are rejected, and both
+// headers are removed afterwards so they cannot leak into request logs.
+func callerAPIKey(tenants []CallerTenant) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			token := req.Header.Get(tenantTokenHeader)
+			key := req.Header.Get(callerAPIKeyHeader)
+			if token == "" && key == "" {
+				return next(ctx)
+			}
+			req.Header.Del(tenantTokenHeader)
+			req.Header.Del(callerAPIKeyHeader)
+
+			tenant, ok := findTenant(tenants, token)
+			if !ok {
+				return ctx.JSON(http.StatusUnauthorized, dto.NewGeneralResponse(
+					nil,
+					constants.StatusFailed,
+					"unauthorized caller",
+					fmt.Sprintf("%s requires a valid %s", callerAPIKeyHeader, tenantTokenHeader),
+					http.StatusUnauthorized))
+			}
+
+			if key == "" {
+				key = tenant.APIKey
+			}
+			ctx.SetRequest(req.WithContext(client.WithAPIKey(req.Context(), key)))
+			return next(ctx)
+		}
+	}
+}
+
+// findTenant returns the tenant owning token, comparing in constant time
+func findTenant(tenants []CallerTenant, token string) (CallerTenant, bool) {
+	if token == "" {
+		return CallerTenant{}, false
+	}
+	for _, tenant := range tenants {
+		if subtle.ConstantTimeCompare([]byte(tenant.Token), []byte(token)) == 1 {
+			return tenant, true
+		}
+	}
+	return CallerTenant{}, false
+}
+
+// adminToken rejects requests without the admin token in the X-Admin-Token header,
+// comparing in constant time
+func adminToken(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if subtle.ConstantTimeCompare([]byte(ctx.Request().Header.Get(adminTokenHeader)), []byte(token)) != 1 {
+				return ctx.JSON(http.StatusUnauthorized, dto.NewGeneralResponse(
+					nil,
+					constants.StatusFailed,
+					"unauthorized caller",
+					fmt.Sprintf("admin routes require a valid %s", adminTokenHeader),
+					http.StatusUnauthorized))
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// upstreamEnvironment selects the upstream environment from the path prefix or
+// the X-Rarible-Environment header, falling back to the default one, and echoes
+// the chosen environment back in the response headers
+func upstreamEnvironment(environments *client.EnvironmentRouter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			name := getFromParam(ctx, environmentParam)
+			if name == "" {
+				name = ctx.Request().Header.Get(environmentHeader)
+			}
+			if name == "" {
+				name = environments.Default()
+			}
+
+			if !environments.Has(name) {
+				return ctx.JSON(http.StatusBadRequest, dto.NewGeneralResponse(
+					nil,
+					constants.StatusFailed,
+					"unknown upstream environment",
+					fmt.Sprintf("environment %q is not configured, expected one of %v", name, environments.Names()),
+					http.StatusBadRequest))
+			}
+
+			ctx.Response().Header().Set(environmentHeader, name)
+			req := ctx.Request()
+			ctx.SetRequest(req.WithContext(client.WithEnvironment(req.Context(), name)))
+			return next(ctx)
+		}
+	}
+}
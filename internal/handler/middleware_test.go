@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Megidy/rarible/internal/client"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallerAPIKeyMiddleware(t *testing.T) {
+	var upstreamKey string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamKey = r.Header.Get("X-API-KEY")
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	raribleClient := client.NewRaribleClient("shared-key", upstream.URL)
+	next := func(ctx echo.Context) error {
+		require.Empty(t, ctx.Request().Header.Get(callerAPIKeyHeader))
+		require.Empty(t, ctx.Request().Header.Get(tenantTokenHeader))
+		_, err := raribleClient.GetOwnershipByID(ctx.Request().Context(), "id-123")
+		return err
+	}
+	tenants := []CallerTenant{
+		{Token: "partner-token"},
+		{Token: "stored-token", APIKey: "stored-key"},
+	}
+
+	serve := func(headers map[string]string) *httptest.ResponseRecorder {
+		upstreamKey = ""
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/id-123", http.NoBody)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := callerAPIKey(tenants)(next)(c)
+		require.NoError(t, err)
+		return rec
+	}
+
+	t.Run("UsesCallerKeyOfAuthenticatedTenant", func(t *testing.T) {
+		rec := serve(map[string]string{tenantTokenHeader: "partner-token", callerAPIKeyHeader: "caller-key"})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "caller-key", upstreamKey)
+	})
+
+	t.Run("UsesStoredTenantKey", func(t *testing.T) {
+		rec := serve(map[string]string{tenantTokenHeader: "stored-token"})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "stored-key", upstreamKey)
+	})
+
+	t.Run("FallsBackToSharedKey", func(t *testing.T) {
+		rec := serve(nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "shared-key", upstreamKey)
+	})
+
+	t.Run("RejectsCallerKeyWithoutTenantToken", func(t *testing.T) {
+		rec := serve(map[string]string{callerAPIKeyHeader: "caller-key"})
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		require.Empty(t, upstreamKey)
+	})
+
+	t.Run("RejectsUnknownTenantToken", func(t *testing.T) {
+		rec := serve(map[string]string{tenantTokenHeader: "guessed-token", callerAPIKeyHeader: "caller-key"})
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		require.Empty(t, upstreamKey)
+	})
+}
+
+func TestUpstreamEnvironmentMiddleware(t *testing.T) {
+	newServer := func(owner string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"owner":"` + owner + `"}`))
+		}))
+	}
+	mainnet := newServer("mainnet-owner")
+	defer mainnet.Close()
+	testnet := newServer("testnet-owner")
+	defer testnet.Close()
+
+	environments, err := client.NewEnvironmentRouter("mainnet", map[string]client.RaribleClient{
+		"mainnet": client.NewRaribleClient("mainnet-key", mainnet.URL),
+		"testnet": client.NewRaribleClient("testnet-key", testnet.URL),
+	})
+	require.NoError(t, err)
+
+	var owner string
+	next := func(ctx echo.Context) error {
+		ownership, err := environments.GetOwnershipByID(ctx.Request().Context(), "id-123")
+		if err != nil {
+			return err
+		}
+		owner = ownership.Owner
+		return nil
+	}
+
+	t.Run("SelectsFromHeader", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/id-123", http.NoBody)
+		req.Header.Set(environmentHeader, "testnet")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := upstreamEnvironment(environments)(next)(c)
+		require.NoError(t, err)
+		require.Equal(t, "testnet-owner", owner)
+		require.Equal(t, "testnet", rec.Header().Get(environmentHeader))
+	})
+
+	t.Run("SelectsFromPathPrefix", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/environments/testnet/ownerships/id-123", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(environmentParam, "id")
+		c.SetParamValues("testnet", "id-123")
+
+		err := upstreamEnvironment(environments)(next)(c)
+		require.NoError(t, err)
+		require.Equal(t, "testnet-owner", owner)
+	})
+
+	t.Run("DefaultsToMainnet", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/id-123", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := upstreamEnvironment(environments)(next)(c)
+		require.NoError(t, err)
+		require.Equal(t, "mainnet-owner", owner)
+		require.Equal(t, "mainnet", rec.Header().Get(environmentHeader))
+	})
+
+	t.Run("RejectsUnknownEnvironment", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/ownerships/id-123", http.NoBody)
+		req.Header.Set(environmentHeader, "devnet")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := upstreamEnvironment(environments)(next)(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
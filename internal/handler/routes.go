@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"github.com/Megidy/rarible/internal/client"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
@@ -8,28 +9,109 @@ import (
 
 const (
 	apiVersionV1 = "/v1"
+	adminPrefix  = "/admin"
+
+	// openRarityBodyLimit bounds uploaded OpenRarity ranking files
+	openRarityBodyLimit = "16M"
+	// metadataBodyLimit bounds uploaded metadata archives
+	metadataBodyLimit = "64M"
 )
 
 type Router struct {
 	echo       *echo.Echo
 	nftHandler *NFTHandler
+
+	callerTenants []CallerTenant
+	environments  *client.EnvironmentRouter
+	adminHandler  *AdminHandler
+	adminToken    string
+}
+
+// RouterOption configures optional behaviour of the router
+type RouterOption func(*Router)
+
+// WithCallerAPIKey lets the given tenants, authenticated by the X-Rarible-Tenant-Token
+// header, bill upstream calls to their own Rarible API key. Without tenants the
+// X-Rarible-Api-Key header is ignored.
+func WithCallerAPIKey(tenants []CallerTenant) RouterOption {
+	return func(r *Router) {
+		r.callerTenants = tenants
+	}
 }
 
-func NewRouter(echo *echo.Echo, nftHandler *NFTHandler) *Router {
-	return &Router{
+// WithEnvironments lets callers select an upstream environment per request,
+// either with the X-Rarible-Environment header or the /v1/environments/{environment} prefix
+func WithEnvironments(environments *client.EnvironmentRouter) RouterOption {
+	return func(r *Router) {
+		r.environments = environments
+	}
+}
+
+// WithAdminHandler registers the admin routes for callers sending token in the
+// X-Admin-Token header. Without a token they are not served at all, and fault
+// injection is only served when the handler has an injector, never in production.
+func WithAdminHandler(adminHandler *AdminHandler, token string) RouterOption {
+	return func(r *Router) {
+		r.adminHandler = adminHandler
+		r.adminToken = token
+	}
+}
+
+func NewRouter(echo *echo.Echo, nftHandler *NFTHandler, opts ...RouterOption) *Router {
+	r := &Router{
 		echo:       echo,
 		nftHandler: nftHandler,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 func (r *Router) RegisterRoutes() {
 	r.echo.Use(middleware.Logger())
 
 	apiVersionV1 := r.echo.Group(apiVersionV1)
+	if len(r.callerTenants) > 0 {
+		apiVersionV1.Use(callerAPIKey(r.callerTenants))
+	}
+	if r.environments != nil {
+		apiVersionV1.Use(upstreamEnvironment(r.environments))
+	}
 
 	r.echo.GET("/swagger/*", echoSwagger.WrapHandler)
 
-	apiVersionV1.GET("/ownerships/:id", r.nftHandler.GetOwnership)
-	apiVersionV1.GET("/trait-rarities", r.nftHandler.GetTraitRarities)
+	if r.adminHandler != nil && r.adminToken != "" {
+		admin := r.echo.Group(adminPrefix, adminToken(r.adminToken))
+		if r.adminHandler.faultInjector != nil {
+			admin.GET("/faults", r.adminHandler.GetFaults)
+			admin.PUT("/faults", r.adminHandler.SetFaults)
+		}
+		admin.GET("/upstream/keys", r.adminHandler.GetKeyStats)
+		admin.GET("/upstream/shadow", r.adminHandler.GetShadowSummary)
+		admin.GET("/upstream/drift", r.adminHandler.GetDriftSummary)
+	}
+
+	r.registerNFTRoutes(apiVersionV1)
+	if r.environments != nil {
+		r.registerNFTRoutes(apiVersionV1.Group("/environments/:" + environmentParam))
+	}
+}
 
+func (r *Router) registerNFTRoutes(group *echo.Group) {
+	group.GET("/ownerships/:id", r.nftHandler.GetOwnership)
+	group.GET("/ownerships/:blockchain/:mint/:owner", r.nftHandler.GetOwnershipByMintSegments)
+	group.GET("/ownerships/:blockchain/:contract/:tokenId/:owner", r.nftHandler.GetOwnershipBySegments)
+	group.GET("/trait-rarities", r.nftHandler.GetTraitRarities)
+	group.GET("/resolve", r.nftHandler.Resolve)
+	group.GET("/collections/:id/rarity-rankings", r.nftHandler.GetRarityRankings)
+	group.GET("/collections/:id/trait-combinations", r.nftHandler.GetTraitCombinations)
+	group.GET("/collections/:id/trait-stats", r.nftHandler.GetTraitStats)
+	group.GET("/collections/:id/openrarity", r.nftHandler.ExportOpenRarity)
+	group.POST("/collections/:id/openrarity/diff", r.nftHandler.DiffOpenRarity, middleware.BodyLimit(openRarityBodyLimit))
+	group.POST("/metadata/rarity", r.nftHandler.GetMetadataRarity, middleware.BodyLimit(metadataBodyLimit))
+	group.POST("/trait-combinations/rarity", r.nftHandler.GetTraitCombinationRarity)
+	group.GET("/items/:id/rarity", r.nftHandler.GetItemRarity)
+	group.GET("/items/:id/similar", r.nftHandler.GetSimilarItems)
+	group.GET("/items/:id/trait-rarities", r.nftHandler.GetItemTraitRarities)
 }
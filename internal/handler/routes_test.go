@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Megidy/rarible/internal/client"
+	"github.com/Megidy/rarible/internal/domain/model"
+	service "github.com/Megidy/rarible/internal/service/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_OwnershipRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockNFTService(ctrl)
+	mockService.EXPECT().GetOwnershipByID(gomock.Any(), testOwnershipID).
+		Return(&model.OwnershipDTO{ID: testOwnershipID, StatusCode: http.StatusOK}, nil).
+		Times(3)
+
+	e := echo.New()
+	NewRouter(e, NewNFTHandler(mockService)).RegisterRoutes()
+
+	paths := []string{
+		"/v1/ownerships/" + testOwnershipID,
+		"/v1/ownerships/ETHEREUM/0x06012c8cf97bead5deae237070f9587f8e7a266d/123456/0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10",
+		"/v1/ownerships/" + url.PathEscape("eip155:1/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d/123456/eip155:1:0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10"),
+	}
+	for _, path := range paths {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		require.Equal(t, http.StatusOK, rec.Code, path)
+	}
+}
+
+func TestRouter_SolanaOwnershipRoute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const solanaOwnershipID = "SOLANA:7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU:9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+	mockService := service.NewMockNFTService(ctrl)
+	mockService.EXPECT().GetOwnershipByID(gomock.Any(), solanaOwnershipID).
+		Return(&model.OwnershipDTO{ID: solanaOwnershipID, StatusCode: http.StatusOK}, nil)
+
+	e := echo.New()
+	NewRouter(e, NewNFTHandler(mockService)).RegisterRoutes()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/ownerships/SOLANA/7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU/9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", http.NoBody))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// the token id segment cannot be used for a Solana mint
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/ownerships/SOLANA/7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU/1/9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", http.NoBody))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "SOLANA items have no token id")
+}
+
+func TestRouter_AdminRoutes(t *testing.T) {
+	e := echo.New()
+	NewRouter(e, NewNFTHandler(nil), WithAdminHandler(NewAdminHandler(nil), "admin-token")).RegisterRoutes()
+
+	// fault injection is only served when the handler has an injector
+	req := httptest.NewRequest(http.MethodGet, "/admin/faults", http.NoBody)
+	req.Header.Set(adminTokenHeader, "admin-token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/upstream/keys", http.NoBody))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/upstream/keys", http.NoBody)
+	req.Header.Set(adminTokenHeader, "wrong-token")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/upstream/keys", http.NoBody)
+	req.Header.Set(adminTokenHeader, "admin-token")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRouter_AdminRoutesWithoutToken(t *testing.T) {
+	// production wires no fault injector, and without ADMIN_TOKEN the admin routes are not served at all
+	e := echo.New()
+	NewRouter(e, NewNFTHandler(nil), WithAdminHandler(NewAdminHandler(nil), "")).RegisterRoutes()
+
+	for _, path := range []string{"/admin/faults", "/admin/upstream/keys"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		require.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
+
+func TestRouter_AdminShadowRoute(t *testing.T) {
+	e := echo.New()
+	NewRouter(e, NewNFTHandler(nil), WithAdminHandler(NewAdminHandler(nil), "admin-token")).RegisterRoutes()
+
+	// mirrored mainnet bodies are never served without the admin token
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/upstream/shadow", http.NoBody))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// with the token the request reaches the handler, which has no shadow client here
+	req := httptest.NewRequest(http.MethodGet, "/admin/upstream/shadow", http.NoBody)
+	req.Header.Set(adminTokenHeader, "admin-token")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Contains(t, rec.Body.String(), "shadow")
+}
+
+func TestRouter_AdminDriftRoute(t *testing.T) {
+	e := echo.New()
+	NewRouter(e, NewNFTHandler(nil), WithAdminHandler(NewAdminHandler(nil), "admin-token")).RegisterRoutes()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/upstream/drift", http.NoBody))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// with the token the request reaches the handler, which has no drift detectors here
+	req := httptest.NewRequest(http.MethodGet, "/admin/upstream/drift", http.NoBody)
+	req.Header.Set(adminTokenHeader, "admin-token")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Contains(t, rec.Body.String(), "strict decoding")
+}
+
+func TestRouter_AdminFaultRoutes(t *testing.T) {
+	// a non-production instance wires an injector, which still needs the admin token
+	injector := client.NewFaultInjector()
+	e := echo.New()
+	NewRouter(e, NewNFTHandler(nil), WithAdminHandler(NewAdminHandler(injector), "admin-token")).RegisterRoutes()
+
+	body := `{"enabled":true,"rules":[{"dropProbability":1}]}`
+	req := httptest.NewRequest(http.MethodPut, "/admin/faults", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.False(t, injector.Config().Enabled)
+
+	req = httptest.NewRequest(http.MethodPut, "/admin/faults", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(adminTokenHeader, "admin-token")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, injector.Config().Enabled)
+}
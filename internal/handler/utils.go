@@ -1,7 +1,63 @@
 package handler
 
-import "github.com/labstack/echo/v4"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
 
+	"github.com/labstack/echo/v4"
+)
+
+// getFromParam returns the unescaped path param, so URL-encoded CAIP ids keep their slashes
 func getFromParam(ctx echo.Context, param string) string {
-	return ctx.Param(param)
+	value := ctx.Param(param)
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+// getBoolFromQuery parses an optional boolean query param, false when absent
+func getBoolFromQuery(ctx echo.Context, param string) (bool, error) {
+	raw := ctx.QueryParam(param)
+	if raw == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean", param)
+	}
+	return value, nil
+}
+
+// archiveFormField is the multipart form field an uploaded archive is read from
+const archiveFormField = "archive"
+
+// readArchive returns the uploaded archive, the archive field of a multipart form or else the whole body
+func readArchive(ctx echo.Context) ([]byte, error) {
+	var r io.Reader = ctx.Request().Body
+	if strings.HasPrefix(ctx.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		header, err := ctx.FormFile(archiveFormField)
+		if err != nil {
+			return nil, fmt.Errorf("%s file is required: %w", archiveFormField, err)
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("archive is required")
+	}
+	return data, nil
 }
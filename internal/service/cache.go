@@ -0,0 +1,74 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds a cache, the oldest entry is evicted to make room
+const maxCacheEntries = 1024
+
+// ttlCache keeps values for a fixed time. Keys should include the upstream
+// environment, see client.EnvironmentFromContext. A zero ttl disables caching.
+type ttlCache[V any] struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry[V]
+}
+
+type cacheEntry[V any] struct {
+	value    V
+	storedAt time.Time
+}
+
+func newTTLCache[V any](ttl time.Duration) *ttlCache[V] {
+	return &ttlCache[V]{ttl: ttl, entries: make(map[string]cacheEntry[V])}
+}
+
+// get returns the value stored under key and when it was stored, unless it expired
+func (c *ttlCache[V]) get(key string) (V, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.storedAt) >= c.ttl {
+		var zero V
+		return zero, time.Time{}, false
+	}
+	return entry.value, entry.storedAt, true
+}
+
+// put stores value under key and returns when it was stored
+func (c *ttlCache[V]) put(key string, value V) time.Time {
+	now := time.Now().UTC()
+	if c.ttl <= 0 {
+		return now
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCacheEntries {
+		c.evict(now)
+	}
+	c.entries[key] = cacheEntry[V]{value: value, storedAt: now}
+	return now
+}
+
+// evict drops expired entries, or the oldest one when none expired
+func (c *ttlCache[V]) evict(now time.Time) {
+	var oldest string
+	for key, entry := range c.entries {
+		if now.Sub(entry.storedAt) >= c.ttl {
+			delete(c.entries, key)
+			continue
+		}
+		if oldest == "" || entry.storedAt.Before(c.entries[oldest].storedAt) {
+			oldest = key
+		}
+	}
+	if len(c.entries) >= maxCacheEntries {
+		delete(c.entries, oldest)
+	}
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+
+	"github.com/Megidy/rarible/internal/client"
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// collectionPageSize is the largest page upstream serves
+	collectionPageSize = 1000
+	// defaultMaxCollectionItems bounds how many items are fetched to score a collection
+	defaultMaxCollectionItems = 10000
+	// maxCollectionPages bounds the listing pages fetched for one collection, leaving room
+	// for short or mostly deleted pages while still stopping a listing that never ends
+	maxCollectionPages = 100
+)
+
+// GetRarityRankings scores every item of a collection from its traits and returns a page of them, rarest first
+func (s *nftService) GetRarityRankings(ctx context.Context, req model.RarityRankingsRequestDTO) (*model.RarityRankingsResponseDTO, error) {
+	method, opts := rarityOptions(req.Method, req.MissingTraits, req.TraitCount)
+
+	expr, err := s.traitFilter(req.CollectionID, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.getCollectionItems(ctx, req.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	rarityItems := s.rarityItems(req.CollectionID, items)
+	collection := rarity.Compute(rarityItems, opts)
+	byID := make(map[string]model.ItemDTO, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	// items are ranked within the whole collection before the filter applies
+	matching := matchingItems(expr, rarityItems)
+	ranked := slices.DeleteFunc(collection.Ranked(method), func(score rarity.ItemScore) bool { return !matching[score.ID] })
+	start := min(req.Offset, len(ranked))
+	end := min(start+req.Limit, len(ranked))
+
+	resp := &model.RarityRankingsResponseDTO{
+		CollectionID: req.CollectionID,
+		Method:       string(method),
+		Total:        collection.Total(),
+		Matched:      len(ranked),
+		Items:        make([]model.ItemRarityDTO, 0, end-start),
+	}
+	for _, score := range ranked[start:end] {
+		resp.Items = append(resp.Items, itemRarity(byID[score.ID], score, collection, method))
+	}
+	return resp, nil
+}
+
+// GetItemRarity scores an item within its collection and breaks its score down by trait.
+// Rarible's own rarity of every trait is added when it can be fetched.
+func (s *nftService) GetItemRarity(ctx context.Context, req model.ItemRarityRequestDTO) (*model.ItemRarityBreakdownDTO, error) {
+	method, opts := rarityOptions(req.Method, req.MissingTraits, req.TraitCount)
+
+	item, collectionID, items, err := s.getItemWithCollection(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	collection := rarity.Compute(s.rarityItems(collectionID, items), opts)
+
+	score, _ := collection.Item(item.ID)
+	contributions, _ := collection.Contributions(item.ID, method)
+	upstream := s.upstreamRarities(ctx, collectionID, item, contributions)
+
+	resp := &model.ItemRarityBreakdownDTO{
+		CollectionID:  collectionID,
+		Method:        string(method),
+		Total:         collection.Total(),
+		ItemRarityDTO: itemRarity(*item, score, collection, method),
+		Traits:        make([]model.TraitContributionDTO, 0, len(contributions)),
+	}
+	for _, contribution := range contributions {
+		trait := model.TraitContributionDTO{
+			Key:          contribution.Trait.Key,
+			Value:        contribution.Trait.Value,
+			Count:        contribution.Count,
+			Rarity:       percent(contribution.Frequency),
+			Synthetic:    contribution.Synthetic,
+			Contribution: contribution.Value,
+			Share:        contribution.Share,
+		}
+		trait.Tier = s.rarityTiers.Tier(trait.Rarity)
+		if upstreamRarity, ok := upstream[contribution.Trait]; ok && !contribution.Synthetic {
+			trait.UpstreamRarity = &upstreamRarity
+		}
+		resp.Traits = append(resp.Traits, trait)
+	}
+	return resp, nil
+}
+
+// getItemWithCollection fetches an item and every item of its collection, the item included
+func (s *nftService) getItemWithCollection(ctx context.Context, itemID string) (*model.ItemDTO, string, []model.ItemDTO, error) {
+	item, err := s.GetItemByID(ctx, itemID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	collectionID, err := itemCollection(item)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	items, err := s.getCollectionItems(ctx, collectionID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	// the collection listing may lag behind the item itself
+	if !slices.ContainsFunc(items, func(i model.ItemDTO) bool { return i.ID == item.ID }) {
+		items = append(items, *item)
+	}
+	return item, collectionID, items, nil
+}
+
+// upstreamRarities fetches Rarible's rarity of the item's own traits. It is best effort:
+// the breakdown is complete without it, so failures are logged and an empty map returned.
+func (s *nftService) upstreamRarities(ctx context.Context, collectionID string, item *model.ItemDTO, contributions []rarity.Contribution) map[rarity.Trait]model.Rarity {
+	own := make(map[rarity.Trait]bool, len(contributions))
+	for _, contribution := range contributions {
+		if !contribution.Synthetic {
+			own[contribution.Trait] = true
+		}
+	}
+
+	// contributions hold normalised traits, Rarible is asked about the item's own spelling of them.
+	// Bucketed traits only match when their value is no number, as Rarible knows no bucket labels.
+	normalizedID := normalizerCollectionID(collectionID)
+	req := model.TraitRarityRequestDTO{CollectionID: collectionID}
+	for _, property := range traitProperties(item.Meta) {
+		if trait, ok := s.normalizeText(normalizedID, property.Key, property.Value); ok && own[trait] {
+			req.Properties = append(req.Properties, property)
+		}
+	}
+	rarities := make(map[rarity.Trait]model.Rarity, len(req.Properties))
+	if len(req.Properties) == 0 {
+		return rarities
+	}
+
+	resp, err := s.GetTraitRarity(ctx, req)
+	if err != nil {
+		log.Warn().Err(err).Str("collection", collectionID).Msg("failed to get upstream trait rarities")
+		return rarities
+	}
+	for _, trait := range resp.Traits {
+		if normalized, ok := s.normalizeText(normalizedID, trait.Key, trait.Value); ok {
+			rarities[normalized] = trait.Rarity
+		}
+	}
+	return rarities
+}
+
+// itemCollection returns the id of the collection an item belongs to, taken from its id when upstream omits it
+func itemCollection(item *model.ItemDTO) (string, error) {
+	if item.Collection != "" {
+		return item.Collection, nil
+	}
+	id, err := model.ParseItemID(item.ID)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", businesserrors.ErrSomethingWentWrong, err)
+	}
+	collectionID, ok := id.Collection()
+	if !ok {
+		return "", fmt.Errorf("%w: item %s belongs to no collection", businesserrors.ErrInvalidRequest, item.ID)
+	}
+	return collectionID.String(), nil
+}
+
+// percent turns a frequency into a rarity in percent, rounded to 2 decimals
+func percent(frequency float64) model.Rarity {
+	return model.Rarity(math.Round(frequency*100*100) / 100)
+}
+
+// rarityOptions returns the scoring method and options a request asks for, filling in the defaults
+func rarityOptions(method string, missingTraits, traitCount *bool) (rarity.Method, rarity.Options) {
+	opts := rarity.DefaultOptions()
+	if missingTraits != nil {
+		opts.IncludeMissing = *missingTraits
+	}
+	if traitCount != nil {
+		opts.IncludeTraitCount = *traitCount
+	}
+	if method == "" {
+		return rarity.MethodInformationContent, opts
+	}
+	return rarity.Method(method), opts
+}
+
+// getCollectionItems pages through every item of a collection at batch priority, in upstream order.
+// Deleted items are left out, they no longer count towards the collection's rarity.
+func (s *nftService) getCollectionItems(ctx context.Context, collectionID string) ([]model.ItemDTO, error) {
+	ctx = client.WithPriority(ctx, client.PriorityBatch)
+
+	var items []model.ItemDTO
+	seen := make(map[string]bool)
+	continuation := ""
+	for pages := 0; ; pages++ {
+		if pages == maxCollectionPages {
+			return nil, fmt.Errorf("%w: collection %s listing did not end within %d pages", businesserrors.ErrSomethingWentWrong, collectionID, maxCollectionPages)
+		}
+		page, err := s.raribleClient.GetItemsByCollection(ctx, collectionID, continuation, collectionPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get data from api: %w", err)
+		}
+		if page.StatusCode != http.StatusOK {
+			return nil, s.handleErrors(page.StatusCode, page.Message)
+		}
+
+		// deleted items count as seen so a page of fresh deletions still moves the listing on
+		added := 0
+		for _, item := range page.Items {
+			if seen[item.ID] {
+				continue
+			}
+			seen[item.ID] = true
+			added++
+			if !item.Deleted {
+				items = append(items, item)
+			}
+		}
+		if len(items) > s.maxCollectionItems {
+			return nil, fmt.Errorf("%w: collection has more than %d items", businesserrors.ErrInvalidRequest, s.maxCollectionItems)
+		}
+		// a repeated continuation or a page of items already seen would otherwise loop forever
+		if page.Continuation == "" || page.Continuation == continuation || added == 0 {
+			break
+		}
+		continuation = page.Continuation
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: collection %s has no items", businesserrors.ErrNotFound, collectionID)
+	}
+	return items, nil
+}
+
+// rarityItems returns the normalised traits of every item of a collection, items without metadata have none
+func (s *nftService) rarityItems(collectionID string, items []model.ItemDTO) []rarity.Item {
+	collectionID = normalizerCollectionID(collectionID)
+	result := make([]rarity.Item, 0, len(items))
+	for _, item := range items {
+		var traits []rarity.Trait
+		if item.Meta != nil {
+			traits = make([]rarity.Trait, 0, len(item.Meta.Attributes))
+			for _, attribute := range item.Meta.Attributes {
+				traits = append(traits, rarity.Trait{Key: attribute.Key, Value: attribute.Value})
+			}
+		}
+		result = append(result, rarity.Item{ID: item.ID, Traits: s.normalizer.Normalize(collectionID, traits)})
+	}
+	return result
+}
+
+func itemRarity(item model.ItemDTO, score rarity.ItemScore, collection *rarity.Collection, method rarity.Method) model.ItemRarityDTO {
+	dto := model.ItemRarityDTO{
+		ItemID:     score.ID,
+		TokenID:    item.TokenID,
+		Rank:       score.Ranks[method],
+		Percentile: collection.Percentile(score.Ranks[method]),
+		Score:      score.Scores.Get(method),
+		Scores: model.RarityScoresDTO{
+			InformationContent: score.Scores.InformationContent,
+			RarityScore:        score.Scores.RarityScore,
+			Statistical:        score.Scores.Statistical,
+		},
+		Ranks: model.RarityRanksDTO{
+			InformationContent: score.Ranks[rarity.MethodInformationContent],
+			RarityScore:        score.Ranks[rarity.MethodRarityScore],
+			Statistical:        score.Ranks[rarity.MethodStatistical],
+		},
+	}
+	if item.Meta != nil {
+		dto.Name = item.Meta.Name
+	}
+	return dto
+}
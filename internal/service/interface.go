@@ -8,5 +8,17 @@ import (
 
 type NFTService interface {
 	GetOwnershipByID(ctx context.Context, id string) (*model.OwnershipDTO, error)
+	GetItemByID(ctx context.Context, id string) (*model.ItemDTO, error)
 	GetTraitRarity(ctx context.Context, req model.TraitRarityRequestDTO) (*model.TraitRarityResponseDTO, error)
+	GetItemTraitRarity(ctx context.Context, req model.ItemTraitRarityRequestDTO) (*model.ItemTraitRarityResponseDTO, error)
+	GetItemRarity(ctx context.Context, req model.ItemRarityRequestDTO) (*model.ItemRarityBreakdownDTO, error)
+	GetSimilarItems(ctx context.Context, req model.SimilarItemsRequestDTO) (*model.SimilarItemsResponseDTO, error)
+	GetTraitCombinations(ctx context.Context, req model.TraitCombinationsRequestDTO) (*model.TraitCombinationsResponseDTO, error)
+	GetTraitCombinationRarity(ctx context.Context, req model.TraitRarityRequestDTO) (*model.TraitCombinationRarityDTO, error)
+	GetTraitStats(ctx context.Context, req model.TraitStatsRequestDTO) (*model.TraitStatsResponseDTO, error)
+	GetRarityRankings(ctx context.Context, req model.RarityRankingsRequestDTO) (*model.RarityRankingsResponseDTO, error)
+	GetMetadataRarity(ctx context.Context, req model.MetadataRarityRequestDTO) (*model.MetadataRarityResponseDTO, error)
+	ExportOpenRarity(ctx context.Context, collectionID string) (*model.OpenRarityRankingsDTO, error)
+	DiffOpenRarity(ctx context.Context, req model.OpenRarityDiffRequestDTO) (*model.OpenRarityDiffDTO, error)
+	Resolve(ctx context.Context, req model.ResolveRequestDTO) (*model.ResolveResponseDTO, error)
 }
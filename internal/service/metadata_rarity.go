@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/Megidy/rarible/internal/domain/metadata"
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+)
+
+// GetMetadataRarity ranks the tokens of an archive of metadata files and describes their traits.
+// Nothing is fetched upstream, so collections can be ranked before they are revealed on chain.
+func (s *nftService) GetMetadataRarity(_ context.Context, req model.MetadataRarityRequestDTO) (*model.MetadataRarityResponseDTO, error) {
+	method, opts := rarityOptions(req.Method, req.MissingTraits, req.TraitCount)
+
+	archive, err := metadata.ReadArchive(req.Archive, metadata.DefaultLimits(s.maxCollectionItems))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", businesserrors.ErrInvalidRequest, err)
+	}
+
+	collectionID := ""
+	if req.CollectionID != "" {
+		collectionID = normalizerCollectionID(req.CollectionID)
+	}
+	items := make([]rarity.Item, 0, len(archive.Tokens))
+	tokens := make(map[string]model.ItemDTO, len(archive.Tokens))
+	for _, token := range archive.Tokens {
+		items = append(items, rarity.Item{ID: token.ID, Traits: s.normalizer.Normalize(collectionID, token.Traits)})
+		tokens[token.ID] = model.ItemDTO{TokenID: token.ID, Meta: &model.ItemMetaDTO{Name: token.Name}}
+	}
+	collection := rarity.Compute(items, opts)
+
+	ranked := collection.Ranked(method)
+	resp := &model.MetadataRarityResponseDTO{
+		Method:     string(method),
+		Total:      collection.Total(),
+		Tokens:     make([]model.ItemRarityDTO, 0, len(ranked)),
+		TraitStats: *s.traitStatsResponse(req.CollectionID, collection.Stats(), time.Now().UTC(), req.LongTail),
+		Skipped:    make([]model.SkippedFileDTO, 0, len(archive.Skipped)),
+	}
+	for _, score := range ranked {
+		resp.Tokens = append(resp.Tokens, itemRarity(tokens[score.ID], score, collection, method))
+	}
+	for _, skipped := range archive.Skipped {
+		resp.Skipped = append(resp.Skipped, model.SkippedFileDTO{File: skipped.File, Reason: skipped.Reason})
+	}
+	return resp, nil
+}
@@ -35,6 +35,96 @@ func (m *MockNFTService) EXPECT() *MockNFTServiceMockRecorder {
 	return m.recorder
 }
 
+// DiffOpenRarity mocks base method.
+func (m *MockNFTService) DiffOpenRarity(ctx context.Context, req model.OpenRarityDiffRequestDTO) (*model.OpenRarityDiffDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffOpenRarity", ctx, req)
+	ret0, _ := ret[0].(*model.OpenRarityDiffDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffOpenRarity indicates an expected call of DiffOpenRarity.
+func (mr *MockNFTServiceMockRecorder) DiffOpenRarity(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffOpenRarity", reflect.TypeOf((*MockNFTService)(nil).DiffOpenRarity), ctx, req)
+}
+
+// ExportOpenRarity mocks base method.
+func (m *MockNFTService) ExportOpenRarity(ctx context.Context, collectionID string) (*model.OpenRarityRankingsDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportOpenRarity", ctx, collectionID)
+	ret0, _ := ret[0].(*model.OpenRarityRankingsDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportOpenRarity indicates an expected call of ExportOpenRarity.
+func (mr *MockNFTServiceMockRecorder) ExportOpenRarity(ctx, collectionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportOpenRarity", reflect.TypeOf((*MockNFTService)(nil).ExportOpenRarity), ctx, collectionID)
+}
+
+// GetItemByID mocks base method.
+func (m *MockNFTService) GetItemByID(ctx context.Context, id string) (*model.ItemDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemByID", ctx, id)
+	ret0, _ := ret[0].(*model.ItemDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemByID indicates an expected call of GetItemByID.
+func (mr *MockNFTServiceMockRecorder) GetItemByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByID", reflect.TypeOf((*MockNFTService)(nil).GetItemByID), ctx, id)
+}
+
+// GetItemRarity mocks base method.
+func (m *MockNFTService) GetItemRarity(ctx context.Context, req model.ItemRarityRequestDTO) (*model.ItemRarityBreakdownDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemRarity", ctx, req)
+	ret0, _ := ret[0].(*model.ItemRarityBreakdownDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemRarity indicates an expected call of GetItemRarity.
+func (mr *MockNFTServiceMockRecorder) GetItemRarity(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemRarity", reflect.TypeOf((*MockNFTService)(nil).GetItemRarity), ctx, req)
+}
+
+// GetItemTraitRarity mocks base method.
+func (m *MockNFTService) GetItemTraitRarity(ctx context.Context, req model.ItemTraitRarityRequestDTO) (*model.ItemTraitRarityResponseDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemTraitRarity", ctx, req)
+	ret0, _ := ret[0].(*model.ItemTraitRarityResponseDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemTraitRarity indicates an expected call of GetItemTraitRarity.
+func (mr *MockNFTServiceMockRecorder) GetItemTraitRarity(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemTraitRarity", reflect.TypeOf((*MockNFTService)(nil).GetItemTraitRarity), ctx, req)
+}
+
+// GetMetadataRarity mocks base method.
+func (m *MockNFTService) GetMetadataRarity(ctx context.Context, req model.MetadataRarityRequestDTO) (*model.MetadataRarityResponseDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetadataRarity", ctx, req)
+	ret0, _ := ret[0].(*model.MetadataRarityResponseDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetadataRarity indicates an expected call of GetMetadataRarity.
+func (mr *MockNFTServiceMockRecorder) GetMetadataRarity(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetadataRarity", reflect.TypeOf((*MockNFTService)(nil).GetMetadataRarity), ctx, req)
+}
+
 // GetOwnershipByID mocks base method.
 func (m *MockNFTService) GetOwnershipByID(ctx context.Context, id string) (*model.OwnershipDTO, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +140,66 @@ func (mr *MockNFTServiceMockRecorder) GetOwnershipByID(ctx, id interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwnershipByID", reflect.TypeOf((*MockNFTService)(nil).GetOwnershipByID), ctx, id)
 }
 
+// GetRarityRankings mocks base method.
+func (m *MockNFTService) GetRarityRankings(ctx context.Context, req model.RarityRankingsRequestDTO) (*model.RarityRankingsResponseDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRarityRankings", ctx, req)
+	ret0, _ := ret[0].(*model.RarityRankingsResponseDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRarityRankings indicates an expected call of GetRarityRankings.
+func (mr *MockNFTServiceMockRecorder) GetRarityRankings(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRarityRankings", reflect.TypeOf((*MockNFTService)(nil).GetRarityRankings), ctx, req)
+}
+
+// GetSimilarItems mocks base method.
+func (m *MockNFTService) GetSimilarItems(ctx context.Context, req model.SimilarItemsRequestDTO) (*model.SimilarItemsResponseDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSimilarItems", ctx, req)
+	ret0, _ := ret[0].(*model.SimilarItemsResponseDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSimilarItems indicates an expected call of GetSimilarItems.
+func (mr *MockNFTServiceMockRecorder) GetSimilarItems(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimilarItems", reflect.TypeOf((*MockNFTService)(nil).GetSimilarItems), ctx, req)
+}
+
+// GetTraitCombinationRarity mocks base method.
+func (m *MockNFTService) GetTraitCombinationRarity(ctx context.Context, req model.TraitRarityRequestDTO) (*model.TraitCombinationRarityDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTraitCombinationRarity", ctx, req)
+	ret0, _ := ret[0].(*model.TraitCombinationRarityDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTraitCombinationRarity indicates an expected call of GetTraitCombinationRarity.
+func (mr *MockNFTServiceMockRecorder) GetTraitCombinationRarity(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTraitCombinationRarity", reflect.TypeOf((*MockNFTService)(nil).GetTraitCombinationRarity), ctx, req)
+}
+
+// GetTraitCombinations mocks base method.
+func (m *MockNFTService) GetTraitCombinations(ctx context.Context, req model.TraitCombinationsRequestDTO) (*model.TraitCombinationsResponseDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTraitCombinations", ctx, req)
+	ret0, _ := ret[0].(*model.TraitCombinationsResponseDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTraitCombinations indicates an expected call of GetTraitCombinations.
+func (mr *MockNFTServiceMockRecorder) GetTraitCombinations(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTraitCombinations", reflect.TypeOf((*MockNFTService)(nil).GetTraitCombinations), ctx, req)
+}
+
 // GetTraitRarity mocks base method.
 func (m *MockNFTService) GetTraitRarity(ctx context.Context, req model.TraitRarityRequestDTO) (*model.TraitRarityResponseDTO, error) {
 	m.ctrl.T.Helper()
@@ -64,3 +214,33 @@ func (mr *MockNFTServiceMockRecorder) GetTraitRarity(ctx, req interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTraitRarity", reflect.TypeOf((*MockNFTService)(nil).GetTraitRarity), ctx, req)
 }
+
+// GetTraitStats mocks base method.
+func (m *MockNFTService) GetTraitStats(ctx context.Context, req model.TraitStatsRequestDTO) (*model.TraitStatsResponseDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTraitStats", ctx, req)
+	ret0, _ := ret[0].(*model.TraitStatsResponseDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTraitStats indicates an expected call of GetTraitStats.
+func (mr *MockNFTServiceMockRecorder) GetTraitStats(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTraitStats", reflect.TypeOf((*MockNFTService)(nil).GetTraitStats), ctx, req)
+}
+
+// Resolve mocks base method.
+func (m *MockNFTService) Resolve(ctx context.Context, req model.ResolveRequestDTO) (*model.ResolveResponseDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, req)
+	ret0, _ := ret[0].(*model.ResolveResponseDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockNFTServiceMockRecorder) Resolve(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockNFTService)(nil).Resolve), ctx, req)
+}
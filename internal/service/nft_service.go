@@ -8,16 +8,33 @@ import (
 	"github.com/Megidy/rarible/internal/client"
 	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
 	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+	"github.com/Megidy/rarible/internal/domain/resolver"
 )
 
 type nftService struct {
 	raribleClient client.RaribleClient
+	resolver      *resolver.Registry
+	rarityTiers   model.RarityTiers
+
+	maxCollectionItems int
+	normalizer         *rarity.Normalizer
+	traitStats         *ttlCache[rarity.Stats]
 }
 
-func NewNFTService(raribleClient client.RaribleClient) NFTService {
-	return &nftService{
+func NewNFTService(raribleClient client.RaribleClient, opts ...Option) NFTService {
+	s := &nftService{
 		raribleClient: raribleClient,
+		resolver:      resolver.DefaultRegistry(),
+		rarityTiers:   model.DefaultRarityTiers(),
+
+		maxCollectionItems: defaultMaxCollectionItems,
+		traitStats:         newTTLCache[rarity.Stats](defaultTraitStatsCacheTTL),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 func (s *nftService) GetOwnershipByID(ctx context.Context, id string) (*model.OwnershipDTO, error) {
@@ -30,10 +47,26 @@ func (s *nftService) GetOwnershipByID(ctx context.Context, id string) (*model.Ow
 	}
 
 	// if status code is valid, returning value
+	minted := ownership.Minted()
+	ownership.MintedValue = &minted
 	return ownership, nil
 }
 
+func (s *nftService) GetItemByID(ctx context.Context, id string) (*model.ItemDTO, error) {
+	item, err := s.raribleClient.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data from api: %w", err)
+	}
+	if item.StatusCode != http.StatusOK {
+		return nil, s.handleErrors(item.StatusCode, item.Message)
+	}
+	minted := item.Minted()
+	item.MintedSupply = &minted
+	return item, nil
+}
+
 func (s *nftService) GetTraitRarity(ctx context.Context, req model.TraitRarityRequestDTO) (*model.TraitRarityResponseDTO, error) {
+	req.Properties = s.upstreamProperties(req.CollectionID, req.Properties)
 	resp, err := s.raribleClient.GetTraitRarity(ctx, &req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get data from api: %w", err)
@@ -41,9 +74,131 @@ func (s *nftService) GetTraitRarity(ctx context.Context, req model.TraitRarityRe
 	if resp.StatusCode != http.StatusOK {
 		return nil, s.handleErrors(resp.StatusCode, resp.Message)
 	}
+
+	// ranks are relative to every requested trait, so they are computed before filtering
+	rankTraits(resp.Traits, s.rarityTiers)
+	resp.Traits = filterTraits(resp.Traits, req)
+	sortTraits(resp.Traits, req.Sort, req.Order)
+	return resp, nil
+}
+
+// GetItemTraitRarity annotates the attributes of an item with their rarity within its collection.
+// Items without attributes or with unrevealed metadata are returned without traits and without calling upstream.
+func (s *nftService) GetItemTraitRarity(ctx context.Context, req model.ItemTraitRarityRequestDTO) (*model.ItemTraitRarityResponseDTO, error) {
+	item, err := s.GetItemByID(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	collectionID, err := itemCollection(item)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.ItemTraitRarityResponseDTO{
+		ItemID:       item.ID,
+		CollectionID: collectionID,
+		Status:       model.ItemTraitsStatusOK,
+		Traits:       []model.ExtendedTraitProperty{},
+	}
+	if item.Meta != nil {
+		resp.Name = item.Meta.Name
+	}
+
+	properties := traitProperties(item.Meta)
+	switch {
+	case item.Meta == nil || isUnrevealed(item.Meta):
+		resp.Status = model.ItemTraitsStatusUnrevealed
+		return resp, nil
+	case len(properties) == 0:
+		resp.Status = model.ItemTraitsStatusNoAttributes
+		return resp, nil
+	}
+
+	traitRarity, err := s.GetTraitRarity(ctx, model.TraitRarityRequestDTO{
+		CollectionID:       collectionID,
+		Properties:         properties,
+		TraitRarityOptions: req.TraitRarityOptions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Traits = traitRarity.Traits
+	return resp, nil
+}
+
+// Resolve turns a marketplace url, CAIP-19 asset id or Rarible id into normalised identifiers,
+// fetching the ownership, or the item when there is no owner, if req.Include is set
+func (s *nftService) Resolve(ctx context.Context, req model.ResolveRequestDTO) (*model.ResolveResponseDTO, error) {
+	ids, err := s.resolver.Resolve(req.Input)
+	if err != nil {
+		return nil, err
+	}
+	if req.Owner != "" {
+		ids, err = ids.WithOwner(req.Owner)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &model.ResolveResponseDTO{
+		Input:      req.Input,
+		Source:     ids.Source,
+		Blockchain: string(ids.Blockchain),
+	}
+	if ids.Collection != nil {
+		resp.CollectionID = ids.Collection.String()
+	}
+	if ids.Item != nil {
+		resp.ItemID = ids.Item.String()
+	}
+	if ids.Ownership != nil {
+		resp.OwnershipID = ids.Ownership.String()
+	}
+	if ids.Owner != nil {
+		resp.Owner = ids.Owner.String()
+	}
+	if req.CAIP {
+		resp.CAIP = caipIDs(ids)
+	}
+
+	if !req.Include {
+		return resp, nil
+	}
+	switch {
+	case resp.OwnershipID != "":
+		resp.Ownership, err = s.GetOwnershipByID(ctx, resp.OwnershipID)
+	case resp.ItemID != "":
+		resp.Item, err = s.GetItemByID(ctx, resp.ItemID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if req.CAIP && resp.Ownership != nil {
+		resp.Ownership.CAIP = resp.CAIP
+	}
+	if req.CAIP && resp.Item != nil {
+		resp.Item.CAIP = resp.CAIP
+	}
 	return resp, nil
 }
 
+// caipIDs returns the CAIP counterparts of the most specific resolved id
+func caipIDs(ids resolver.Identifiers) *model.CAIPIDsDTO {
+	switch {
+	case ids.Ownership != nil:
+		return ids.Ownership.CAIPIDs()
+	case ids.Item != nil:
+		return ids.Item.CAIPIDs()
+	case ids.Collection != nil:
+		caip := ids.Collection.CAIPIDs()
+		if ids.Owner != nil && caip != nil {
+			caip.Owner, _ = ids.Owner.CAIP10()
+		}
+		return caip
+	}
+	return nil
+}
+
 // handleErrors function that maps API statuses to business errors for consistent error handling
 func (s *nftService) handleErrors(statusCode int, message string) error {
 	switch statusCode {
@@ -1,14 +1,21 @@
 package service
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"testing"
 	"time"
 
+	raribleclient "github.com/Megidy/rarible/internal/client"
 	client "github.com/Megidy/rarible/internal/client/mock"
 	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
 	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
@@ -18,6 +25,41 @@ const (
 	id      = "id-123"
 )
 
+// collectionItem returns token tokenID of collectionID named "#tokenID", with key, value
+// pairs as its attributes. Pairs without a value are left out.
+func collectionItem(collectionID, tokenID string, pairs ...string) model.ItemDTO {
+	meta := &model.ItemMetaDTO{Name: "#" + tokenID, Attributes: []model.MetaAttributeDTO{}}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			meta.Attributes = append(meta.Attributes, model.MetaAttributeDTO{Key: pairs[i], Value: pairs[i+1]})
+		}
+	}
+	return model.ItemDTO{ID: collectionID + ":" + tokenID, TokenID: tokenID, Meta: meta}
+}
+
+// mockCollection returns an upstream listing pages as the items of collectionID, every page
+// but the last continued by the index of the next one. Listings must be at batch priority.
+func mockCollection(t *testing.T, collectionID string, pages ...[]model.ItemDTO) *client.MockRaribleClient {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	raribleClient := client.NewMockRaribleClient(ctrl)
+	raribleClient.EXPECT().GetItemsByCollection(gomock.Any(), collectionID, gomock.Any(), collectionPageSize).
+		DoAndReturn(func(ctx context.Context, _, continuation string, _ int) (*model.ItemsDTO, error) {
+			require.Equal(t, raribleclient.PriorityBatch, raribleclient.PriorityFromContext(ctx))
+			page := 0
+			if continuation != "" {
+				page, _ = strconv.Atoi(continuation)
+			}
+			resp := &model.ItemsDTO{Items: pages[page], StatusCode: http.StatusOK}
+			if page+1 < len(pages) {
+				resp.Continuation = strconv.Itoa(page + 1)
+			}
+			return resp, nil
+		}).AnyTimes()
+	return raribleClient
+}
+
 func TestGetOwnershipByID(t *testing.T) {
 	t.Run("ShouldReturnValidValue", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -45,6 +87,52 @@ func TestGetOwnershipByID(t *testing.T) {
 
 		require.Equal(t, expectedOwnership, actualOwnership)
 	})
+	t.Run("ShouldComputeMintedValue", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ownership := &model.OwnershipDTO{
+			ID:         id,
+			Value:      model.MustParseQuantity("100000000000000000000"),
+			LazyValue:  model.NewQuantity(40),
+			StatusCode: http.StatusOK,
+		}
+
+		client := client.NewMockRaribleClient(ctrl)
+		client.EXPECT().GetOwnershipByID(gomock.Any(), id).Return(ownership, nil)
+
+		service := NewNFTService(client)
+
+		resp, err := service.GetOwnershipByID(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, "99999999999999999960", resp.MintedValue.String())
+	})
+	t.Run("ShouldReturnZeroMintedValueOfLazyOwnership", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ownership := &model.OwnershipDTO{
+			ID:         id,
+			Value:      model.NewQuantity(3),
+			LazyValue:  model.NewQuantity(3),
+			StatusCode: http.StatusOK,
+		}
+		// upstream never sends the computed value
+		data, err := json.Marshal(ownership)
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "mintedValue")
+
+		client := client.NewMockRaribleClient(ctrl)
+		client.EXPECT().GetOwnershipByID(gomock.Any(), id).Return(ownership, nil)
+
+		service := NewNFTService(client)
+
+		resp, err := service.GetOwnershipByID(context.Background(), id)
+		require.NoError(t, err)
+		data, err = json.Marshal(resp)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"mintedValue":"0"`)
+	})
 	t.Run("ShouldReturnError", func(t *testing.T) {
 		t.Run("ShouldReturn400", func(t *testing.T) {
 			ctrl := gomock.NewController(t)
@@ -133,7 +221,7 @@ func TestGetTraitRarity(t *testing.T) {
 		mockResponse := &model.TraitRarityResponseDTO{
 			Continuation: "token123",
 			Traits: []model.ExtendedTraitProperty{
-				{Key: "Hat", Value: "Halo", Rarity: "1.2"},
+				{Key: "Hat", Value: "Halo", Rarity: 1.2},
 			},
 			StatusCode: http.StatusOK,
 		}
@@ -249,3 +337,992 @@ func TestGetTraitRarity(t *testing.T) {
 		})
 	})
 }
+
+func TestResolve(t *testing.T) {
+	const (
+		openseaURL  = "https://opensea.io/assets/ethereum/0x06012C8cf97BEaD5deAe237070F9587f8E7A266d/123456"
+		itemID      = "ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d:123456"
+		owner       = "0x4b7a2cde2d4a9d1e2e1b7e0f7a5c7d1f4e8b9a10"
+		ownershipID = itemID + ":" + owner
+	)
+
+	t.Run("ShouldReturnIdentifiersWithoutCallingApi", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		service := NewNFTService(client.NewMockRaribleClient(ctrl))
+
+		resp, err := service.Resolve(ctx, model.ResolveRequestDTO{Input: openseaURL})
+		require.NoError(t, err)
+		require.Equal(t, "opensea", resp.Source)
+		require.Equal(t, "ETHEREUM", resp.Blockchain)
+		require.Equal(t, "ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d", resp.CollectionID)
+		require.Equal(t, itemID, resp.ItemID)
+		require.Empty(t, resp.OwnershipID)
+		require.Nil(t, resp.Item)
+	})
+
+	t.Run("ShouldIncludeItem", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		item := &model.ItemDTO{ID: itemID, StatusCode: http.StatusOK}
+		client := client.NewMockRaribleClient(ctrl)
+		client.EXPECT().GetItemByID(gomock.Any(), itemID).Return(item, nil)
+
+		service := NewNFTService(client)
+
+		resp, err := service.Resolve(ctx, model.ResolveRequestDTO{Input: openseaURL, Include: true})
+		require.NoError(t, err)
+		require.Equal(t, item, resp.Item)
+		require.Nil(t, resp.Ownership)
+	})
+
+	t.Run("ShouldIncludeOwnershipWhenOwnerGiven", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		ownership := &model.OwnershipDTO{ID: ownershipID, StatusCode: http.StatusOK}
+		client := client.NewMockRaribleClient(ctrl)
+		client.EXPECT().GetOwnershipByID(gomock.Any(), ownershipID).Return(ownership, nil)
+
+		service := NewNFTService(client)
+
+		resp, err := service.Resolve(ctx, model.ResolveRequestDTO{Input: openseaURL, Owner: owner, Include: true})
+		require.NoError(t, err)
+		require.Equal(t, ownershipID, resp.OwnershipID)
+		require.Equal(t, ownership, resp.Ownership)
+	})
+
+	t.Run("ShouldIncludeCAIPIDs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		item := &model.ItemDTO{ID: itemID, StatusCode: http.StatusOK}
+		client := client.NewMockRaribleClient(ctrl)
+		client.EXPECT().GetItemByID(gomock.Any(), itemID).Return(item, nil)
+
+		service := NewNFTService(client)
+
+		resp, err := service.Resolve(ctx, model.ResolveRequestDTO{Input: openseaURL, Include: true, CAIP: true})
+		require.NoError(t, err)
+		require.Equal(t, "eip155:1/erc721:0x06012c8cf97bead5deae237070f9587f8e7a266d/123456", resp.CAIP.Asset)
+		require.Equal(t, resp.CAIP, resp.Item.CAIP)
+	})
+
+	t.Run("ShouldReturnHolderOfCollection", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewNFTService(client.NewMockRaribleClient(ctrl))
+
+		resp, err := service.Resolve(context.Background(), model.ResolveRequestDTO{
+			Input:   "https://etherscan.io/token/0x06012c8cf97bead5deae237070f9587f8e7a266d?a=" + owner,
+			Include: true,
+			CAIP:    true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "ETHEREUM:0x06012c8cf97bead5deae237070f9587f8e7a266d", resp.CollectionID)
+		require.Equal(t, "ETHEREUM:"+owner, resp.Owner)
+		require.Empty(t, resp.OwnershipID)
+		require.Equal(t, "eip155:1:"+owner, resp.CAIP.Owner)
+	})
+
+	t.Run("ShouldReturnError", func(t *testing.T) {
+		t.Run("ShouldReturn400ForUnknownInput", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewNFTService(client.NewMockRaribleClient(ctrl))
+
+			_, err := service.Resolve(context.Background(), model.ResolveRequestDTO{Input: "https://example.com/nft/1"})
+			require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+		})
+
+		t.Run("ShouldReturn404ForMissingItem", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := client.NewMockRaribleClient(ctrl)
+			client.EXPECT().GetItemByID(gomock.Any(), itemID).Return(&model.ItemDTO{StatusCode: http.StatusNotFound}, nil)
+
+			service := NewNFTService(client)
+
+			_, err := service.Resolve(context.Background(), model.ResolveRequestDTO{Input: openseaURL, Include: true})
+			require.ErrorIs(t, err, businesserrors.ErrNotFound)
+		})
+	})
+}
+
+func TestGetTraitRarity_Enrichment(t *testing.T) {
+	traits := func() []model.ExtendedTraitProperty {
+		return []model.ExtendedTraitProperty{
+			{Key: "Hat", Value: "Cap", Rarity: 30},
+			{Key: "Eyes", Value: "Laser", Rarity: 0.5},
+			{Key: "Background", Value: "Gold", Rarity: 4},
+			{Key: "Mouth", Value: "Grin", Rarity: 4},
+		}
+	}
+	request := model.TraitRarityRequestDTO{CollectionID: "ETHEREUM:0x123"}
+
+	getTraitRarity := func(t *testing.T, req model.TraitRarityRequestDTO, opts ...Option) []model.ExtendedTraitProperty {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := client.NewMockRaribleClient(ctrl)
+		client.EXPECT().GetTraitRarity(gomock.Any(), gomock.Any()).
+			Return(&model.TraitRarityResponseDTO{Traits: traits(), StatusCode: http.StatusOK}, nil)
+
+		resp, err := NewNFTService(client, opts...).GetTraitRarity(context.Background(), req)
+		require.NoError(t, err)
+		return resp.Traits
+	}
+
+	t.Run("ShouldRankWithinRequest", func(t *testing.T) {
+		resp := getTraitRarity(t, request)
+
+		require.Equal(t, []int{4, 1, 2, 2}, []int{resp[0].Rank, resp[1].Rank, resp[2].Rank, resp[3].Rank})
+		require.Equal(t, []float64{25, 100, 75, 75}, []float64{resp[0].Percentile, resp[1].Percentile, resp[2].Percentile, resp[3].Percentile})
+		require.Equal(t, []string{model.TierUncommon, model.TierLegendary, model.TierEpic, model.TierEpic},
+			[]string{resp[0].Tier, resp[1].Tier, resp[2].Tier, resp[3].Tier})
+	})
+
+	t.Run("ShouldUseConfiguredTiers", func(t *testing.T) {
+		resp := getTraitRarity(t, request, WithRarityTiers(model.RarityTiers{Legendary: 0.1, Epic: 0.2, Rare: 50, Uncommon: 60}))
+		require.Equal(t, model.TierRare, resp[0].Tier)
+		require.Equal(t, model.TierRare, resp[1].Tier)
+	})
+
+	t.Run("ShouldSortAndFilter", func(t *testing.T) {
+		req := request
+		req.Sort = model.TraitSortRarity
+		resp := getTraitRarity(t, req)
+		require.Equal(t, []string{"Eyes", "Background", "Mouth", "Hat"}, []string{resp[0].Key, resp[1].Key, resp[2].Key, resp[3].Key})
+
+		req.Order = model.OrderDesc
+		resp = getTraitRarity(t, req)
+		require.Equal(t, []string{"Hat", "Background", "Mouth", "Eyes"}, []string{resp[0].Key, resp[1].Key, resp[2].Key, resp[3].Key})
+
+		req = request
+		req.Sort = model.TraitSortKey
+		minRarity, maxRarity := 1.0, 10.0
+		req.MinRarity, req.MaxRarity = &minRarity, &maxRarity
+		resp = getTraitRarity(t, req)
+		require.Len(t, resp, 2)
+		require.Equal(t, "Background", resp[0].Key)
+		require.Equal(t, 2, resp[0].Rank)
+
+		req = request
+		req.Tiers = []string{model.TierLegendary, model.TierUncommon}
+		resp = getTraitRarity(t, req)
+		require.Equal(t, []string{"Hat", "Eyes"}, []string{resp[0].Key, resp[1].Key})
+	})
+}
+
+func TestGetRarityRankings(t *testing.T) {
+	const collectionID = "ETHEREUM:0x123"
+	request := model.RarityRankingsRequestDTO{CollectionID: collectionID, Limit: 10}
+
+	newService := func(t *testing.T, opts ...Option) NFTService {
+		return NewNFTService(mockCollection(t, collectionID,
+			[]model.ItemDTO{
+				collectionItem(collectionID, "1", "Eyes", "Blue", "Hat", "Cap"),
+				collectionItem(collectionID, "2", "Eyes", "Blue", "Hat", "Cap"),
+			},
+			[]model.ItemDTO{
+				collectionItem(collectionID, "3", "Eyes", "Blue", "Hat", "Crown"),
+				collectionItem(collectionID, "4", "Eyes", "Blue"),
+				{ID: collectionID + ":5", Deleted: true},
+			},
+		), opts...)
+	}
+
+	t.Run("ShouldRankEveryPageRarestFirst", func(t *testing.T) {
+		resp, err := newService(t).GetRarityRankings(context.Background(), request)
+		require.NoError(t, err)
+
+		require.Equal(t, "information_content", resp.Method)
+		require.Equal(t, 4, resp.Total)
+		require.Len(t, resp.Items, 4)
+		require.Equal(t, "ETHEREUM:0x123:4", resp.Items[0].ItemID)
+		require.Equal(t, "#4", resp.Items[0].Name)
+		require.Equal(t, 1, resp.Items[0].Rank)
+		require.Equal(t, 100.0, resp.Items[0].Percentile)
+		require.Equal(t, resp.Items[0].Scores.InformationContent, resp.Items[0].Score)
+		require.Equal(t, []int{1, 2, 3, 3}, []int{resp.Items[0].Rank, resp.Items[1].Rank, resp.Items[2].Rank, resp.Items[3].Rank})
+	})
+
+	t.Run("ShouldPageAndHonourOptions", func(t *testing.T) {
+		req := request
+		req.Method = "rarity_score"
+		req.Offset, req.Limit = 1, 1
+		missingTraits := false
+		req.MissingTraits = &missingTraits
+
+		resp, err := newService(t).GetRarityRankings(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, 4, resp.Total)
+		require.Len(t, resp.Items, 1)
+		// without scoring the missing hat, item 3's crown outranks item 4's trait count
+		require.Equal(t, "ETHEREUM:0x123:4", resp.Items[0].ItemID)
+		require.Equal(t, 2, resp.Items[0].Ranks.RarityScore)
+	})
+
+	t.Run("ShouldFilterAfterRanking", func(t *testing.T) {
+		req := request
+		req.Filter = `Hat IN (Cap, Crown) AND NOT Hat = Cap`
+
+		resp, err := newService(t).GetRarityRankings(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, 4, resp.Total)
+		require.Equal(t, 1, resp.Matched)
+		require.Len(t, resp.Items, 1)
+		require.Equal(t, "ETHEREUM:0x123:3", resp.Items[0].ItemID)
+		require.Equal(t, 2, resp.Items[0].Rank)
+	})
+
+	t.Run("ShouldRejectInvalidFilter", func(t *testing.T) {
+		req := request
+		req.Filter = `Hat =`
+
+		_, err := newService(t).GetRarityRankings(context.Background(), req)
+		require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+	})
+
+	t.Run("ShouldRejectTooLargeCollection", func(t *testing.T) {
+		_, err := newService(t, WithMaxCollectionItems(3)).GetRarityRankings(context.Background(), request)
+		require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+	})
+
+	t.Run("ShouldReturn404", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemsByCollection(gomock.Any(), gomock.Any(), "", gomock.Any()).
+			Return(&model.ItemsDTO{StatusCode: http.StatusOK}, nil)
+
+		_, err := NewNFTService(raribleClient).GetRarityRankings(context.Background(), request)
+		require.ErrorIs(t, err, businesserrors.ErrNotFound)
+	})
+
+	t.Run("ShouldStopOnRepeatedContinuation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		calls := 0
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemsByCollection(gomock.Any(), collectionID, gomock.Any(), collectionPageSize).
+			DoAndReturn(func(_ context.Context, _, _ string, _ int) (*model.ItemsDTO, error) {
+				calls++
+				return &model.ItemsDTO{
+					Items:        []model.ItemDTO{collectionItem(collectionID, strconv.Itoa(calls), "Eyes", "Blue")},
+					Continuation: "next",
+					StatusCode:   http.StatusOK,
+				}, nil
+			}).Times(2)
+
+		resp, err := NewNFTService(raribleClient).GetRarityRankings(context.Background(), request)
+		require.NoError(t, err)
+		require.Equal(t, 2, resp.Total)
+	})
+
+	t.Run("ShouldStopWhenPageAddsNoItems", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// the same items come back under an ever new continuation
+		calls := 0
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemsByCollection(gomock.Any(), collectionID, gomock.Any(), collectionPageSize).
+			DoAndReturn(func(_ context.Context, _, _ string, _ int) (*model.ItemsDTO, error) {
+				calls++
+				return &model.ItemsDTO{
+					Items: []model.ItemDTO{
+						collectionItem(collectionID, "1", "Eyes", "Blue"),
+						{ID: collectionID + ":2", Deleted: true},
+					},
+					Continuation: strconv.Itoa(calls),
+					StatusCode:   http.StatusOK,
+				}, nil
+			}).Times(2)
+
+		resp, err := NewNFTService(raribleClient).GetRarityRankings(context.Background(), request)
+		require.NoError(t, err)
+		require.Equal(t, 1, resp.Total)
+	})
+
+	t.Run("ShouldCapPages", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// every page holds a fresh deleted item and points at another page
+		calls := 0
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemsByCollection(gomock.Any(), collectionID, gomock.Any(), collectionPageSize).
+			DoAndReturn(func(_ context.Context, _, _ string, _ int) (*model.ItemsDTO, error) {
+				calls++
+				return &model.ItemsDTO{
+					Items:        []model.ItemDTO{{ID: collectionID + ":" + strconv.Itoa(calls), Deleted: true}},
+					Continuation: strconv.Itoa(calls),
+					StatusCode:   http.StatusOK,
+				}, nil
+			}).Times(maxCollectionPages)
+
+		_, err := NewNFTService(raribleClient).GetRarityRankings(context.Background(), request)
+		require.ErrorIs(t, err, businesserrors.ErrSomethingWentWrong)
+	})
+}
+
+func TestGetItemRarity(t *testing.T) {
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+	items := []model.ItemDTO{
+		collectionItem(collectionID, "1", "Hat", "Cap", "Eyes", "Blue"),
+		collectionItem(collectionID, "2", "Hat", "Cap", "Eyes", "Blue"),
+		collectionItem(collectionID, "3", "Hat", "Crown", "Eyes", "Blue"),
+	}
+	latest := model.ItemDTO{
+		ID:         collectionID + ":4",
+		Collection: collectionID,
+		TokenID:    "4",
+		Meta:       &model.ItemMetaDTO{Name: "#4", Attributes: []model.MetaAttributeDTO{{Key: "Eyes", Value: "Red"}}},
+		StatusCode: http.StatusOK,
+	}
+
+	t.Run("ShouldBreakDownScoreByTrait", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemByID(gomock.Any(), collectionID+":4").Return(&latest, nil)
+		// the listing lags behind, item 4 is only known by id
+		raribleClient.EXPECT().GetItemsByCollection(gomock.Any(), collectionID, "", gomock.Any()).
+			Return(&model.ItemsDTO{Items: items, StatusCode: http.StatusOK}, nil)
+		raribleClient.EXPECT().GetTraitRarity(gomock.Any(), &model.TraitRarityRequestDTO{
+			CollectionID: collectionID,
+			Properties:   []model.TraitPropertyInput{{Key: "Eyes", Value: "Red"}},
+		}).Return(&model.TraitRarityResponseDTO{
+			Traits:     []model.ExtendedTraitProperty{{Key: "Eyes", Value: "Red", Rarity: 0.4}},
+			StatusCode: http.StatusOK,
+		}, nil)
+
+		resp, err := NewNFTService(raribleClient).GetItemRarity(context.Background(), model.ItemRarityRequestDTO{ItemID: collectionID + ":4"})
+		require.NoError(t, err)
+
+		require.Equal(t, collectionID, resp.CollectionID)
+		require.Equal(t, 4, resp.Total)
+		require.Equal(t, "#4", resp.Name)
+		require.Equal(t, 1, resp.Rank)
+		require.Equal(t, 100.0, resp.Percentile)
+		require.Len(t, resp.Traits, 3)
+
+		eyes := resp.Traits[0]
+		require.Equal(t, "Eyes", eyes.Key)
+		require.Equal(t, model.Rarity(25), eyes.Rarity)
+		require.Equal(t, model.TierUncommon, eyes.Tier)
+		require.Equal(t, model.Rarity(0.4), *eyes.UpstreamRarity)
+		require.Equal(t, 33.33, eyes.Share)
+		require.True(t, resp.Traits[1].Synthetic)
+		require.Nil(t, resp.Traits[1].UpstreamRarity)
+	})
+
+	t.Run("ShouldSucceedWithoutUpstreamRarity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// no collection in the payload, it is taken from the item id
+		item := items[2]
+		item.StatusCode = http.StatusOK
+
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemByID(gomock.Any(), gomock.Any()).Return(&item, nil)
+		raribleClient.EXPECT().GetItemsByCollection(gomock.Any(), collectionID, "", gomock.Any()).
+			Return(&model.ItemsDTO{Items: items, StatusCode: http.StatusOK}, nil)
+		raribleClient.EXPECT().GetTraitRarity(gomock.Any(), gomock.Any()).Return(nil, errors.New("upstream down"))
+
+		resp, err := NewNFTService(raribleClient).GetItemRarity(context.Background(), model.ItemRarityRequestDTO{ItemID: item.ID, Method: "rarity_score"})
+		require.NoError(t, err)
+		require.Equal(t, "rarity_score", resp.Method)
+		require.Equal(t, 1, resp.Rank)
+		require.Equal(t, "Crown", resp.Traits[0].Value)
+		require.Nil(t, resp.Traits[0].UpstreamRarity)
+	})
+
+	t.Run("ShouldReturn404ForMissingItem", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemByID(gomock.Any(), gomock.Any()).
+			Return(&model.ItemDTO{StatusCode: http.StatusNotFound, Message: "item not found"}, nil)
+
+		_, err := NewNFTService(raribleClient).GetItemRarity(context.Background(), model.ItemRarityRequestDTO{ItemID: collectionID + ":9"})
+		require.ErrorIs(t, err, businesserrors.ErrNotFound)
+	})
+}
+
+func TestGetItemTraitRarity(t *testing.T) {
+	const itemID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6:1"
+
+	getItemTraitRarity := func(t *testing.T, meta *model.ItemMetaDTO, req model.ItemTraitRarityRequestDTO, expectRarity func(*client.MockRaribleClient)) *model.ItemTraitRarityResponseDTO {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemByID(gomock.Any(), itemID).
+			Return(&model.ItemDTO{ID: itemID, Meta: meta, StatusCode: http.StatusOK}, nil)
+		if expectRarity != nil {
+			expectRarity(raribleClient)
+		}
+
+		req.ItemID = itemID
+		resp, err := NewNFTService(raribleClient).GetItemTraitRarity(context.Background(), req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("ShouldAnnotateAttributes", func(t *testing.T) {
+		meta := &model.ItemMetaDTO{Name: "#1", Attributes: []model.MetaAttributeDTO{
+			{Key: "Hat", Value: "Cap"}, {Key: "Eyes", Value: "Laser"}, {Key: "Hat", Value: "Cap"}, {Key: "Note"},
+		}}
+		req := model.ItemTraitRarityRequestDTO{TraitRarityOptions: model.TraitRarityOptions{Sort: model.TraitSortRarity}}
+
+		resp := getItemTraitRarity(t, meta, req, func(raribleClient *client.MockRaribleClient) {
+			raribleClient.EXPECT().GetTraitRarity(gomock.Any(), &model.TraitRarityRequestDTO{
+				CollectionID:       "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6",
+				Properties:         []model.TraitPropertyInput{{Key: "Hat", Value: "Cap"}, {Key: "Eyes", Value: "Laser"}},
+				TraitRarityOptions: req.TraitRarityOptions,
+			}).Return(&model.TraitRarityResponseDTO{
+				Traits:     []model.ExtendedTraitProperty{{Key: "Hat", Value: "Cap", Rarity: 30}, {Key: "Eyes", Value: "Laser", Rarity: 0.5}},
+				StatusCode: http.StatusOK,
+			}, nil)
+		})
+
+		require.Equal(t, model.ItemTraitsStatusOK, resp.Status)
+		require.Equal(t, "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6", resp.CollectionID)
+		require.Equal(t, "#1", resp.Name)
+		require.Len(t, resp.Traits, 2)
+		require.Equal(t, "Eyes", resp.Traits[0].Key)
+		require.Equal(t, model.TierLegendary, resp.Traits[0].Tier)
+	})
+
+	t.Run("ShouldNotCallUpstreamForUnrevealedItems", func(t *testing.T) {
+		resp := getItemTraitRarity(t, nil, model.ItemTraitRarityRequestDTO{}, nil)
+		require.Equal(t, model.ItemTraitsStatusUnrevealed, resp.Status)
+		require.Empty(t, resp.Traits)
+
+		placeholder := &model.ItemMetaDTO{Name: "Mystery box", Attributes: []model.MetaAttributeDTO{{Key: "Status", Value: "Not Revealed"}}}
+		resp = getItemTraitRarity(t, placeholder, model.ItemTraitRarityRequestDTO{}, nil)
+		require.Equal(t, model.ItemTraitsStatusUnrevealed, resp.Status)
+
+		flagged := &model.ItemMetaDTO{Attributes: []model.MetaAttributeDTO{{Key: "Revealed", Value: "false"}}}
+		resp = getItemTraitRarity(t, flagged, model.ItemTraitRarityRequestDTO{}, nil)
+		require.Equal(t, model.ItemTraitsStatusUnrevealed, resp.Status)
+	})
+
+	t.Run("ShouldNotCallUpstreamWithoutAttributes", func(t *testing.T) {
+		resp := getItemTraitRarity(t, &model.ItemMetaDTO{Name: "#1", Attributes: []model.MetaAttributeDTO{{Key: "Note"}}}, model.ItemTraitRarityRequestDTO{}, nil)
+		require.Equal(t, model.ItemTraitsStatusNoAttributes, resp.Status)
+		require.NotNil(t, resp.Traits)
+		require.Empty(t, resp.Traits)
+	})
+}
+
+func TestTraitNormalization(t *testing.T) {
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+	normalizer, err := rarity.NewNormalizer(rarity.NormalizationRules{
+		TrimSpace:   true,
+		IgnoredKeys: []string{"Generation"},
+		Collections: map[string]rarity.CollectionRules{
+			collectionID: {ValueAliases: map[string]map[string]string{"Background": {"GOLD": "Gold"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("ShouldSendCallerSpellingUpstream", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// later spellings of the same trait and ignored keys are dropped, the first spelling is kept as is
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetTraitRarity(gomock.Any(), &model.TraitRarityRequestDTO{
+			CollectionID: "ETHEREUM:0x60E4D786628FEA6478F785A6D7E704777C86A7C6",
+			Properties:   []model.TraitPropertyInput{{Key: "Background ", Value: "GOLD"}},
+		}).Return(&model.TraitRarityResponseDTO{StatusCode: http.StatusOK}, nil)
+
+		_, err := NewNFTService(raribleClient, WithTraitNormalizer(normalizer)).GetTraitRarity(context.Background(), model.TraitRarityRequestDTO{
+			CollectionID: "ETHEREUM:0x60E4D786628FEA6478F785A6D7E704777C86A7C6",
+			Properties: []model.TraitPropertyInput{
+				{Key: "Background ", Value: "GOLD"}, {Key: "Background", Value: " Gold"}, {Key: "Generation", Value: "2"},
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("ShouldCountNormalizedTraitsLocally", func(t *testing.T) {
+		raribleClient := mockCollection(t, collectionID, []model.ItemDTO{
+			collectionItem(collectionID, "1", "Background", "Gold", "Generation", "1"),
+			collectionItem(collectionID, "2", "Background", "GOLD", "Generation", "2"),
+			collectionItem(collectionID, "3", "Background", "Gold ", "Generation", "3"),
+		})
+
+		resp, err := NewNFTService(raribleClient, WithTraitNormalizer(normalizer)).GetRarityRankings(context.Background(), model.RarityRankingsRequestDTO{
+			CollectionID: collectionID,
+			Limit:        10,
+		})
+		require.NoError(t, err)
+		// one spelling and no generation left, so every item is as rare as the others
+		for _, item := range resp.Items {
+			require.Equal(t, 1, item.Rank)
+			require.Zero(t, item.Score)
+		}
+	})
+}
+
+func TestTraitNormalization_CaseFold(t *testing.T) {
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+	normalizer, err := rarity.NewNormalizer(rarity.NormalizationRules{CaseFold: true})
+	require.NoError(t, err)
+
+	// exactMatchUpstream answers like Rarible, only for properties spelled as it stores them
+	exactMatchUpstream := func(raribleClient *client.MockRaribleClient) {
+		stored := map[model.TraitPropertyInput]model.Rarity{{Key: "Hat", Value: "Gold"}: 1.5}
+		raribleClient.EXPECT().GetTraitRarity(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *model.TraitRarityRequestDTO) (*model.TraitRarityResponseDTO, error) {
+				resp := &model.TraitRarityResponseDTO{StatusCode: http.StatusOK}
+				for _, property := range req.Properties {
+					if rarity, ok := stored[property]; ok {
+						resp.Traits = append(resp.Traits, model.ExtendedTraitProperty{Key: property.Key, Value: property.Value, Rarity: rarity})
+					}
+				}
+				return resp, nil
+			})
+	}
+
+	t.Run("ShouldFindTraitRarity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		exactMatchUpstream(raribleClient)
+
+		resp, err := NewNFTService(raribleClient, WithTraitNormalizer(normalizer)).GetTraitRarity(context.Background(), model.TraitRarityRequestDTO{
+			CollectionID: collectionID,
+			Properties:   []model.TraitPropertyInput{{Key: "Hat", Value: "Gold"}, {Key: "hat", Value: "GOLD"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []model.ExtendedTraitProperty{{Key: "Hat", Value: "Gold", Rarity: 1.5, Rank: 1, Percentile: 100, Tier: model.TierEpic}}, resp.Traits)
+	})
+
+	t.Run("ShouldFindUpstreamRarityOfBreakdown", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		item := collectionItem(collectionID, "1", "Hat", "Gold")
+		item.StatusCode = http.StatusOK
+
+		raribleClient := mockCollection(t, collectionID, []model.ItemDTO{item, collectionItem(collectionID, "2", "HAT", "gold")})
+		raribleClient.EXPECT().GetItemByID(gomock.Any(), item.ID).Return(&item, nil)
+		exactMatchUpstream(raribleClient)
+
+		resp, err := NewNFTService(raribleClient, WithTraitNormalizer(normalizer)).GetItemRarity(context.Background(), model.ItemRarityRequestDTO{ItemID: item.ID})
+		require.NoError(t, err)
+		require.Equal(t, "hat", resp.Traits[0].Key)
+		require.Equal(t, 2, resp.Traits[0].Count)
+		require.NotNil(t, resp.Traits[0].UpstreamRarity)
+		require.Equal(t, model.Rarity(1.5), *resp.Traits[0].UpstreamRarity)
+	})
+}
+
+func TestTraitCombinations(t *testing.T) {
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+	newService := func(t *testing.T) NFTService {
+		return NewNFTService(mockCollection(t, collectionID, []model.ItemDTO{
+			collectionItem(collectionID, "1", "Eyes", "Laser", "Background", "Blue"),
+			collectionItem(collectionID, "2", "Eyes", "Laser", "Background", "Blue"),
+			collectionItem(collectionID, "3", "Eyes", "Laser", "Background", "Gold"),
+			collectionItem(collectionID, "4", "Eyes", "Sleepy", "Background", "Gold"),
+		}))
+	}
+
+	t.Run("ShouldReturnRarestPairs", func(t *testing.T) {
+		resp, err := newService(t).GetTraitCombinations(context.Background(), model.TraitCombinationsRequestDTO{CollectionID: collectionID, Size: 2, Limit: 2})
+		require.NoError(t, err)
+
+		require.Equal(t, 4, resp.Total)
+		require.Len(t, resp.Combinations, 2)
+		require.Equal(t, model.TraitCombinationDTO{
+			Traits: []model.TraitPropertyInput{{Key: "Background", Value: "Gold"}, {Key: "Eyes", Value: "Laser"}},
+			Count:  1,
+			Rarity: 25,
+			Tier:   model.TierUncommon,
+		}, resp.Combinations[0])
+		require.Equal(t, 1, resp.Combinations[1].Count)
+	})
+
+	t.Run("ShouldCountCombinationsOfMatchingItemsOnly", func(t *testing.T) {
+		resp, err := newService(t).GetTraitCombinations(context.Background(), model.TraitCombinationsRequestDTO{
+			CollectionID: collectionID, Size: 2, Limit: 10, Filter: `Eyes = Laser`,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, 3, resp.Total)
+		require.Equal(t, model.TraitCombinationDTO{
+			Traits: []model.TraitPropertyInput{{Key: "Background", Value: "Gold"}, {Key: "Eyes", Value: "Laser"}},
+			Count:  1,
+			Rarity: 33.33,
+			Tier:   model.TierUncommon,
+		}, resp.Combinations[0])
+	})
+
+	t.Run("ShouldRejectInvalidFilter", func(t *testing.T) {
+		_, err := newService(t).GetTraitCombinations(context.Background(), model.TraitCombinationsRequestDTO{
+			CollectionID: collectionID, Size: 2, Limit: 10, Filter: `Eyes =`,
+		})
+		require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+	})
+
+	t.Run("ShouldCompareCombinationWithIndependentTraits", func(t *testing.T) {
+		resp, err := newService(t).GetTraitCombinationRarity(context.Background(), model.TraitRarityRequestDTO{
+			CollectionID: collectionID,
+			Properties:   []model.TraitPropertyInput{{Key: "Eyes", Value: "Laser"}, {Key: "Background", Value: "Gold"}},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, 1, resp.Count)
+		require.Equal(t, model.Rarity(25), resp.Rarity)
+		// 75% have laser eyes and 50% a gold background
+		require.Equal(t, model.Rarity(37.5), resp.ExpectedRarity)
+		require.Equal(t, []string{"Background", "Eyes"}, []string{resp.Individual[0].Key, resp.Individual[1].Key})
+		require.Equal(t, 1, resp.Individual[0].Rank)
+	})
+}
+
+func TestGetTraitStats(t *testing.T) {
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+	items := []model.ItemDTO{
+		collectionItem(collectionID, "1", "Eyes", "Laser", "Hat", "Cap"),
+		collectionItem(collectionID, "2", "Eyes", "Laser", "Hat", "Cap"),
+		collectionItem(collectionID, "3", "Eyes", "Laser", "Hat", "Crown"),
+		collectionItem(collectionID, "4", "Eyes", "Sleepy"),
+	}
+
+	t.Run("ShouldDescribeEveryKey", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemsByCollection(gomock.Any(), collectionID, "", gomock.Any()).
+			Return(&model.ItemsDTO{Items: items, StatusCode: http.StatusOK}, nil)
+
+		longTail := 25.0
+		resp, err := NewNFTService(raribleClient).GetTraitStats(context.Background(), model.TraitStatsRequestDTO{CollectionID: collectionID, LongTail: &longTail})
+		require.NoError(t, err)
+
+		require.Equal(t, 4, resp.Items)
+		require.Equal(t, 2, resp.Keys)
+		require.Equal(t, 4, resp.Values)
+		require.Equal(t, []model.TraitCountBucketDTO{{Traits: 1, Items: 1}, {Traits: 2, Items: 3}}, resp.TraitCounts)
+
+		hat := resp.TraitKeys[1]
+		require.Equal(t, "Hat", hat.Key)
+		require.Equal(t, 1, hat.Missing)
+		require.Equal(t, model.Rarity(25), hat.MissingRarity)
+		require.Equal(t, 1.5, hat.Entropy)
+		// Cap, Crown and no hat at all are three outcomes, spread 2:1:1
+		require.Equal(t, 0.946395, hat.NormalizedEntropy)
+		require.Equal(t, []string{"Crown"}, hat.LongTail)
+		require.Equal(t, []model.TraitValueStatsDTO{
+			{Value: "Cap", Count: 2, Rarity: 50, Tier: model.TierCommon},
+			{Value: "Crown", Count: 1, Rarity: 25, Tier: model.TierUncommon},
+		}, hat.Distribution)
+		require.Equal(t, []model.TierBucketDTO{
+			{Tier: model.TierLegendary}, {Tier: model.TierEpic}, {Tier: model.TierRare},
+			{Tier: model.TierUncommon, Values: 1, Items: 1}, {Tier: model.TierCommon, Values: 1, Items: 2},
+		}, hat.Histogram)
+	})
+
+	t.Run("ShouldCachePerEnvironmentAndCollection", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemsByCollection(gomock.Any(), collectionID, "", gomock.Any()).
+			Return(&model.ItemsDTO{Items: items, StatusCode: http.StatusOK}, nil).Times(2)
+
+		s := NewNFTService(raribleClient)
+		first, err := s.GetTraitStats(context.Background(), model.TraitStatsRequestDTO{CollectionID: collectionID})
+		require.NoError(t, err)
+		second, err := s.GetTraitStats(context.Background(), model.TraitStatsRequestDTO{CollectionID: collectionID})
+		require.NoError(t, err)
+		require.Equal(t, first.ComputedAt, second.ComputedAt)
+
+		_, err = s.GetTraitStats(raribleclient.WithEnvironment(context.Background(), "testnet"), model.TraitStatsRequestDTO{CollectionID: collectionID})
+		require.NoError(t, err)
+	})
+
+	t.Run("ShouldDescribeMatchingItemsAndCachePerFilter", func(t *testing.T) {
+		s := NewNFTService(mockCollection(t, collectionID, items))
+
+		all, err := s.GetTraitStats(context.Background(), model.TraitStatsRequestDTO{CollectionID: collectionID})
+		require.NoError(t, err)
+		require.Equal(t, 4, all.Items)
+
+		filtered, err := s.GetTraitStats(context.Background(), model.TraitStatsRequestDTO{CollectionID: collectionID, Filter: `Hat EXISTS`})
+		require.NoError(t, err)
+		require.Equal(t, 3, filtered.Items)
+		require.Equal(t, []model.TraitCountBucketDTO{{Traits: 2, Items: 3}}, filtered.TraitCounts)
+		require.Zero(t, filtered.TraitKeys[1].Missing)
+
+		// served from the entry of the first filtered request
+		respelled, err := s.GetTraitStats(context.Background(), model.TraitStatsRequestDTO{CollectionID: collectionID, Filter: `"Hat"  exists`})
+		require.NoError(t, err)
+		require.Equal(t, filtered.ComputedAt, respelled.ComputedAt)
+	})
+
+	t.Run("ShouldNotCacheWithoutTTL", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		raribleClient := client.NewMockRaribleClient(ctrl)
+		raribleClient.EXPECT().GetItemsByCollection(gomock.Any(), collectionID, "", gomock.Any()).
+			Return(&model.ItemsDTO{Items: items, StatusCode: http.StatusOK}, nil).Times(2)
+
+		s := NewNFTService(raribleClient, WithTraitStatsCacheTTL(0))
+		for range 2 {
+			_, err := s.GetTraitStats(context.Background(), model.TraitStatsRequestDTO{CollectionID: collectionID})
+			require.NoError(t, err)
+		}
+	})
+}
+
+func TestGetSimilarItems(t *testing.T) {
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+	newService := func(t *testing.T) NFTService {
+		item := collectionItem(collectionID, "1", "Hat", "Crown", "Eyes", "Laser", "Background", "Blue")
+		item.StatusCode = http.StatusOK
+
+		raribleClient := mockCollection(t, collectionID, []model.ItemDTO{
+			item,
+			collectionItem(collectionID, "2", "Hat", "Crown", "Eyes", "Laser", "Background", "Gold"),
+			collectionItem(collectionID, "3", "Hat", "Cap", "Eyes", "Sleepy", "Background", "Blue"),
+			collectionItem(collectionID, "4", "Hat", "Cap", "Eyes", "Sleepy", "Background", "Gold"),
+		})
+		raribleClient.EXPECT().GetItemByID(gomock.Any(), item.ID).Return(&item, nil)
+		return NewNFTService(raribleClient)
+	}
+
+	t.Run("ShouldReturnSharedAndDifferingTraits", func(t *testing.T) {
+		resp, err := newService(t).GetSimilarItems(context.Background(), model.SimilarItemsRequestDTO{ItemID: collectionID + ":1", Method: "jaccard", Limit: 1})
+		require.NoError(t, err)
+
+		require.Equal(t, "jaccard", resp.Method)
+		require.Equal(t, 2, resp.Total)
+		require.Equal(t, []model.SimilarItemDTO{{
+			ItemID:     collectionID + ":2",
+			TokenID:    "2",
+			Name:       "#2",
+			Similarity: 0.5,
+			Shared:     []model.TraitPropertyInput{{Key: "Hat", Value: "Crown"}, {Key: "Eyes", Value: "Laser"}},
+			Differing:  []model.TraitDifferenceDTO{{Key: "Background", Values: []string{"Blue"}, SimilarValues: []string{"Gold"}}},
+		}}, resp.Items)
+	})
+
+	t.Run("ShouldFilterSimilarItems", func(t *testing.T) {
+		resp, err := newService(t).GetSimilarItems(context.Background(), model.SimilarItemsRequestDTO{ItemID: collectionID + ":1", Filter: `Background = Blue`, Limit: 10})
+		require.NoError(t, err)
+
+		require.Equal(t, 1, resp.Total)
+		require.Equal(t, collectionID+":3", resp.Items[0].ItemID)
+	})
+
+	t.Run("ShouldExcludeKeys", func(t *testing.T) {
+		resp, err := newService(t).GetSimilarItems(context.Background(), model.SimilarItemsRequestDTO{ItemID: collectionID + ":1", Exclude: []string{"Hat", "Eyes"}, Limit: 10})
+		require.NoError(t, err)
+
+		require.Equal(t, "weighted", resp.Method)
+		require.Len(t, resp.Items, 1)
+		require.Equal(t, collectionID+":3", resp.Items[0].ItemID)
+		require.Equal(t, 1.0, resp.Items[0].Similarity)
+	})
+}
+
+func TestTraitFilterNormalization(t *testing.T) {
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+
+	normalizer, err := rarity.NewNormalizer(rarity.NormalizationRules{
+		TrimSpace: true,
+		CaseFold:  true,
+		Collections: map[string]rarity.CollectionRules{
+			collectionID: {Buckets: map[string][]float64{"Level": {10, 50}}},
+		},
+	})
+	require.NoError(t, err)
+	s := &nftService{normalizer: normalizer}
+	items := s.rarityItems(collectionID, []model.ItemDTO{
+		collectionItem(collectionID, "1", "Hat", "Halo", "Level", "30", "Power", "30"),
+		collectionItem(collectionID, "2", "Hat", "Cap", "Level", "5", "Power", "5"),
+	})
+
+	t.Run("ShouldMatchNormalizedSpellingsAndBuckets", func(t *testing.T) {
+		expr, err := s.traitFilter(collectionID, `HAT = " Halo" AND Level = 12`)
+		require.NoError(t, err)
+		require.Equal(t, `("hat" = "halo" AND "level" = "10-50")`, expr.String())
+		require.Equal(t, map[string]bool{collectionID + ":1": true}, matchingItems(expr, items))
+	})
+
+	t.Run("ShouldCompareRawNumbersOfUnbucketedTraits", func(t *testing.T) {
+		expr, err := s.traitFilter(collectionID, `Power >= 12`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{collectionID + ":1": true}, matchingItems(expr, items))
+	})
+
+	t.Run("ShouldRejectOrderingBucketedTraits", func(t *testing.T) {
+		for _, query := range []string{`Level >= 12`, `Hat = Halo AND NOT level < 12`} {
+			_, err := s.traitFilter(collectionID, query)
+			require.ErrorIs(t, err, businesserrors.ErrInvalidRequest, query)
+		}
+	})
+}
+
+func TestOpenRarity(t *testing.T) {
+	const collectionID = "ETHEREUM:0x60e4d786628fea6478f785a6d7e704777c86a7c6"
+	newService := func(t *testing.T) NFTService {
+		// upstream omits the token id of item 4, it is taken from the item id
+		withoutTokenID := collectionItem(collectionID, "4", "Eyes", "Blue")
+		withoutTokenID.TokenID = ""
+
+		return NewNFTService(mockCollection(t, collectionID, []model.ItemDTO{
+			collectionItem(collectionID, "1", "Eyes", "Blue", "Hat", "Cap"),
+			collectionItem(collectionID, "2", "Eyes", "Blue", "Hat", "Cap"),
+			collectionItem(collectionID, "3", "Eyes", "Blue", "Hat", "Crown"),
+			withoutTokenID,
+		}))
+	}
+
+	t.Run("ShouldExportRankingWithOpenRarityOptions", func(t *testing.T) {
+		resp, err := newService(t).ExportOpenRarity(context.Background(), collectionID)
+		require.NoError(t, err)
+
+		require.Equal(t, "ETHEREUM", resp.Chain)
+		require.Equal(t, "0x60e4d786628fea6478f785a6d7e704777c86a7c6", resp.ContractAddress)
+		require.Equal(t, 4, resp.TotalSupply)
+		// 3's unique crown ranks it first although 4's missing hat gives it more information
+		require.Equal(t, []model.OpenRarityTokenID{"3", "4", "1", "2"},
+			[]model.OpenRarityTokenID{resp.Tokens[0].TokenID, resp.Tokens[1].TokenID, resp.Tokens[2].TokenID, resp.Tokens[3].TokenID})
+		require.Equal(t, []int{1, 2, 3, 3}, []int{resp.Tokens[0].Rank, resp.Tokens[1].Rank, resp.Tokens[2].Rank, resp.Tokens[3].Rank})
+		require.Equal(t, []int{1, 0}, []int{resp.Tokens[0].UniqueAttributeCount, resp.Tokens[1].UniqueAttributeCount})
+		require.Less(t, resp.Tokens[0].Score, resp.Tokens[1].Score)
+	})
+
+	t.Run("ShouldReportRankDifferences", func(t *testing.T) {
+		resp, err := newService(t).DiffOpenRarity(context.Background(), model.OpenRarityDiffRequestDTO{
+			CollectionID: collectionID,
+			Rankings: model.OpenRarityRankingsDTO{Tokens: []model.OpenRarityTokenDTO{
+				{TokenID: "003", Rank: 2, Score: 1.5},
+				{TokenID: "4", Rank: 1, Score: 2},
+				{TokenID: "1", Rank: 3},
+				{TokenID: "99", Rank: 4},
+			}},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, 4, resp.Total)
+		require.Equal(t, 4, resp.Imported)
+		require.Equal(t, 3, resp.Compared)
+		require.Equal(t, 1, resp.Identical)
+		require.Equal(t, 0.67, resp.MeanAbsRankDelta)
+		require.Equal(t, 1, resp.MaxAbsRankDelta)
+		require.Equal(t, []string{"2"}, resp.Missing)
+		require.Equal(t, []string{"99"}, resp.Unknown)
+		require.Len(t, resp.Tokens, 2)
+		require.Equal(t, model.OpenRarityTokenDiffDTO{
+			TokenID:       "3",
+			ItemID:        collectionID + ":3",
+			Rank:          1,
+			ImportedRank:  2,
+			RankDelta:     1,
+			Score:         resp.Tokens[0].Score,
+			ImportedScore: 1.5,
+		}, resp.Tokens[0])
+		require.Equal(t, -1, resp.Tokens[1].RankDelta)
+	})
+}
+
+func TestGetMetadataRarity(t *testing.T) {
+	metadataZip := func(t *testing.T, files map[string]string) []byte {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		for name, content := range files {
+			f, err := w.Create(name)
+			require.NoError(t, err)
+			_, err = f.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	archive := metadataZip(t, map[string]string{
+		"1.json": `{"name":"#1","attributes":[{"trait_type":"Hat","value":"Cap"},{"trait_type":"Eyes","value":"Blue"}]}`,
+		"2.json": `{"name":"#2","attributes":[{"trait_type":"Hat","value":"cap "},{"trait_type":"Eyes","value":"Blue"}]}`,
+		"3.json": `{"name":"#3","attributes":[{"trait_type":"Hat","value":"Crown"},{"trait_type":"Eyes","value":"Blue"}]}`,
+		"4.json": `{"name":"#4","attributes":[{"trait_type":"Eyes","value":"Red"}]}`,
+		"5.json": `{"name":`,
+	})
+
+	t.Run("ShouldRankWithoutUpstream", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		normalizer, err := rarity.NewNormalizer(rarity.NormalizationRules{TrimSpace: true, CaseFold: true})
+		require.NoError(t, err)
+		// no call is expected on the client
+		s := NewNFTService(client.NewMockRaribleClient(ctrl), WithTraitNormalizer(normalizer))
+
+		resp, err := s.GetMetadataRarity(context.Background(), model.MetadataRarityRequestDTO{Archive: archive})
+		require.NoError(t, err)
+
+		require.Equal(t, "information_content", resp.Method)
+		require.Equal(t, 4, resp.Total)
+		require.Equal(t, []string{"4", "3", "1", "2"}, []string{resp.Tokens[0].ItemID, resp.Tokens[1].ItemID, resp.Tokens[2].ItemID, resp.Tokens[3].ItemID})
+		require.Equal(t, "#4", resp.Tokens[0].Name)
+		require.Equal(t, "4", resp.Tokens[0].TokenID)
+		// "Cap" and "cap " are one trait once normalised
+		require.Equal(t, 3, resp.Tokens[2].Rank)
+		require.Equal(t, 3, resp.Tokens[3].Rank)
+
+		require.Equal(t, 4, resp.TraitStats.Items)
+		require.Equal(t, 2, resp.TraitStats.Keys)
+		require.Equal(t, "hat", resp.TraitStats.TraitKeys[1].Key)
+		require.Equal(t, 1, resp.TraitStats.TraitKeys[1].Missing)
+		require.Equal(t, []model.SkippedFileDTO{{File: "5.json", Reason: resp.Skipped[0].Reason}}, resp.Skipped)
+	})
+
+	t.Run("ShouldRejectUnreadableArchive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := NewNFTService(client.NewMockRaribleClient(ctrl), WithMaxCollectionItems(2))
+		_, err := s.GetMetadataRarity(context.Background(), model.MetadataRarityRequestDTO{Archive: []byte("not an archive")})
+		require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+
+		_, err = s.GetMetadataRarity(context.Background(), model.MetadataRarityRequestDTO{Archive: archive})
+		require.ErrorIs(t, err, businesserrors.ErrInvalidRequest)
+	})
+}
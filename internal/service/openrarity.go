@@ -0,0 +1,177 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+)
+
+// ExportOpenRarity ranks a collection the way OpenRarity does and returns it in OpenRarity's layout
+func (s *nftService) ExportOpenRarity(ctx context.Context, collectionID string) (*model.OpenRarityRankingsDTO, error) {
+	tokens, err := s.openRarityTokens(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.OpenRarityRankingsDTO{
+		CollectionID: collectionID,
+		TotalSupply:  len(tokens),
+		Tokens:       make([]model.OpenRarityTokenDTO, 0, len(tokens)),
+	}
+	if id, err := model.ParseCollectionID(collectionID); err == nil {
+		resp.Chain = string(id.Blockchain)
+		resp.ContractAddress = id.Contract
+	}
+	for _, token := range tokens {
+		resp.Tokens = append(resp.Tokens, token.OpenRarityTokenDTO)
+	}
+	return resp, nil
+}
+
+// DiffOpenRarity compares an imported OpenRarity ranking of a collection with the one computed here
+func (s *nftService) DiffOpenRarity(ctx context.Context, req model.OpenRarityDiffRequestDTO) (*model.OpenRarityDiffDTO, error) {
+	tokens, err := s.openRarityTokens(ctx, req.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	imported := make(map[string]model.OpenRarityTokenDTO, len(req.Rankings.Tokens))
+	resp := &model.OpenRarityDiffDTO{
+		CollectionID: req.CollectionID,
+		Total:        len(tokens),
+		Missing:      []string{},
+		Unknown:      []string{},
+		Tokens:       []model.OpenRarityTokenDiffDTO{},
+	}
+	for _, token := range req.Rankings.Tokens {
+		tokenID := canonicalTokenID(string(token.TokenID))
+		if _, ok := imported[tokenID]; !ok {
+			imported[tokenID] = token
+		}
+	}
+	resp.Imported = len(imported)
+
+	known := make(map[string]bool, len(tokens))
+	totalDelta := 0
+	for _, token := range tokens {
+		tokenID := string(token.TokenID)
+		known[tokenID] = true
+		theirs, ok := imported[tokenID]
+		if !ok {
+			resp.Missing = append(resp.Missing, tokenID)
+			continue
+		}
+
+		resp.Compared++
+		delta := theirs.Rank - token.Rank
+		if delta == 0 {
+			resp.Identical++
+			continue
+		}
+		abs := max(delta, -delta)
+		totalDelta += abs
+		resp.MaxAbsRankDelta = max(resp.MaxAbsRankDelta, abs)
+		resp.Tokens = append(resp.Tokens, model.OpenRarityTokenDiffDTO{
+			TokenID:       tokenID,
+			ItemID:        token.itemID,
+			Rank:          token.Rank,
+			ImportedRank:  theirs.Rank,
+			RankDelta:     delta,
+			Score:         token.Score,
+			ImportedScore: theirs.Score,
+		})
+	}
+	for tokenID := range imported {
+		if !known[tokenID] {
+			resp.Unknown = append(resp.Unknown, tokenID)
+		}
+	}
+	slices.SortFunc(resp.Unknown, model.CompareTokenIDs)
+	if resp.Compared > 0 {
+		resp.MeanAbsRankDelta = math.Round(float64(totalDelta)/float64(resp.Compared)*100) / 100
+	}
+	slices.SortFunc(resp.Tokens, func(a, b model.OpenRarityTokenDiffDTO) int {
+		return cmp.Or(cmp.Compare(max(b.RankDelta, -b.RankDelta), max(a.RankDelta, -a.RankDelta)), cmp.Compare(a.Rank, b.Rank))
+	})
+	return resp, nil
+}
+
+// openRarityToken is a ranked token and the item it is
+type openRarityToken struct {
+	model.OpenRarityTokenDTO
+	itemID string
+}
+
+// openRarityTokens scores a collection with OpenRarity's options and ranks it by OpenRarity's rule:
+// tokens with more unique attributes first, then by information content over every trait, missing
+// traits and trait count included. A token scoring the same as the one before shares its rank.
+func (s *nftService) openRarityTokens(ctx context.Context, collectionID string) ([]openRarityToken, error) {
+	items, err := s.getCollectionItems(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	collection := rarity.Compute(s.rarityItems(collectionID, items), rarity.DefaultOptions())
+	byID := make(map[string]model.ItemDTO, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	ranked := collection.Ranked(rarity.MethodInformationContent)
+	tokens := make([]openRarityToken, 0, len(ranked))
+	for _, score := range ranked {
+		tokens = append(tokens, openRarityToken{
+			OpenRarityTokenDTO: model.OpenRarityTokenDTO{
+				TokenID:              model.OpenRarityTokenID(canonicalTokenID(itemTokenID(byID[score.ID]))),
+				Score:                score.Scores.InformationContent,
+				UniqueAttributeCount: collection.UniqueTraits(score.ID),
+			},
+			itemID: score.ID,
+		})
+	}
+	slices.SortStableFunc(tokens, func(a, b openRarityToken) int {
+		return cmp.Compare(b.UniqueAttributeCount, a.UniqueAttributeCount)
+	})
+	for i := range tokens {
+		tokens[i].Rank = i + 1
+		if i > 0 && scoresClose(tokens[i].Score, tokens[i-1].Score) {
+			tokens[i].Rank = tokens[i-1].Rank
+		}
+	}
+	return tokens, nil
+}
+
+// scoresClose reports whether two scores are equal within the relative tolerance
+// OpenRarity ties them with, that of Python's math.isclose
+func scoresClose(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*max(math.Abs(a), math.Abs(b))
+}
+
+// itemTokenID returns an item's token id, its mint for items such as Solana ones that have none
+func itemTokenID(item model.ItemDTO) string {
+	if item.TokenID != "" {
+		return item.TokenID
+	}
+	id, err := model.ParseItemID(item.ID)
+	if err != nil {
+		return item.ID
+	}
+	if id.TokenID == "" {
+		return id.Contract
+	}
+	return id.TokenID
+}
+
+// canonicalTokenID writes decimal token ids without leading zeros, so "007" and 7 match
+func canonicalTokenID(tokenID string) string {
+	tokenID = strings.TrimSpace(tokenID)
+	if n, ok := new(big.Int).SetString(tokenID, 10); ok {
+		return n.String()
+	}
+	return tokenID
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+)
+
+// Option configures optional behaviour of the service
+type Option func(*nftService)
+
+// WithRarityTiers sets the thresholds trait rarities are labelled with, model.DefaultRarityTiers otherwise
+func WithRarityTiers(tiers model.RarityTiers) Option {
+	return func(s *nftService) {
+		s.rarityTiers = tiers
+	}
+}
+
+// WithMaxCollectionItems bounds how many items are fetched to score a collection, 10000 otherwise.
+// Larger collections are rejected as invalid requests.
+func WithMaxCollectionItems(n int) Option {
+	return func(s *nftService) {
+		s.maxCollectionItems = n
+	}
+}
+
+// WithTraitNormalizer cleans up trait keys and values before they are sent upstream or counted locally
+func WithTraitNormalizer(normalizer *rarity.Normalizer) Option {
+	return func(s *nftService) {
+		s.normalizer = normalizer
+	}
+}
+
+// WithTraitStatsCacheTTL sets how long collection trait stats are reused, 10 minutes otherwise. Zero disables caching.
+func WithTraitStatsCacheTTL(ttl time.Duration) Option {
+	return func(s *nftService) {
+		s.traitStats = newTTLCache[rarity.Stats](ttl)
+	}
+}
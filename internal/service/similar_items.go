@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+)
+
+// GetSimilarItems ranks the other items of an item's collection by how many of its traits they share
+func (s *nftService) GetSimilarItems(ctx context.Context, req model.SimilarItemsRequestDTO) (*model.SimilarItemsResponseDTO, error) {
+	measure := rarity.SimilarityWeighted
+	if req.Method != "" {
+		measure = rarity.Similarity(req.Method)
+	}
+
+	item, collectionID, items, err := s.getItemWithCollection(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := s.traitFilter(collectionID, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	rarityItems := s.rarityItems(collectionID, items)
+	collection := rarity.Compute(rarityItems, rarity.Options{})
+	matching := matchingItems(expr, rarityItems)
+	byID := make(map[string]model.ItemDTO, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	matches, _ := collection.Similar(item.ID, measure, s.excludedKeys(collectionID, req.Exclude))
+	matches = slices.DeleteFunc(matches, func(match rarity.Match) bool { return !matching[match.ID] })
+	resp := &model.SimilarItemsResponseDTO{
+		ItemID:       item.ID,
+		CollectionID: collectionID,
+		Method:       string(measure),
+		Total:        len(matches),
+		Items:        make([]model.SimilarItemDTO, 0, min(req.Limit, len(matches))),
+	}
+	for _, match := range matches[:min(req.Limit, len(matches))] {
+		resp.Items = append(resp.Items, similarItem(byID[match.ID], match))
+	}
+	return resp, nil
+}
+
+// excludedKeys normalises trait keys the way the collection's trait keys are
+func (s *nftService) excludedKeys(collectionID string, keys []string) []string {
+	traits := make([]rarity.Trait, 0, len(keys))
+	for _, key := range keys {
+		traits = append(traits, rarity.Trait{Key: key})
+	}
+
+	excluded := make([]string, 0, len(keys))
+	for _, trait := range s.normalizer.NormalizeText(normalizerCollectionID(collectionID), traits) {
+		excluded = append(excluded, trait.Key)
+	}
+	return excluded
+}
+
+func similarItem(item model.ItemDTO, match rarity.Match) model.SimilarItemDTO {
+	dto := model.SimilarItemDTO{
+		ItemID:     match.ID,
+		TokenID:    item.TokenID,
+		Similarity: math.Round(match.Score*1e6) / 1e6,
+		Shared:     make([]model.TraitPropertyInput, 0, len(match.Shared)),
+		Differing:  []model.TraitDifferenceDTO{},
+	}
+	if item.Meta != nil {
+		dto.Name = item.Meta.Name
+	}
+	for _, trait := range match.Shared {
+		dto.Shared = append(dto.Shared, model.TraitPropertyInput{Key: trait.Key, Value: trait.Value})
+	}
+
+	differing := make(map[string]int)
+	difference := func(key string) *model.TraitDifferenceDTO {
+		i, ok := differing[key]
+		if !ok {
+			i = len(dto.Differing)
+			differing[key] = i
+			dto.Differing = append(dto.Differing, model.TraitDifferenceDTO{Key: key, Values: []string{}, SimilarValues: []string{}})
+		}
+		return &dto.Differing[i]
+	}
+	for _, trait := range match.Only {
+		difference(trait.Key).Values = append(difference(trait.Key).Values, trait.Value)
+	}
+	for _, trait := range match.Other {
+		difference(trait.Key).SimilarValues = append(difference(trait.Key).SimilarValues, trait.Value)
+	}
+	return dto
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/Megidy/rarible/internal/domain/filter"
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+)
+
+// GetTraitCombinations counts the trait pairs or triples of a collection's items, or of the ones
+// matching the request's filter, and returns the rarest
+func (s *nftService) GetTraitCombinations(ctx context.Context, req model.TraitCombinationsRequestDTO) (*model.TraitCombinationsResponseDTO, error) {
+	expr, err := s.traitFilter(req.CollectionID, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := s.computeCollection(ctx, req.CollectionID, expr, rarity.DefaultOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	combinations := collection.Combinations(req.Size)
+	resp := &model.TraitCombinationsResponseDTO{
+		CollectionID: req.CollectionID,
+		Size:         req.Size,
+		Total:        collection.Total(),
+		Combinations: make([]model.TraitCombinationDTO, 0, min(req.Limit, len(combinations))),
+	}
+	for _, combination := range combinations[:min(req.Limit, len(combinations))] {
+		resp.Combinations = append(resp.Combinations, s.traitCombination(combination))
+	}
+	return resp, nil
+}
+
+// GetTraitCombinationRarity returns how many items of a collection have every requested trait,
+// compared with how many would if the traits occurred independently
+func (s *nftService) GetTraitCombinationRarity(ctx context.Context, req model.TraitRarityRequestDTO) (*model.TraitCombinationRarityDTO, error) {
+	traits := make([]rarity.Trait, 0, len(req.Properties))
+	for _, property := range req.Properties {
+		traits = append(traits, rarity.Trait{Key: property.Key, Value: property.Value})
+	}
+	traits = s.normalizer.Normalize(normalizerCollectionID(req.CollectionID), traits)
+	if len(traits) == 0 {
+		return nil, fmt.Errorf("%w: no properties left to combine", businesserrors.ErrInvalidRequest)
+	}
+
+	collection, err := s.computeCollection(ctx, req.CollectionID, nil, rarity.DefaultOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	combination := collection.Combination(traits)
+	resp := &model.TraitCombinationRarityDTO{
+		CollectionID:        req.CollectionID,
+		Total:               collection.Total(),
+		TraitCombinationDTO: s.traitCombination(combination),
+		Individual:          make([]model.ExtendedTraitProperty, 0, len(combination.Traits)),
+	}
+	expected := 1.0
+	for _, trait := range combination.Traits {
+		frequency := collection.Frequency(trait)
+		expected *= frequency.Frequency
+		resp.Individual = append(resp.Individual, model.ExtendedTraitProperty{
+			Key:    trait.Key,
+			Value:  trait.Value,
+			Rarity: percent(frequency.Frequency),
+		})
+	}
+	resp.ExpectedRarity = percent(expected)
+	rankTraits(resp.Individual, s.rarityTiers)
+	return resp, nil
+}
+
+// computeCollection fetches every item of a collection and scores the ones matching expr, every item when nil
+func (s *nftService) computeCollection(ctx context.Context, collectionID string, expr filter.Expr, opts rarity.Options) (*rarity.Collection, error) {
+	items, err := s.getCollectionItems(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	rarityItems := s.rarityItems(collectionID, items)
+	if expr != nil {
+		matching := matchingItems(expr, rarityItems)
+		rarityItems = slices.DeleteFunc(rarityItems, func(item rarity.Item) bool { return !matching[item.ID] })
+	}
+	return rarity.Compute(rarityItems, opts), nil
+}
+
+func (s *nftService) traitCombination(combination rarity.Combination) model.TraitCombinationDTO {
+	dto := model.TraitCombinationDTO{
+		Traits: make([]model.TraitPropertyInput, 0, len(combination.Traits)),
+		Count:  combination.Count,
+		Rarity: percent(combination.Frequency),
+	}
+	for _, trait := range combination.Traits {
+		dto.Traits = append(dto.Traits, model.TraitPropertyInput{Key: trait.Key, Value: trait.Value})
+	}
+	dto.Tier = s.rarityTiers.Tier(dto.Rarity)
+	return dto
+}
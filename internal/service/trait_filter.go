@@ -0,0 +1,64 @@
+package service
+
+import (
+	"fmt"
+
+	businesserrors "github.com/Megidy/rarible/internal/domain/errors"
+	"github.com/Megidy/rarible/internal/domain/filter"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+)
+
+// traitFilter parses a trait filter and normalises its keys and values the way the
+// collection's traits are, so they compare equal however they are spelled. It is nil for an empty filter.
+// Bucketed traits only keep their bucket label, so ordering operators on them are rejected.
+func (s *nftService) traitFilter(collectionID, query string) (filter.Expr, error) {
+	if query == "" {
+		return nil, nil
+	}
+	expr, err := filter.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", businesserrors.ErrInvalidRequest, err)
+	}
+
+	collectionID = normalizerCollectionID(collectionID)
+	var bucketErr error
+	expr = filter.MapComparisons(expr, func(c filter.Comparison) filter.Comparison {
+		if keys := s.normalizer.NormalizeText(collectionID, []rarity.Trait{{Key: c.Key}}); len(keys) > 0 {
+			c.Key = keys[0].Key
+		}
+		if c.Op.Ordering() && s.normalizer.Bucketed(collectionID, c.Key) && bucketErr == nil {
+			bucketErr = fmt.Errorf("%w: %s values are bucketed and cannot be compared with %s, compare with = or IN and a bucket label instead",
+				businesserrors.ErrInvalidRequest, c.Key, c.Op)
+		}
+		for i, value := range c.Values {
+			if traits := s.normalizer.Normalize(collectionID, []rarity.Trait{{Key: c.Key, Value: value}}); len(traits) > 0 {
+				c.Values[i] = traits[0].Value
+			}
+		}
+		return c
+	})
+	if bucketErr != nil {
+		return nil, bucketErr
+	}
+	return expr, nil
+}
+
+// traitValues indexes an item's traits for filtering
+func traitValues(item rarity.Item) filter.Traits {
+	traits := make(filter.Traits, len(item.Traits))
+	for _, trait := range item.Traits {
+		traits[trait.Key] = append(traits[trait.Key], trait.Value)
+	}
+	return traits
+}
+
+// matchingItems returns the ids of the items matching expr, every item when expr is nil
+func matchingItems(expr filter.Expr, items []rarity.Item) map[string]bool {
+	matching := make(map[string]bool, len(items))
+	for _, item := range items {
+		if expr == nil || expr.Match(traitValues(item)) {
+			matching[item.ID] = true
+		}
+	}
+	return matching
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+)
+
+// rankTraits sets the rank, percentile and tier of every trait within the request
+func rankTraits(traits []model.ExtendedTraitProperty, tiers model.RarityTiers) {
+	for i := range traits {
+		rarerCount, atLeastAsCommon := 0, 0
+		for _, other := range traits {
+			if other.Rarity < traits[i].Rarity {
+				rarerCount++
+			}
+			if other.Rarity >= traits[i].Rarity {
+				atLeastAsCommon++
+			}
+		}
+		traits[i].Rank = rarerCount + 1
+		traits[i].Percentile = math.Round(float64(atLeastAsCommon)/float64(len(traits))*100*100) / 100
+		traits[i].Tier = tiers.Tier(traits[i].Rarity)
+	}
+}
+
+// filterTraits drops traits outside the requested rarity range or tiers
+func filterTraits(traits []model.ExtendedTraitProperty, req model.TraitRarityRequestDTO) []model.ExtendedTraitProperty {
+	return slices.DeleteFunc(traits, func(trait model.ExtendedTraitProperty) bool {
+		if req.MinRarity != nil && float64(trait.Rarity) < *req.MinRarity {
+			return true
+		}
+		if req.MaxRarity != nil && float64(trait.Rarity) > *req.MaxRarity {
+			return true
+		}
+		return len(req.Tiers) > 0 && !slices.Contains(req.Tiers, trait.Tier)
+	})
+}
+
+// sortTraits orders traits by the requested key, keeping the request order for ties
+func sortTraits(traits []model.ExtendedTraitProperty, sortBy, order string) {
+	var compare func(a, b model.ExtendedTraitProperty) int
+	switch sortBy {
+	case model.TraitSortRarity:
+		compare = func(a, b model.ExtendedTraitProperty) int {
+			return cmp.Compare(a.Rarity, b.Rarity)
+		}
+	case model.TraitSortKey:
+		compare = func(a, b model.ExtendedTraitProperty) int {
+			return cmp.Or(cmp.Compare(a.Key, b.Key), cmp.Compare(a.Value, b.Value))
+		}
+	default:
+		compare = func(a, b model.ExtendedTraitProperty) int { return 0 }
+	}
+
+	if order == model.OrderDesc {
+		if sortBy == "" || sortBy == model.TraitSortRequest {
+			slices.Reverse(traits)
+			return
+		}
+		asc := compare
+		compare = func(a, b model.ExtendedTraitProperty) int { return asc(b, a) }
+	}
+	slices.SortStableFunc(traits, compare)
+}
+
+// unrevealedValues are the attribute keys and values, lowercased without separators,
+// that placeholder metadata uses before a collection is revealed
+var unrevealedValues = []string{"unrevealed", "notrevealed", "prereveal", "revealed:false"}
+
+// isUnrevealed reports whether meta is a pre-reveal placeholder
+func isUnrevealed(meta *model.ItemMetaDTO) bool {
+	normalize := strings.NewReplacer(" ", "", "-", "", "_", "")
+	for _, attribute := range meta.Attributes {
+		key := normalize.Replace(strings.ToLower(attribute.Key))
+		value := normalize.Replace(strings.ToLower(attribute.Value))
+		if slices.Contains(unrevealedValues, key) || slices.Contains(unrevealedValues, value) || slices.Contains(unrevealedValues, key+":"+value) {
+			return true
+		}
+	}
+	return false
+}
+
+// traitProperties turns meta's attributes into trait rarity properties, dropping attributes
+// without a key or value, which upstream rejects, and repeated ones
+func traitProperties(meta *model.ItemMetaDTO) []model.TraitPropertyInput {
+	if meta == nil {
+		return nil
+	}
+	var properties []model.TraitPropertyInput
+	for _, attribute := range meta.Attributes {
+		property := model.TraitPropertyInput{Key: attribute.Key, Value: attribute.Value}
+		if property.Key == "" || property.Value == "" || slices.Contains(properties, property) {
+			continue
+		}
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+// upstreamProperties drops ignored properties and ones spelling an earlier property differently.
+// Rarible matches values exactly, so the properties kept are sent with the caller's spelling.
+func (s *nftService) upstreamProperties(collectionID string, properties []model.TraitPropertyInput) []model.TraitPropertyInput {
+	if s.normalizer == nil {
+		return properties
+	}
+
+	collectionID = normalizerCollectionID(collectionID)
+	seen := make(map[rarity.Trait]bool, len(properties))
+	kept := make([]model.TraitPropertyInput, 0, len(properties))
+	for _, property := range properties {
+		trait, ok := s.normalizeText(collectionID, property.Key, property.Value)
+		if !ok || seen[trait] {
+			continue
+		}
+		seen[trait] = true
+		kept = append(kept, property)
+	}
+	return kept
+}
+
+// normalizeText applies every normalisation rule but bucketing to one trait of a collection
+// already passed through normalizerCollectionID. It reports false for ignored traits.
+func (s *nftService) normalizeText(collectionID, key, value string) (rarity.Trait, bool) {
+	traits := s.normalizer.NormalizeText(collectionID, []rarity.Trait{{Key: key, Value: value}})
+	if len(traits) == 0 {
+		return rarity.Trait{}, false
+	}
+	return traits[0], true
+}
+
+// normalizerCollectionID returns the normalised form of a collection id, which rules are keyed by
+func normalizerCollectionID(collectionID string) string {
+	id, err := model.ParseCollectionID(collectionID)
+	if err != nil {
+		return collectionID
+	}
+	return id.Normalized().String()
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/Megidy/rarible/internal/client"
+	"github.com/Megidy/rarible/internal/domain/model"
+	"github.com/Megidy/rarible/internal/domain/rarity"
+)
+
+// defaultTraitStatsCacheTTL is how long collection trait stats are reused
+const defaultTraitStatsCacheTTL = 10 * time.Minute
+
+// GetTraitStats describes how the traits of a collection's items, or of the ones matching the
+// request's filter, are distributed. The stats are cached per upstream environment, collection and filter.
+func (s *nftService) GetTraitStats(ctx context.Context, req model.TraitStatsRequestDTO) (*model.TraitStatsResponseDTO, error) {
+	expr, err := s.traitFilter(req.CollectionID, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	environment, _ := client.EnvironmentFromContext(ctx)
+	key := environment + "|" + normalizerCollectionID(req.CollectionID)
+	if expr != nil {
+		// the canonical form, so spellings of the same filter share an entry
+		key += "|" + expr.String()
+	}
+
+	stats, computedAt, ok := s.traitStats.get(key)
+	if !ok {
+		collection, err := s.computeCollection(ctx, req.CollectionID, expr, rarity.Options{})
+		if err != nil {
+			return nil, err
+		}
+		stats = collection.Stats()
+		computedAt = s.traitStats.put(key, stats)
+	}
+
+	return s.traitStatsResponse(req.CollectionID, stats, computedAt, req.LongTail), nil
+}
+
+// traitStatsResponse describes stats, labelling values at or below the longTail rarity, DefaultLongTailRarity when nil, as long tail
+func (s *nftService) traitStatsResponse(collectionID string, stats rarity.Stats, computedAt time.Time, longTail *float64) *model.TraitStatsResponseDTO {
+	longTailRarity := model.Rarity(model.DefaultLongTailRarity)
+	if longTail != nil {
+		longTailRarity = model.Rarity(*longTail)
+	}
+
+	resp := &model.TraitStatsResponseDTO{
+		CollectionID: collectionID,
+		Items:        stats.Items,
+		Keys:         len(stats.Keys),
+		ComputedAt:   computedAt,
+		TraitCounts:  make([]model.TraitCountBucketDTO, 0, len(stats.TraitCounts)),
+		TraitKeys:    make([]model.TraitKeyStatsDTO, 0, len(stats.Keys)),
+	}
+	for traits, items := range stats.TraitCounts {
+		resp.TraitCounts = append(resp.TraitCounts, model.TraitCountBucketDTO{Traits: traits, Items: items})
+	}
+	slices.SortFunc(resp.TraitCounts, func(a, b model.TraitCountBucketDTO) int {
+		return a.Traits - b.Traits
+	})
+	for _, keyStats := range stats.Keys {
+		resp.Values += len(keyStats.Values)
+		resp.TraitKeys = append(resp.TraitKeys, s.traitKeyStats(keyStats, stats.Items, longTailRarity))
+	}
+	return resp
+}
+
+func (s *nftService) traitKeyStats(keyStats rarity.KeyStats, total int, longTail model.Rarity) model.TraitKeyStatsDTO {
+	dto := model.TraitKeyStatsDTO{
+		Key:          keyStats.Key,
+		Values:       len(keyStats.Values),
+		Items:        keyStats.Items,
+		Missing:      keyStats.Missing,
+		Entropy:      math.Round(keyStats.Entropy*1e6) / 1e6,
+		LongTail:     []string{},
+		Histogram:    make([]model.TierBucketDTO, 0, len(model.RarityTierLabels)),
+		Distribution: make([]model.TraitValueStatsDTO, 0, len(keyStats.Values)),
+	}
+	if total > 0 {
+		dto.MissingRarity = percent(float64(keyStats.Missing) / float64(total))
+	}
+
+	// a uniform spread over every value, missing counted as one, has the most entropy
+	outcomes := len(keyStats.Values)
+	if keyStats.Missing > 0 {
+		outcomes++
+	}
+	if outcomes > 1 {
+		dto.NormalizedEntropy = math.Round(keyStats.Entropy/math.Log2(float64(outcomes))*1e6) / 1e6
+	}
+
+	buckets := make(map[string]*model.TierBucketDTO, len(model.RarityTierLabels))
+	for _, tier := range model.RarityTierLabels {
+		dto.Histogram = append(dto.Histogram, model.TierBucketDTO{Tier: tier})
+	}
+	for i := range dto.Histogram {
+		buckets[dto.Histogram[i].Tier] = &dto.Histogram[i]
+	}
+
+	for _, value := range keyStats.Values {
+		valueStats := model.TraitValueStatsDTO{
+			Value:  value.Value,
+			Count:  value.Count,
+			Rarity: percent(value.Frequency),
+		}
+		valueStats.Tier = s.rarityTiers.Tier(valueStats.Rarity)
+		dto.Distribution = append(dto.Distribution, valueStats)
+
+		buckets[valueStats.Tier].Values++
+		buckets[valueStats.Tier].Items += value.Count
+		if valueStats.Rarity <= longTail {
+			dto.LongTail = append(dto.LongTail, value.Value)
+		}
+	}
+	return dto
+}